@@ -1,20 +1,40 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
 	"strconv"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/OmidRasouli/weather-api/config"
 	_ "github.com/OmidRasouli/weather-api/docs"
-	"github.com/OmidRasouli/weather-api/infrastructure/database/cache"
 	postgres "github.com/OmidRasouli/weather-api/infrastructure/database/database"
 	authUseCase "github.com/OmidRasouli/weather-api/internal/application/auth"
 	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/application/ratelimit"
+	"github.com/OmidRasouli/weather-api/internal/application/scheduler"
 	"github.com/OmidRasouli/weather-api/internal/application/service"
 	migration "github.com/OmidRasouli/weather-api/internal/database/migrations"
 	authDomain "github.com/OmidRasouli/weather-api/internal/domain/services"
+	"github.com/OmidRasouli/weather-api/internal/infrastructure/cache"
+	"github.com/OmidRasouli/weather-api/internal/infrastructure/database/postgres/alert"
+	"github.com/OmidRasouli/weather-api/internal/infrastructure/database/postgres/apikey"
+	"github.com/OmidRasouli/weather-api/internal/infrastructure/database/postgres/forecast"
+	"github.com/OmidRasouli/weather-api/internal/infrastructure/database/postgres/tracking"
+	"github.com/OmidRasouli/weather-api/internal/infrastructure/database/postgres/user"
 	"github.com/OmidRasouli/weather-api/internal/infrastructure/database/postgres/weather"
+	"github.com/OmidRasouli/weather-api/internal/infrastructure/oidc"
 	"github.com/OmidRasouli/weather-api/internal/infrastructure/openweather"
+	"github.com/OmidRasouli/weather-api/internal/infrastructure/provider"
+	"github.com/OmidRasouli/weather-api/internal/infrastructure/signing"
+	"github.com/OmidRasouli/weather-api/internal/infrastructure/webhook"
 	"github.com/OmidRasouli/weather-api/internal/interfaces/http/controller"
+	"github.com/OmidRasouli/weather-api/internal/interfaces/http/middleware"
 	router "github.com/OmidRasouli/weather-api/internal/interfaces/http/routers"
 	"github.com/OmidRasouli/weather-api/pkg/logger"
 	"github.com/OmidRasouli/weather-api/pkg/validator"
@@ -54,30 +74,189 @@ func main() {
 	validator.Initialize()
 }
 
-func RunServer(cfg *config.Config, db interfaces.Database, rd interfaces.Cache) {
+func RunServer(cfg *config.Config, db interfaces.Database, rd *cache.Redis) {
 	weatherRepo := weather.NewWeatherPostgresRepository(db)
-	apiClient := openweather.NewClient(cfg.OpenWeather.APIKey)
+	providers, err := provider.DefaultRegistry().Build(cfg, cfg.Providers.Priority)
+	if err != nil {
+		logger.Fatalf("failed to configure weather providers: %v", err)
+	}
+	// OpenWeather calls are kept within the plan's budgets, counted in Redis across replicas,
+	// or by the governor in memory per instance while Redis fails; once a budget is used up
+	// the failover client moves on to the next provider
+	var quotaReporters []controller.QuotaReporter
+	for i, p := range providers {
+		if p.Name != openweather.ProviderName {
+			continue
+		}
+		governor := provider.NewQuotaGovernor(p.Name, provider.QuotaBudget{
+			PerMinute: cfg.OpenWeather.QuotaPerMinute,
+			PerDay:    cfg.OpenWeather.QuotaPerDay,
+		}, rd)
+		providers[i].Client = governor.Wrap(p.Client)
+		quotaReporters = append(quotaReporters, governor)
+	}
+	apiClient := provider.NewFailoverClient(providers...)
+	quotaController := controller.NewQuotaController(quotaReporters...)
 
-	// Pass Redis client to the weather service
-	weatherService := service.NewWeatherService(weatherRepo, apiClient, rd)
+	// Alert rules are evaluated whenever a fetched observation is stored
+	alertService := service.NewAlertService(
+		alert.NewRulePostgresRepository(db),
+		alert.NewDeliveryPostgresRepository(db),
+		webhook.NewSender(cfg.Alerts.WebhookTimeout),
+		cfg.Alerts.WebhookMaxAttempts,
+		cfg.Alerts.WebhookRetryBackoff,
+	)
+	alertController := controller.NewAlertController(alertService)
+
+	// Weather and forecast lookups are answered from memory when possible. Each instance drops
+	// the entries changed by the others, which announce them over Redis pub/sub.
+	var weatherCache interfaces.Cache = rd
+	var cacheStats controller.CacheStatsReporter
+	if cfg.Redis.LocalSize > 0 {
+		tiered := cache.NewTiered(rd, cfg.Redis.LocalSize, time.Duration(cfg.Redis.LocalTTL)*time.Second)
+		defer tiered.Close()
+		weatherCache, cacheStats = tiered, tiered
+	}
+	// While Redis is unreachable they are cached in this instance's memory, until it answers again
+	failover := cache.NewFailover(weatherCache, cache.NewMemory(cfg.Redis.LocalSize, time.Duration(cfg.Redis.TTL)*time.Second), cfg.Redis.CheckInterval)
+	defer failover.Close()
+	weatherEntries := cache.NewWeatherCache(failover)
+	cacheController := controller.NewCacheController(cacheStats, weatherEntries)
+
+	// Pass the cache to the weather service; cached observations past the soft TTL are
+	// served stale while they are refreshed, and expire from Redis at the hard TTL (REDIS_TTL)
+	weatherService := service.NewWeatherService(weatherRepo, apiClient, weatherEntries).
+		WithObserver(alertService).
+		WithCacheTTLs(time.Duration(cfg.Redis.SoftTTL)*time.Second, time.Duration(cfg.Redis.TTL)*time.Second)
+	// Replicas take a Redis lock before fetching a missing city, so concurrent misses make one upstream call
+	weatherService.WithLocker(failover)
 	weatherController := controller.NewWeatherController(weatherService)
-	authService := authDomain.NewAuthService()
-	authUC := authUseCase.NewUseCase(authService)
+
+	// Forecasts are cached under their own keys with a separate TTL
+	forecastRepo := forecast.NewForecastPostgresRepository(db)
+	forecastTTL := time.Duration(cfg.Redis.ForecastTTL) * time.Second
+	forecastService := service.NewForecastService(forecastRepo, apiClient, failover, forecastTTL)
+	forecastController := controller.NewForecastController(forecastService)
+
+	// Tracked locations are refreshed in the background by the scheduler
+	trackingRepo := tracking.NewTrackedLocationPostgresRepository(db)
+	trackingService := service.NewTrackingService(trackingRepo)
+	trackingController := controller.NewTrackingController(trackingService)
+
+	// Accounts live in Postgres; the first admin comes from ADMIN_USERNAME/ADMIN_PASSWORD
+	userRepo := user.NewUserPostgresRepository(db)
+	userService := service.NewUserService(userRepo)
+	created, err := userService.BootstrapAdmin(context.Background(), cfg.Auth.AdminUsername, cfg.Auth.AdminPassword)
+	if err != nil {
+		logger.Errorf("failed to bootstrap admin user: %v", err)
+	} else if created {
+		logger.Infof("Created initial admin user %q", cfg.Auth.AdminUsername)
+	}
+	userController := controller.NewUserController(userService)
+
+	// API keys let machine clients call the protected weather routes without logging in
+	apiKeyService := service.NewAPIKeyService(apikey.NewAPIKeyPostgresRepository(db))
+	apiKeyController := controller.NewAPIKeyController(apiKeyService)
+
+	// Tokens are signed with the configured PEM key (or JWT_SECRET); extra keys stay valid for verification
+	keySet, err := signing.LoadKeySet(cfg.Auth.JWTSecret, cfg.Auth.SigningKeyFile, cfg.Auth.VerificationKeyFiles)
+	if err != nil {
+		logger.Fatalf("failed to load JWT keys: %v", err)
+	}
+	if _, err := keySet.Active(); err != nil {
+		logger.Warnf("Neither JWT_SIGNING_KEY_FILE nor JWT_SECRET is set: logins will fail")
+	}
+	authService := authDomain.NewAuthService(keySet)
+	// Revoked token IDs are kept in Redis until the tokens would have expired. There is no memory
+	// fallback: while Redis is unreachable, token checks, logout and refresh fail closed with 503.
+	authUC := authUseCase.NewUseCase(authService, userRepo, rd, cfg.Auth.AccessTokenTTL, cfg.Auth.RefreshTokenTTL)
+	// Logins can be delegated to an OIDC issuer, whose tokens are then accepted as well
+	if cfg.OIDC.IssuerURL != "" {
+		provider, err := oidc.NewProvider(context.Background(), oidc.Config{
+			IssuerURL:     cfg.OIDC.IssuerURL,
+			ClientID:      cfg.OIDC.ClientID,
+			ClientSecret:  cfg.OIDC.ClientSecret,
+			RedirectURL:   cfg.OIDC.RedirectURL,
+			Scopes:        cfg.OIDC.Scopes,
+			Audiences:     cfg.OIDC.Audiences,
+			UsernameClaim: cfg.OIDC.UsernameClaim,
+			GroupsClaim:   cfg.OIDC.GroupsClaim,
+			GroupRoles:    cfg.OIDC.GroupRoles,
+			DefaultRole:   cfg.OIDC.DefaultRole,
+		})
+		if err != nil {
+			logger.Fatalf("failed to set up OIDC login: %v", err)
+		}
+		authUC.EnableOIDC(provider, cfg.Auth.PasswordLogin)
+		logger.Infof("OIDC login enabled for issuer %s", cfg.OIDC.IssuerURL)
+	} else if !cfg.Auth.PasswordLogin {
+		logger.Warnf("PASSWORD_LOGIN_ENABLED is false but OIDC_ISSUER_URL is not set: password login stays enabled")
+	}
 	authController := controller.NewAuthController(authUC)
-	r := router.Setup(weatherController, authController, authUC, db, rd)
+	// Requests are counted in Redis across instances; the limiter counts per instance in memory
+	// on its own while Redis fails, so it is given Redis directly rather than a failover cache
+	var rateLimiter *middleware.RateLimiter
+	if cfg.RateLimit.Enabled {
+		rateLimiter = middleware.NewRateLimiter(ratelimit.NewLimiter(rd, cfg.RateLimit.Window),
+			middleware.RateLimits{PerIP: cfg.RateLimit.ReadPerIP, PerUser: cfg.RateLimit.ReadPerUser, PerAPIKey: cfg.RateLimit.ReadPerAPIKey},
+			middleware.RateLimits{PerIP: cfg.RateLimit.WritePerIP, PerUser: cfg.RateLimit.WritePerUser, PerAPIKey: cfg.RateLimit.WritePerAPIKey},
+		)
+	}
+	r := router.Setup(weatherController, forecastController, trackingController, alertController, userController, apiKeyController, quotaController, cacheController, authController, authUC, userRepo, apiKeyService, rateLimiter, db, failover)
+	// Without trusted proxies the client IP used for rate limiting is the remote address
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.Fatalf("invalid TRUSTED_PROXIES: %v", err)
+	}
 	port := cfg.Server.Port
 	addr := ":" + strconv.Itoa(port)
-	logger.Infof("Server is starting on port %d", port)
 
 	// Add Swagger endpoint
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	if err := r.Run(addr); err != nil {
-		logger.Errorf("failed to start server: %v", err)
+	// Cancelled on SIGINT/SIGTERM or when the HTTP server stops on its own
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var background sync.WaitGroup
+	if cfg.Scheduler.Enabled {
+		refreshScheduler := scheduler.NewScheduler(trackingRepo, weatherService, scheduler.Options{
+			PollInterval: cfg.Scheduler.PollInterval,
+			Concurrency:  cfg.Scheduler.Concurrency,
+			Jitter:       cfg.Scheduler.Jitter,
+			FetchTimeout: cfg.Scheduler.FetchTimeout,
+		})
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			refreshScheduler.Run(ctx)
+		}()
 	}
+
+	srv := &http.Server{Addr: addr, Handler: r}
+	go func() {
+		logger.Infof("Server is starting on port %d", port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorf("failed to start server: %v", err)
+		}
+		stop()
+	}()
+
+	<-ctx.Done()
+	logger.Info("Shutting down...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Errorf("failed to shut down server: %v", err)
+	}
+
+	// Let in-flight scheduled refreshes and webhook deliveries finish before the database is closed
+	background.Wait()
+	alertService.Shutdown(shutdownCtx)
+	weatherService.Wait()
 }
 
-func RunDatabase(cfg *config.Config) (interfaces.Database, interfaces.Cache) {
+func RunDatabase(cfg *config.Config) (interfaces.Database, *cache.Redis) {
 	// Create a new database connection using the configuration values.
 	dbConfig := postgres.PostgresConfig{
 		Host:            cfg.Database.Host,
@@ -95,10 +274,13 @@ func RunDatabase(cfg *config.Config) (interfaces.Database, interfaces.Cache) {
 		logger.Errorf("failed to connect to postgres: %v", err)
 	}
 
-	// Initialize Redis client
-	redisClient, err := cache.NewRedisConnection(cfg.Redis)
-	if err != nil {
-		logger.Warnf("Failed to connect to Redis: %v. Continuing without caching.", err)
+	// Initialize Redis client; it connects on first use and reconnects after failures, and the
+	// caches built on it serve from memory while it is unreachable
+	redisClient := cache.NewRedisClient(cfg.Redis)
+	pingCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := redisClient.HealthCheck(pingCtx); err != nil {
+		logger.Warnf("Failed to connect to Redis: %v. Caching in memory until it is reachable.", err)
 	}
 
 	// Create a new migration instance for managing database migrations.
@@ -13,11 +13,20 @@ type Config struct {
 	Server      ServerConfig
 	Database    DatabaseConfig
 	OpenWeather OpenWeatherConfig
+	OpenMeteo   OpenMeteoConfig
+	Providers   ProvidersConfig
 	Redis       RedisConfig
+	Scheduler   SchedulerConfig
+	Alerts      AlertsConfig
+	Auth        AuthConfig
+	OIDC        OIDCConfig
+	RateLimit   RateLimitConfig
 }
 
 type ServerConfig struct {
 	Port int `envconfig:"SERVER_PORT"`
+	// TrustedProxies lists the proxies whose X-Forwarded-For header is used for the client IP
+	TrustedProxies []string `envconfig:"TRUSTED_PROXIES"`
 }
 
 type DatabaseConfig struct {
@@ -33,15 +42,102 @@ type DatabaseConfig struct {
 }
 
 type RedisConfig struct {
-	Host     string `envconfig:"REDIS_HOST"`
-	Port     int    `envconfig:"REDIS_PORT"`
-	Password string `envconfig:"REDIS_PASSWORD"`
-	DB       int    `envconfig:"REDIS_DB"`
-	TTL      int    `envconfig:"REDIS_TTL"`
+	Host        string `envconfig:"REDIS_HOST"`
+	Port        int    `envconfig:"REDIS_PORT"`
+	Password    string `envconfig:"REDIS_PASSWORD"`
+	DB          int    `envconfig:"REDIS_DB"`
+	TTL         int    `envconfig:"REDIS_TTL"`
+	SoftTTL     int    `envconfig:"REDIS_SOFT_TTL"`
+	ForecastTTL int    `envconfig:"REDIS_FORECAST_TTL"`
+	// Namespace prefixes every key the application stores, and bounds what a cache flush removes
+	Namespace string `envconfig:"REDIS_NAMESPACE" default:"weather-api:"`
+	// LocalSize is the number of entries each instance keeps in memory in front of Redis; 0 disables it
+	LocalSize int `envconfig:"REDIS_LOCAL_SIZE" default:"10000"`
+	// LocalTTL is the number of seconds an entry is kept in memory before Redis is read again
+	LocalTTL int `envconfig:"REDIS_LOCAL_TTL" default:"30"`
+	// CheckInterval is how often Redis is checked while it is up, and retried while it is down
+	CheckInterval time.Duration `envconfig:"REDIS_CHECK_INTERVAL" default:"5s"`
 }
 
+// OpenWeatherConfig holds the API credentials, the call budgets of the plan and the retry
+// and circuit breaker settings of the client; a zero budget is unlimited. The free plan
+// allows 60 calls per minute and 1,000,000 per month.
 type OpenWeatherConfig struct {
-	APIKey string `envconfig:"OPENWEATHER_API_KEY"`
+	APIKey           string        `envconfig:"OPENWEATHER_API_KEY"`
+	BaseURL          string        `envconfig:"OPENWEATHER_BASE_URL"`
+	QuotaPerMinute   int           `envconfig:"OPENWEATHER_QUOTA_PER_MINUTE" default:"60"`
+	QuotaPerDay      int           `envconfig:"OPENWEATHER_QUOTA_PER_DAY" default:"30000"`
+	MaxAttempts      int           `envconfig:"OPENWEATHER_MAX_ATTEMPTS" default:"3"`
+	RetryBackoff     time.Duration `envconfig:"OPENWEATHER_RETRY_BACKOFF" default:"200ms"`
+	MaxRetryBackoff  time.Duration `envconfig:"OPENWEATHER_MAX_RETRY_BACKOFF" default:"2s"`
+	BreakerThreshold int           `envconfig:"OPENWEATHER_BREAKER_THRESHOLD" default:"5"`
+	BreakerCooldown  time.Duration `envconfig:"OPENWEATHER_BREAKER_COOLDOWN" default:"30s"`
+}
+
+type OpenMeteoConfig struct {
+	BaseURL      string `envconfig:"OPENMETEO_BASE_URL"`
+	GeocodingURL string `envconfig:"OPENMETEO_GEOCODING_URL"`
+}
+
+type ProvidersConfig struct {
+	Priority []string `envconfig:"WEATHER_PROVIDERS" default:"openweather,openmeteo"`
+}
+
+type SchedulerConfig struct {
+	Enabled      bool          `envconfig:"SCHEDULER_ENABLED" default:"true"`
+	PollInterval time.Duration `envconfig:"SCHEDULER_POLL_INTERVAL" default:"30s"`
+	Concurrency  int           `envconfig:"SCHEDULER_CONCURRENCY" default:"4"`
+	Jitter       time.Duration `envconfig:"SCHEDULER_JITTER" default:"10s"`
+	FetchTimeout time.Duration `envconfig:"SCHEDULER_FETCH_TIMEOUT" default:"30s"`
+}
+
+type AlertsConfig struct {
+	WebhookTimeout      time.Duration `envconfig:"ALERT_WEBHOOK_TIMEOUT" default:"5s"`
+	WebhookMaxAttempts  int           `envconfig:"ALERT_WEBHOOK_MAX_ATTEMPTS" default:"3"`
+	WebhookRetryBackoff time.Duration `envconfig:"ALERT_WEBHOOK_RETRY_BACKOFF" default:"1s"`
+}
+
+// AuthConfig holds the credentials of the admin account created on first start,
+// when the users table is still empty, the lifetimes of issued tokens and the JWT keys.
+type AuthConfig struct {
+	AdminUsername   string        `envconfig:"ADMIN_USERNAME"`
+	AdminPassword   string        `envconfig:"ADMIN_PASSWORD"`
+	AccessTokenTTL  time.Duration `envconfig:"ACCESS_TOKEN_TTL" default:"15m"`
+	RefreshTokenTTL time.Duration `envconfig:"REFRESH_TOKEN_TTL" default:"168h"`
+	// JWTSecret signs HS256 tokens when no signing key file is set
+	JWTSecret            string   `envconfig:"JWT_SECRET"`
+	SigningKeyFile       string   `envconfig:"JWT_SIGNING_KEY_FILE"`
+	VerificationKeyFiles []string `envconfig:"JWT_VERIFICATION_KEY_FILES"`
+	// PasswordLogin can be turned off when every login goes through the OIDC issuer
+	PasswordLogin bool `envconfig:"PASSWORD_LOGIN_ENABLED" default:"true"`
+}
+
+// OIDCConfig delegates login to an OpenID Connect issuer. It is disabled while IssuerURL is empty.
+// GroupRoles maps IdP groups to roles, e.g. "weather-admins:admin,weather-editors:editor".
+type OIDCConfig struct {
+	IssuerURL     string            `envconfig:"OIDC_ISSUER_URL"`
+	ClientID      string            `envconfig:"OIDC_CLIENT_ID"`
+	ClientSecret  string            `envconfig:"OIDC_CLIENT_SECRET"`
+	RedirectURL   string            `envconfig:"OIDC_REDIRECT_URL"`
+	Scopes        []string          `envconfig:"OIDC_SCOPES" default:"openid,profile,email"`
+	Audiences     []string          `envconfig:"OIDC_AUDIENCES"`
+	UsernameClaim string            `envconfig:"OIDC_USERNAME_CLAIM" default:"preferred_username"`
+	GroupsClaim   string            `envconfig:"OIDC_GROUPS_CLAIM" default:"groups"`
+	GroupRoles    map[string]string `envconfig:"OIDC_GROUP_ROLES"`
+	DefaultRole   string            `envconfig:"OIDC_DEFAULT_ROLE"`
+}
+
+// RateLimitConfig holds the requests allowed per window for each client kind, separately
+// for read and write routes. A zero limit disables limiting for that client kind.
+type RateLimitConfig struct {
+	Enabled        bool          `envconfig:"RATE_LIMIT_ENABLED" default:"true"`
+	Window         time.Duration `envconfig:"RATE_LIMIT_WINDOW" default:"1m"`
+	ReadPerIP      int           `envconfig:"RATE_LIMIT_READ_PER_IP" default:"120"`
+	ReadPerUser    int           `envconfig:"RATE_LIMIT_READ_PER_USER" default:"300"`
+	ReadPerAPIKey  int           `envconfig:"RATE_LIMIT_READ_PER_API_KEY" default:"600"`
+	WritePerIP     int           `envconfig:"RATE_LIMIT_WRITE_PER_IP" default:"10"`
+	WritePerUser   int           `envconfig:"RATE_LIMIT_WRITE_PER_USER" default:"30"`
+	WritePerAPIKey int           `envconfig:"RATE_LIMIT_WRITE_PER_API_KEY" default:"60"`
 }
 
 func Load() (*Config, error) {
@@ -15,75 +15,65 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/health": {
+        "/.well-known/jwks.json": {
             "get": {
-                "description": "Returns 200 OK if the service is running",
+                "description": "Public keys for verifying access tokens, selected by the kid header. Retired keys stay listed until their tokens expire. Empty when tokens are signed with a shared HS256 secret.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "auth"
                 ],
-                "summary": "Basic health check",
+                "summary": "JSON Web Key Set",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.HealthResponse"
+                            "$ref": "#/definitions/services.JWKS"
                         }
                     }
                 }
             }
         },
-        "/health/live": {
+        "/admin/api-keys": {
             "get": {
-                "description": "Simple check for container orchestration",
+                "description": "Lists every API key, including revoked ones, with its last-used time. Admin only.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "admin"
                 ],
-                "summary": "Liveness check",
+                "summary": "List API keys",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/controller.HealthResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controller.APIKeyResponse"
+                            }
                         }
-                    }
-                }
-            }
-        },
-        "/health/ready": {
-            "get": {
-                "description": "Verifies connections to PostgreSQL and Redis",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "health"
-                ],
-                "summary": "Readiness check",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "403": {
+                        "description": "admin privileges required",
                         "schema": {
-                            "$ref": "#/definitions/controller.HealthResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "503": {
-                        "description": "Service Unavailable",
+                    "500": {
+                        "description": "Failed to list API keys",
                         "schema": {
-                            "$ref": "#/definitions/controller.HealthResponse"
+                            "$ref": "#/definitions/errors.AppError"
                         }
                     }
                 }
-            }
-        },
-        "/login": {
+            },
             "post": {
-                "description": "Authenticate user and return a JWT access token",
+                "description": "Issues a long-lived key for machine clients, sent as the X-API-Key header. Scopes: read, write. The key is only returned in this response; only its hash is stored. Admin only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -91,41 +81,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "User login",
+                "summary": "Create API key",
                 "parameters": [
                     {
-                        "description": "Login credentials",
+                        "description": "New API key",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controller.LoginRequestDTO"
+                            "$ref": "#/definitions/controller.CreateAPIKeyRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "token response",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/controller.APIKeyResponse"
                         }
                     },
                     "400": {
-                        "description": "validation error",
+                        "description": "Invalid request data",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/errors.AppError"
                         }
                     },
-                    "401": {
-                        "description": "invalid credentials",
+                    "403": {
+                        "description": "admin privileges required",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -134,83 +118,169 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "server error",
+                        "description": "Failed to create API key",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/api-keys/{id}": {
+            "delete": {
+                "description": "Permanently disables an API key. The record is kept for auditing. Admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Revoke API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.APIKeyResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "admin privileges required",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    },
+                    "404": {
+                        "description": "API key not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to revoke API key",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
                     }
                 }
             }
         },
-        "/weather": {
+        "/admin/cache/entries": {
             "get": {
-                "description": "Retrieves all weather records from the database",
+                "description": "Lists the weather cache entries whose key, after the \"weather:\" prefix, matches a glob pattern (e.g. \"city:London:*\", \"id:*\"), with the seconds until each expires (-1 when it does not). Keys are scanned in batches without blocking Redis: pass the returned cursor to get the next page, until it is 0. A page may be empty before the listing is done, and an entry may appear twice. Admin only.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "weather"
+                    "admin"
+                ],
+                "summary": "List weather cache entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "*",
+                        "description": "Glob pattern of the key after weather:",
+                        "name": "pattern",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Cursor returned by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Number of keys to scan (1-1000)",
+                        "name": "count",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all weather records",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/weather.Weather"
+                            "$ref": "#/definitions/controller.CacheEntriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "admin privileges required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     },
                     "500": {
-                        "description": "Failed to fetch weather records",
+                        "description": "Failed to list cache entries",
                         "schema": {
                             "$ref": "#/definitions/errors.AppError"
                         }
                     }
                 }
             },
-            "post": {
-                "description": "Fetches weather data from external API for a city and country, and stores it in the database",
-                "consumes": [
-                    "application/json"
-                ],
+            "delete": {
+                "description": "Deletes the weather cache entries whose key, after the \"weather:\" prefix, matches a glob pattern, from Redis and from the memory of every instance. The pattern is required; \"*\" purges the whole weather cache. Admin only.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "weather"
+                    "admin"
                 ],
-                "summary": "Fetch and store weather data",
+                "summary": "Purge weather cache entries",
                 "parameters": [
                     {
-                        "description": "City and country information",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/controller.FetchWeatherRequest"
-                        }
+                        "type": "string",
+                        "description": "Glob pattern of the key after weather:",
+                        "name": "pattern",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/weather.Weather"
+                            "$ref": "#/definitions/controller.CachePurgeResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request data",
+                        "description": "Invalid query parameters",
                         "schema": {
                             "$ref": "#/definitions/errors.AppError"
                         }
                     },
+                    "403": {
+                        "description": "admin privileges required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
-                        "description": "Failed to fetch weather data",
+                        "description": "Failed to purge cache entries",
                         "schema": {
                             "$ref": "#/definitions/errors.AppError"
                         }
@@ -218,34 +288,69 @@ const docTemplate = `{
                 }
             }
         },
-        "/weather/latest/{city}": {
+        "/admin/cache/stats": {
             "get": {
-                "description": "Retrieves the latest weather record for a specific city",
+                "description": "Lookups answered (hits) and missed by each tier of this instance's weather cache since it started: the in-memory tier, then Redis for the lookups the memory tier missed. Empty when the memory tier is disabled. Admin only.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "weather"
+                    "admin"
                 ],
-                "summary": "Get latest weather by city",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "City name",
-                        "name": "city",
-                        "in": "path",
-                        "required": true
+                "summary": "Cache hit and miss counters",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/interfaces.CacheTierStats"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "admin privileges required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
+                }
+            }
+        },
+        "/admin/quota": {
+            "get": {
+                "description": "Calls made to each rate-capped weather provider in the current minute and UTC day, against the configured budgets. A limit of 0 is unlimited. Admin only.",
+                "produces": [
+                    "application/json"
                 ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Provider quota usage",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/weather.Weather"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/interfaces.QuotaUsage"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Weather data not found for the city",
+                    "403": {
+                        "description": "admin privileges required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to read quota usage",
                         "schema": {
                             "$ref": "#/definitions/errors.AppError"
                         }
@@ -253,42 +358,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/weather/{id}": {
+        "/admin/users": {
             "get": {
-                "description": "Retrieves a specific weather record by its ID",
+                "description": "Lists every account. Admin only.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "weather"
-                ],
-                "summary": "Get weather by ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Weather ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "List users",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/weather.Weather"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controller.UserResponse"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Weather data not found",
+                    "403": {
+                        "description": "admin privileges required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list users",
                         "schema": {
                             "$ref": "#/definitions/errors.AppError"
                         }
                     }
                 }
             },
-            "put": {
-                "description": "Updates an existing weather record",
+            "post": {
+                "description": "Creates an account that can log in. Passwords must be 8-72 bytes and are stored as bcrypt hashes. Admin only.",
                 "consumes": [
                     "application/json"
                 ],
@@ -296,32 +404,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "weather"
+                    "admin"
                 ],
-                "summary": "Update weather record",
+                "summary": "Create user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Weather ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Weather information to update",
+                        "description": "New account",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/controller.UpdateWeatherRequest"
+                            "$ref": "#/definitions/controller.CreateUserRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/weather.Weather"
+                            "$ref": "#/definitions/controller.UserResponse"
                         }
                     },
                     "400": {
@@ -330,33 +431,44 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.AppError"
                         }
                     },
-                    "404": {
-                        "description": "Weather data not found",
+                    "403": {
+                        "description": "admin privileges required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Username already taken",
                         "schema": {
                             "$ref": "#/definitions/errors.AppError"
                         }
                     },
                     "500": {
-                        "description": "Failed to update weather data",
+                        "description": "Failed to create user",
                         "schema": {
                             "$ref": "#/definitions/errors.AppError"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes a weather record by its ID",
+            }
+        },
+        "/admin/users/{id}/disable": {
+            "post": {
+                "description": "Prevents an account from logging in. Admin only.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "weather"
+                    "admin"
                 ],
-                "summary": "Delete weather record",
+                "summary": "Disable user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Weather ID",
+                        "description": "User ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -364,7 +476,13 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Weather record deleted",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.UserResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "admin privileges required",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -373,117 +491,2589 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Weather data not found",
+                        "description": "User not found",
                         "schema": {
                             "$ref": "#/definitions/errors.AppError"
                         }
                     },
                     "500": {
-                        "description": "Failed to delete weather record",
+                        "description": "Failed to disable user",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/enable": {
+            "post": {
+                "description": "Re-enables a disabled account. Admin only.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Enable user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.UserResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "admin privileges required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to enable user",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/reset-password": {
+            "post": {
+                "description": "Sets a new password. When the body is empty or has no password, a random one is generated and returned once. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reset user password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New password",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ResetPasswordResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "admin privileges required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to reset password",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/role": {
+            "put": {
+                "description": "Sets the role of an account: viewer (read), editor (read, fetch and edit) or admin (everything including deletes and account management). Tokens issued earlier keep their old role until they expire. Admin only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Change user role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.SetRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "403": {
+                        "description": "admin privileges required",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to change role",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/alerts": {
+            "get": {
+                "description": "Lists every alert rule",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "List alert rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controller.AlertRuleResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list alert rules",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a rule that posts an HMAC-signed webhook whenever a newly stored observation satisfies field operator threshold. Fields: temperature, feels_like, temp_min, temp_max, humidity, pressure, visibility, clouds, wind_speed, wind_deg, wind_gust, rain, snow (metric units). The signing secret is generated when omitted and only returned in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Create alert rule",
+                "parameters": [
+                    {
+                        "description": "Alert rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.AlertRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/controller.AlertRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create alert rule",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/alerts/{id}": {
+            "get": {
+                "description": "Retrieves an alert rule by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Get alert rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Alert rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.AlertRuleResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Alert rule not found",
                         "schema": {
                             "$ref": "#/definitions/errors.AppError"
                         }
                     }
                 }
+            },
+            "put": {
+                "description": "Replaces the condition, webhook and enabled flag of a rule. The secret is rotated only when a new one is given.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Update alert rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Alert rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Alert rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.AlertRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.AlertRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Alert rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update alert rule",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes an alert rule together with its delivery log",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Delete alert rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Alert rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Alert rule deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Alert rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete alert rule",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/alerts/{id}/deliveries": {
+            "get": {
+                "description": "Returns the most recent webhook deliveries of a rule, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "List alert deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Alert rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Maximum number of deliveries (1-500)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controller.AlertDeliveryResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Alert rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list alert deliveries",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/callback": {
+            "get": {
+                "description": "Redirect target of the issuer. Exchanges the authorization code, creates the account on first login with the role mapped from the IdP groups, and returns our own token pair.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Complete OIDC login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Login state",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "token response",
+                        "schema": {
+                            "$ref": "#/definitions/controller.TokenResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid or expired login state",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "login failed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "no role is mapped to your groups",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "OIDC login is not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "username is already used by another account",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/login": {
+            "get": {
+                "description": "Redirects to the configured OpenID Connect issuer for an authorization-code login with PKCE. The login state is kept in a short-lived cookie until the callback.",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Start OIDC login",
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    },
+                    "404": {
+                        "description": "OIDC login is not configured",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/forecast/{city}": {
+            "get": {
+                "description": "Returns the 5-day/3-hour forecast for a city, served from cache when available",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "forecast"
+                ],
+                "summary": "Get forecast by city",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "City name",
+                        "name": "city",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Country code",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "metric",
+                            "imperial",
+                            "standard"
+                        ],
+                        "type": "string",
+                        "description": "Measurement system (defaults to the Accept-Units header, then metric)",
+                        "name": "units",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "metric",
+                            "imperial",
+                            "standard"
+                        ],
+                        "type": "string",
+                        "description": "Default measurement system for this client",
+                        "name": "Accept-Units",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/forecast.Forecast"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "City not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "429": {
+                        "description": "Weather provider quota or rate limit exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "502": {
+                        "description": "Failed to fetch forecast",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "503": {
+                        "description": "Weather provider unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Returns 200 OK if the service is running",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Basic health check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/live": {
+            "get": {
+                "description": "Simple check for container orchestration",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health/ready": {
+            "get": {
+                "description": "Verifies connections to PostgreSQL and Redis. While Redis is down and the cache is served from memory, the instance stays ready with status DEGRADED.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.HealthResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/controller.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Authenticate user and return a short-lived JWT access token and a refresh token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "User login",
+                "parameters": [
+                    {
+                        "description": "Login credentials",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.LoginRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "token response",
+                        "schema": {
+                            "$ref": "#/definitions/controller.TokenResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "invalid credentials",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "password login is disabled",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/logout": {
+            "post": {
+                "description": "Revokes the access token used to call this endpoint and, when given, the refresh token of the same user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Logout",
+                "parameters": [
+                    {
+                        "description": "Refresh token to revoke",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/controller.RefreshRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Logged out",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "invalid refresh token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "invalid or expired token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "token revocation unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/token/refresh": {
+            "post": {
+                "description": "Exchanges a refresh token for a new access and refresh token pair. Each refresh token can be used once; presenting it again is rejected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Refresh tokens",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.RefreshRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "token response",
+                        "schema": {
+                            "$ref": "#/definitions/controller.TokenResponseDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "validation error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "invalid, expired or revoked refresh token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "token revocation unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tracked-locations": {
+            "get": {
+                "description": "Lists every tracked location with its schedule and the outcome of its last refresh",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking"
+                ],
+                "summary": "List tracked locations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controller.TrackedLocationResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to list tracked locations",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Starts refreshing the weather of a city in the background at the given interval (default 30m, between 1m and 168h)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking"
+                ],
+                "summary": "Track a location",
+                "parameters": [
+                    {
+                        "description": "Location and schedule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.CreateTrackedLocationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/controller.TrackedLocationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create tracked location",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/tracked-locations/{id}": {
+            "get": {
+                "description": "Retrieves a tracked location by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking"
+                ],
+                "summary": "Get tracked location",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tracked location ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.TrackedLocationResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tracked location not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Changes the refresh interval or pauses/resumes a tracked location",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking"
+                ],
+                "summary": "Update tracked location",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tracked location ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Schedule changes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.UpdateTrackedLocationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.TrackedLocationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Tracked location not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update tracked location",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a tracked location; stored weather records are kept",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking"
+                ],
+                "summary": "Stop tracking a location",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tracked location ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tracked location deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Tracked location not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete tracked location",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/weather": {
+            "get": {
+                "description": "Retrieves a page of weather records, optionally filtered by city, country and fetch time",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "List weather records",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by city",
+                        "name": "city",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by country code",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only records fetched at or after this time (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only records fetched at or before this time (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "city",
+                            "country",
+                            "latitude",
+                            "longitude",
+                            "temperature",
+                            "feels_like",
+                            "temp_min",
+                            "temp_max",
+                            "description",
+                            "condition_id",
+                            "icon",
+                            "humidity",
+                            "pressure",
+                            "visibility",
+                            "clouds",
+                            "wind_speed",
+                            "wind_deg",
+                            "wind_gust",
+                            "rain",
+                            "snow",
+                            "sunrise",
+                            "sunset",
+                            "fetched_at",
+                            "provider",
+                            "created_at",
+                            "updated_at"
+                        ],
+                        "type": "string",
+                        "default": "fetched_at",
+                        "description": "Sort field",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Sort order",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of records to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "metric",
+                            "imperial",
+                            "standard"
+                        ],
+                        "type": "string",
+                        "description": "Measurement system (defaults to the Accept-Units header, then metric)",
+                        "name": "units",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "metric",
+                            "imperial",
+                            "standard"
+                        ],
+                        "type": "string",
+                        "description": "Default measurement system for this client",
+                        "name": "Accept-Units",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.WeatherListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch weather records",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Fetches weather data from external API for a city and country, or for lat/lon coordinates, and stores it in the database.\nCached observations are returned with their age in an Age header. Past the soft TTL, or while the API is unavailable, the observation is returned with a Warning header.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Fetch and store weather data",
+                "parameters": [
+                    {
+                        "description": "City and country, or lat and lon",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.FetchWeatherRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/weather.Weather"
+                        },
+                        "headers": {
+                            "Age": {
+                                "type": "integer",
+                                "description": "Seconds since the observation was fetched, when it was not fetched for this request"
+                            },
+                            "Warning": {
+                                "type": "string",
+                                "description": "110 - \\\"Response is Stale\\\" when the observation is past its freshness"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "City not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "429": {
+                        "description": "Weather provider quota or rate limit exhausted",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch weather data",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "502": {
+                        "description": "Weather provider rejected the API credentials",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "503": {
+                        "description": "Weather provider unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/weather/history/{city}": {
+            "get": {
+                "description": "Returns per-bucket min/max/avg temperature, humidity and wind speed for a city over a time range",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Get weather history for a city",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "City name",
+                        "name": "city",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Country code",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Range start (RFC3339), defaults to 24h before to",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Range end (RFC3339), defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "1h",
+                        "description": "Bucket width, e.g. 15m, 1h, 1d",
+                        "name": "bucket",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "metric",
+                            "imperial",
+                            "standard"
+                        ],
+                        "type": "string",
+                        "description": "Measurement system (defaults to the Accept-Units header, then metric)",
+                        "name": "units",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "metric",
+                            "imperial",
+                            "standard"
+                        ],
+                        "type": "string",
+                        "description": "Default measurement system for this client",
+                        "name": "Accept-Units",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.WeatherHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch weather history",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/weather/latest/{city}": {
+            "get": {
+                "description": "Retrieves the latest weather record for a specific city",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Get latest weather by city",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "City name",
+                        "name": "city",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "metric",
+                            "imperial",
+                            "standard"
+                        ],
+                        "type": "string",
+                        "description": "Measurement system (defaults to the Accept-Units header, then metric)",
+                        "name": "units",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "metric",
+                            "imperial",
+                            "standard"
+                        ],
+                        "type": "string",
+                        "description": "Default measurement system for this client",
+                        "name": "Accept-Units",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/weather.Weather"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid units",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Weather data not found for the city",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/weather/nearby": {
+            "get": {
+                "description": "Returns stored observations within a radius of a latitude/longitude, nearest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Find weather near a point",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Latitude",
+                        "name": "lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude",
+                        "name": "lon",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "default": 25,
+                        "description": "Search radius in km (max 500)",
+                        "name": "radius_km",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Maximum number of results (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "metric",
+                            "imperial",
+                            "standard"
+                        ],
+                        "type": "string",
+                        "description": "Measurement system (defaults to the Accept-Units header, then metric)",
+                        "name": "units",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "metric",
+                            "imperial",
+                            "standard"
+                        ],
+                        "type": "string",
+                        "description": "Default measurement system for this client",
+                        "name": "Accept-Units",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/weather.NearbyResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query parameters",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to find nearby weather",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        },
+        "/weather/{id}": {
+            "get": {
+                "description": "Retrieves a specific weather record by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Get weather by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Weather ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "metric",
+                            "imperial",
+                            "standard"
+                        ],
+                        "type": "string",
+                        "description": "Measurement system (defaults to the Accept-Units header, then metric)",
+                        "name": "units",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "metric",
+                            "imperial",
+                            "standard"
+                        ],
+                        "type": "string",
+                        "description": "Default measurement system for this client",
+                        "name": "Accept-Units",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/weather.Weather"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid units",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Weather data not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates an existing weather record",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Update weather record",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Weather ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Weather information to update",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.UpdateWeatherRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/weather.Weather"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request data",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "404": {
+                        "description": "Weather data not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update weather data",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a weather record by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Delete weather record",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Weather ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Weather record deleted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Weather data not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete weather record",
+                        "schema": {
+                            "$ref": "#/definitions/errors.AppError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controller.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "controller.AlertDeliveryResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "statusCode": {
+                    "type": "integer"
+                },
+                "succeeded": {
+                    "type": "boolean"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "weatherId": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.AlertRuleRequest": {
+            "type": "object",
+            "required": [
+                "field",
+                "operator",
+                "threshold",
+                "webhookUrl"
+            ],
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string",
+                    "maxLength": 3,
+                    "minLength": 2
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "field": {
+                    "type": "string",
+                    "example": "temperature"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "operator": {
+                    "type": "string",
+                    "example": "\u003e"
+                },
+                "secret": {
+                    "type": "string",
+                    "minLength": 16
+                },
+                "threshold": {
+                    "type": "number",
+                    "example": 35
+                },
+                "webhookUrl": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/weather"
+                }
+            }
+        },
+        "controller.AlertRuleResponse": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "operator": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "number"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "webhookUrl": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.CacheEntriesResponse": {
+            "type": "object",
+            "properties": {
+                "cursor": {
+                    "description": "Cursor continues the listing; 0 when there are no more entries",
+                    "type": "integer"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/interfaces.CacheEntry"
+                    }
+                }
+            }
+        },
+        "controller.CachePurgeResponse": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "integer"
+                }
+            }
+        },
+        "controller.CreateAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "nightly-ingest"
+                },
+                "scopes": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "read",
+                        "write"
+                    ]
+                }
+            }
+        },
+        "controller.CreateTrackedLocationRequest": {
+            "type": "object",
+            "required": [
+                "city",
+                "country"
+            ],
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "country": {
+                    "type": "string",
+                    "maxLength": 3,
+                    "minLength": 2
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "refreshInterval": {
+                    "type": "string",
+                    "example": "15m"
+                }
+            }
+        },
+        "controller.CreateUserRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "correct-horse-battery"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "viewer",
+                        "editor",
+                        "admin"
+                    ],
+                    "example": "editor"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "alice"
+                }
+            }
+        },
+        "controller.FetchWeatherRequest": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "country": {
+                    "type": "string",
+                    "maxLength": 3,
+                    "minLength": 2
+                },
+                "lat": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90,
+                    "example": 35.6892
+                },
+                "lon": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180,
+                    "example": 51.389
+                }
+            }
+        },
+        "controller.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "components": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.LoginRequestDTO": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "secret"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "admin"
+                }
+            }
+        },
+        "controller.PageLinks": {
+            "type": "object",
+            "properties": {
+                "next": {
+                    "type": "string"
+                },
+                "prev": {
+                    "type": "string"
+                },
+                "self": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.RefreshRequestDTO": {
+            "type": "object",
+            "properties": {
+                "refreshToken": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.ResetPasswordRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.ResetPasswordResponse": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/controller.UserResponse"
+                }
+            }
+        },
+        "controller.SetRoleRequest": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "viewer",
+                        "editor",
+                        "admin"
+                    ],
+                    "example": "editor"
+                }
+            }
+        },
+        "controller.TokenResponseDTO": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string",
+                    "example": "2025-01-01T12:15:00Z"
+                },
+                "refreshExpiresAt": {
+                    "type": "string",
+                    "example": "2025-01-08T12:00:00Z"
+                },
+                "refreshToken": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "tokenType": {
+                    "type": "string",
+                    "example": "Bearer"
+                }
+            }
+        },
+        "controller.TrackedLocationResponse": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "lastRefreshedAt": {
+                    "type": "string"
+                },
+                "nextRunAt": {
+                    "type": "string"
+                },
+                "refreshInterval": {
+                    "type": "string",
+                    "example": "15m0s"
+                }
+            }
+        },
+        "controller.UpdateTrackedLocationRequest": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "refreshInterval": {
+                    "type": "string",
+                    "example": "1h"
+                }
+            }
+        },
+        "controller.UpdateWeatherRequest": {
+            "type": "object",
+            "required": [
+                "city",
+                "country"
+            ],
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "country": {
+                    "type": "string",
+                    "maxLength": 3,
+                    "minLength": 2
+                },
+                "description": {
+                    "type": "string"
+                },
+                "humidity": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "temperature": {
+                    "type": "number"
+                },
+                "windSpeed": {
+                    "type": "number",
+                    "minimum": 0
+                }
+            }
+        },
+        "controller.UserResponse": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "disabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "identityIssuer": {
+                    "type": "string"
+                },
+                "lastLoginAt": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.WeatherHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "string"
+                },
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/weather.HistoryBucket"
+                    }
+                },
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "controller.WeatherListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/weather.Weather"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "links": {
+                    "$ref": "#/definitions/controller.PageLinks"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "errors.AppError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "details": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "forecast.Entry": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "forecastAt": {
+                    "type": "string"
+                },
+                "humidity": {
+                    "type": "integer"
+                },
+                "temperature": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "windSpeed": {
+                    "type": "number",
+                    "format": "float64"
+                }
             }
-        }
-    },
-    "definitions": {
-        "controller.FetchWeatherRequest": {
+        },
+        "forecast.Forecast": {
             "type": "object",
-            "required": [
-                "city",
-                "country"
-            ],
             "properties": {
                 "city": {
-                    "type": "string",
-                    "minLength": 1
+                    "type": "string"
                 },
                 "country": {
-                    "type": "string",
-                    "maxLength": 3,
-                    "minLength": 2
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/forecast.Entry"
+                    }
+                },
+                "fetchedAt": {
+                    "type": "string"
                 }
             }
         },
-        "controller.HealthResponse": {
+        "interfaces.CacheEntry": {
             "type": "object",
             "properties": {
-                "components": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
-                },
-                "status": {
+                "key": {
                     "type": "string"
                 },
-                "version": {
+                "ttlSeconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "interfaces.CacheTierStats": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "integer"
+                },
+                "hits": {
+                    "type": "integer"
+                },
+                "misses": {
+                    "type": "integer"
+                },
+                "tier": {
                     "type": "string"
                 }
             }
         },
-        "controller.LoginRequestDTO": {
+        "interfaces.QuotaUsage": {
             "type": "object",
             "properties": {
-                "password": {
-                    "type": "string",
-                    "example": "secret"
+                "dayLimit": {
+                    "type": "integer"
                 },
-                "username": {
-                    "type": "string",
-                    "example": "admin"
+                "dayResetsAt": {
+                    "type": "string"
+                },
+                "dayUsed": {
+                    "type": "integer"
+                },
+                "minuteLimit": {
+                    "type": "integer"
+                },
+                "minuteResetsAt": {
+                    "type": "string"
+                },
+                "minuteUsed": {
+                    "type": "integer"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "shared": {
+                    "description": "Shared is false while the counters are kept in memory because the cache is unavailable",
+                    "type": "boolean"
                 }
             }
         },
-        "controller.UpdateWeatherRequest": {
+        "services.JWK": {
             "type": "object",
-            "required": [
-                "city",
-                "country"
-            ],
             "properties": {
-                "city": {
-                    "type": "string",
-                    "minLength": 1
+                "alg": {
+                    "type": "string"
                 },
-                "country": {
-                    "type": "string",
-                    "maxLength": 3,
-                    "minLength": 2
+                "crv": {
+                    "type": "string"
                 },
-                "description": {
+                "e": {
                     "type": "string"
                 },
-                "humidity": {
-                    "type": "integer",
-                    "maximum": 100,
-                    "minimum": 0
+                "kid": {
+                    "type": "string"
                 },
-                "temperature": {
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                }
+            }
+        },
+        "services.JWKS": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.JWK"
+                    }
+                }
+            }
+        },
+        "weather.HistoryBucket": {
+            "type": "object",
+            "properties": {
+                "avgHumidity": {
                     "type": "number"
                 },
-                "windSpeed": {
-                    "type": "number",
-                    "minimum": 0
+                "avgTemperature": {
+                    "type": "number"
+                },
+                "avgWindSpeed": {
+                    "type": "number"
+                },
+                "maxHumidity": {
+                    "type": "integer"
+                },
+                "maxTemperature": {
+                    "type": "number"
+                },
+                "maxWindSpeed": {
+                    "type": "number"
+                },
+                "minHumidity": {
+                    "type": "integer"
+                },
+                "minTemperature": {
+                    "type": "number"
+                },
+                "minWindSpeed": {
+                    "type": "number"
+                },
+                "samples": {
+                    "type": "integer"
+                },
+                "start": {
+                    "type": "string"
                 }
             }
         },
-        "errors.AppError": {
+        "weather.NearbyResult": {
             "type": "object",
             "properties": {
-                "code": {
+                "city": {
+                    "type": "string"
+                },
+                "clouds": {
+                    "description": "cloud cover in percent",
                     "type": "integer"
                 },
-                "details": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
+                "conditionID": {
+                    "description": "provider condition code, e.g. OpenWeather 800 for clear sky",
+                    "type": "integer"
                 },
-                "message": {
+                "country": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "distanceKm": {
+                    "type": "number"
+                },
+                "feelsLike": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "fetchedAt": {
+                    "type": "string"
+                },
+                "humidity": {
+                    "type": "integer"
+                },
+                "icon": {
+                    "description": "provider icon identifier, e.g. \"01d\"",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "longitude": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "pressure": {
+                    "description": "hPa",
+                    "type": "integer"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "rain": {
+                    "description": "precipitation volume for the last hour, mm",
+                    "type": "number",
+                    "format": "float64"
+                },
+                "snow": {
+                    "description": "snow volume for the last hour, mm",
+                    "type": "number",
+                    "format": "float64"
+                },
+                "sunrise": {
+                    "type": "string"
+                },
+                "sunset": {
+                    "type": "string"
+                },
+                "tempMax": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "tempMin": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "temperature": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "updatedAt": {
                     "type": "string"
+                },
+                "visibility": {
+                    "description": "meters",
+                    "type": "integer"
+                },
+                "windDeg": {
+                    "description": "meteorological degrees",
+                    "type": "integer"
+                },
+                "windGust": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "windSpeed": {
+                    "type": "number",
+                    "format": "float64"
                 }
             }
         },
@@ -493,6 +3083,14 @@ const docTemplate = `{
                 "city": {
                     "type": "string"
                 },
+                "clouds": {
+                    "description": "cloud cover in percent",
+                    "type": "integer"
+                },
+                "conditionID": {
+                    "description": "provider condition code, e.g. OpenWeather 800 for clear sky",
+                    "type": "integer"
+                },
                 "country": {
                     "type": "string"
                 },
@@ -502,15 +3100,62 @@ const docTemplate = `{
                 "description": {
                     "type": "string"
                 },
+                "feelsLike": {
+                    "type": "number",
+                    "format": "float64"
+                },
                 "fetchedAt": {
                     "type": "string"
                 },
                 "humidity": {
                     "type": "integer"
                 },
+                "icon": {
+                    "description": "provider icon identifier, e.g. \"01d\"",
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
+                "latitude": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "longitude": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "pressure": {
+                    "description": "hPa",
+                    "type": "integer"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "rain": {
+                    "description": "precipitation volume for the last hour, mm",
+                    "type": "number",
+                    "format": "float64"
+                },
+                "snow": {
+                    "description": "snow volume for the last hour, mm",
+                    "type": "number",
+                    "format": "float64"
+                },
+                "sunrise": {
+                    "type": "string"
+                },
+                "sunset": {
+                    "type": "string"
+                },
+                "tempMax": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "tempMin": {
+                    "type": "number",
+                    "format": "float64"
+                },
                 "temperature": {
                     "type": "number",
                     "format": "float64"
@@ -518,6 +3163,18 @@ const docTemplate = `{
                 "updatedAt": {
                     "type": "string"
                 },
+                "visibility": {
+                    "description": "meters",
+                    "type": "integer"
+                },
+                "windDeg": {
+                    "description": "meteorological degrees",
+                    "type": "integer"
+                },
+                "windGust": {
+                    "type": "number",
+                    "format": "float64"
+                },
                 "windSpeed": {
                     "type": "number",
                     "format": "float64"
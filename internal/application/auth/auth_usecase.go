@@ -1,20 +1,73 @@
 package auth
 
 import (
+	"context"
 	"errors"
 	"time"
 
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
 	"github.com/OmidRasouli/weather-api/internal/domain/services"
+	"github.com/OmidRasouli/weather-api/internal/domain/user"
+	"github.com/OmidRasouli/weather-api/pkg/logger"
 )
 
+const (
+	DefaultAccessTokenTTL  = 15 * time.Minute
+	DefaultRefreshTokenTTL = 7 * 24 * time.Hour
+)
+
+var (
+	// ErrInvalidCredentials is returned when the username is unknown, the account is disabled or the password is wrong.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrTokenIssue is returned when a token could not be signed.
+	ErrTokenIssue = errors.New("could not issue token")
+	// ErrInvalidToken is returned when a token is malformed, expired or of the wrong kind.
+	ErrInvalidToken = errors.New("invalid or expired token")
+	// ErrTokenRevoked is returned when a token was revoked by logout or has already been exchanged.
+	ErrTokenRevoked = errors.New("token has been revoked")
+	// ErrRevocationUnavailable is returned when the denylist cannot be read or written.
+	ErrRevocationUnavailable = errors.New("token revocation is unavailable")
+	// ErrPasswordLoginDisabled is returned by Login when logins are delegated to an OIDC issuer only.
+	ErrPasswordLoginDisabled = errors.New("password login is disabled")
+	// ErrNoRole is returned when an external identity is in no group mapped to a role.
+	ErrNoRole = errors.New("no role is mapped to the identity")
+)
+
+// dummyHash is compared against when the user does not exist, so unknown usernames take as long as wrong passwords
+const dummyHash = "$2a$10$/qcOR9xffOg/Y5Ziy6GLMeRGOly2Xf9qBE6Q/TSSm38pC5vikJgky"
+
 type UseCase struct {
 	authService *services.AuthService
+	users       interfaces.UserRepository
+	denylist    *Denylist
+	accessTTL   time.Duration
+	refreshTTL  time.Duration
+	// idp is the OIDC issuer logins may be delegated to; nil when not configured
+	idp           interfaces.IdentityProvider
+	passwordLogin bool
 }
 
-func NewUseCase(authService *services.AuthService) *UseCase {
-	return &UseCase{
-		authService: authService,
+// NewUseCase creates the use case. Revoked token IDs are kept in cache; with a nil cache
+// revocation is disabled. Non-positive token lifetimes fall back to the defaults.
+func NewUseCase(authService *services.AuthService, users interfaces.UserRepository, cache interfaces.Cache, accessTTL time.Duration, refreshTTL time.Duration) *UseCase {
+	if accessTTL <= 0 {
+		accessTTL = DefaultAccessTokenTTL
+	}
+	if refreshTTL <= 0 {
+		refreshTTL = DefaultRefreshTokenTTL
 	}
+
+	uc := &UseCase{
+		authService:   authService,
+		users:         users,
+		accessTTL:     accessTTL,
+		refreshTTL:    refreshTTL,
+		passwordLogin: true,
+	}
+	if cache != nil {
+		uc.denylist = NewDenylist(cache)
+	}
+	return uc
 }
 
 type LoginRequest struct {
@@ -22,33 +75,155 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
 type LoginResponse struct {
-	Token     string `json:"token"`
-	TokenType string `json:"tokenType"`
-	ExpiresAt string `json:"expiresAt"`
+	Token            string `json:"token"`
+	TokenType        string `json:"tokenType"`
+	ExpiresAt        string `json:"expiresAt"`
+	RefreshToken     string `json:"refreshToken"`
+	RefreshExpiresAt string `json:"refreshExpiresAt"`
 }
 
-func (uc *UseCase) Login(req LoginRequest) (*LoginResponse, error) {
-	if !uc.authService.ValidateCredentials(req.Username, req.Password) {
-		return nil, errors.New("invalid credentials")
+func (uc *UseCase) Login(ctx context.Context, req LoginRequest) (*LoginResponse, error) {
+	if !uc.passwordLogin {
+		return nil, ErrPasswordLoginDisabled
 	}
 
-	token, exp, err := uc.authService.GenerateToken(req.Username, 24*time.Hour)
+	u, err := uc.users.FindByUsername(ctx, req.Username)
 	if err != nil {
-		return nil, errors.New("could not issue token")
+		services.ComparePassword(dummyHash, req.Password)
+		return nil, ErrInvalidCredentials
+	}
+	if !services.ComparePassword(u.PasswordHash, req.Password) || u.Disabled {
+		return nil, ErrInvalidCredentials
 	}
 
-	return &LoginResponse{
-		Token:     token,
-		TokenType: "Bearer",
-		ExpiresAt: exp.UTC().Format(time.RFC3339),
-	}, nil
+	response, err := uc.issueTokens(u)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	u.LastLoginAt = &now
+	if err := uc.users.Update(ctx, u); err != nil {
+		logger.Warnf("failed to record login of %s: %v", u.Username, err)
+	}
+	return response, nil
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token pair. The
+// presented refresh token is revoked, so each one can be used only once; the role
+// is re-read from the account, so role changes and disabling apply here.
+func (uc *UseCase) Refresh(ctx context.Context, refreshToken string) (*LoginResponse, error) {
+	claims, err := uc.parse(refreshToken, services.TokenUseRefresh)
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.denylist != nil {
+		first, err := uc.denylist.Revoke(ctx, claims.ID, claims.ExpiresAt.Time)
+		if err != nil {
+			logger.Errorf("failed to revoke refresh token %s: %v", claims.ID, err)
+			return nil, ErrRevocationUnavailable
+		}
+		if !first {
+			logger.Warnf("Refresh token %s of %s was presented again after being used", claims.ID, claims.Subject)
+			return nil, ErrTokenRevoked
+		}
+	}
+
+	u, err := uc.users.FindByUsername(ctx, claims.Subject)
+	if err != nil || u.Disabled {
+		return nil, ErrInvalidCredentials
+	}
+	return uc.issueTokens(u)
 }
 
-func (uc *UseCase) ValidateToken(tokenString string) (string, error) {
+// Logout revokes the access token it was called with and, when given, the refresh token of the same account.
+func (uc *UseCase) Logout(ctx context.Context, access *services.Claims, refreshToken string) error {
+	if uc.denylist == nil {
+		return ErrRevocationUnavailable
+	}
+
+	var refresh *services.Claims
+	if refreshToken != "" {
+		claims, err := uc.parse(refreshToken, services.TokenUseRefresh)
+		if err != nil || claims.Subject != access.Subject {
+			return ErrInvalidToken
+		}
+		refresh = claims
+	}
+
+	for _, c := range []*services.Claims{access, refresh} {
+		// Tokens of an external issuer without a jti cannot be revoked here
+		if c == nil || c.ID == "" {
+			continue
+		}
+		if _, err := uc.denylist.Revoke(ctx, c.ID, c.ExpiresAt.Time); err != nil {
+			logger.Errorf("failed to revoke token %s: %v", c.ID, err)
+			return ErrRevocationUnavailable
+		}
+	}
+	return nil
+}
+
+// ValidateToken verifies an access token, checks it against the denylist and returns its
+// claims. When an OIDC issuer is configured, tokens it issued are accepted as well.
+func (uc *UseCase) ValidateToken(ctx context.Context, tokenString string) (*services.Claims, error) {
+	claims, err := uc.parse(tokenString, services.TokenUseAccess)
+	if err != nil && uc.idp != nil {
+		claims, err = uc.externalClaims(ctx, tokenString)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.denylist != nil && claims.ID != "" {
+		revoked, err := uc.denylist.IsRevoked(ctx, claims.ID)
+		if err != nil {
+			logger.Errorf("failed to check token revocation: %v", err)
+			return nil, ErrRevocationUnavailable
+		}
+		if revoked {
+			return nil, ErrTokenRevoked
+		}
+	}
+	return claims, nil
+}
+
+// JWKS returns the public keys tokens can be verified with.
+func (uc *UseCase) JWKS() services.JWKS {
+	return uc.authService.JWKS()
+}
+
+// parse verifies the signature and expiry of a token and that it is meant for use
+func (uc *UseCase) parse(tokenString string, use services.TokenUse) (*services.Claims, error) {
 	claims, err := uc.authService.ValidateToken(tokenString)
+	if err != nil || claims.TokenUse != use || claims.ID == "" || claims.ExpiresAt == nil {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// issueTokens signs a new access and refresh token pair for u
+func (uc *UseCase) issueTokens(u *user.User) (*LoginResponse, error) {
+	token, exp, err := uc.authService.GenerateToken(u.Username, u.Role, uc.accessTTL)
+	if err != nil {
+		return nil, ErrTokenIssue
+	}
+	refresh, refreshExp, err := uc.authService.GenerateRefreshToken(u.Username, uc.refreshTTL)
 	if err != nil {
-		return "", err
+		return nil, ErrTokenIssue
 	}
-	return claims.Subject, nil
+
+	return &LoginResponse{
+		Token:            token,
+		TokenType:        "Bearer",
+		ExpiresAt:        exp.UTC().Format(time.RFC3339),
+		RefreshToken:     refresh,
+		RefreshExpiresAt: refreshExp.UTC().Format(time.RFC3339),
+	}, nil
 }
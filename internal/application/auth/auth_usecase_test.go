@@ -0,0 +1,183 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	authUseCase "github.com/OmidRasouli/weather-api/internal/application/auth"
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/application/service/mocks"
+	"github.com/OmidRasouli/weather-api/internal/domain/services"
+	"github.com/OmidRasouli/weather-api/internal/domain/user"
+	"github.com/OmidRasouli/weather-api/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMain(m *testing.M) {
+	testhelpers.InitTestLogger()
+	m.Run()
+}
+
+// fakeCache implements the counter operations the denylist uses; with down set they all fail
+type fakeCache struct {
+	interfaces.Cache
+	mu      sync.Mutex
+	counter map[string]int64
+	down    bool
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{counter: make(map[string]int64)}
+}
+
+func (f *fakeCache) Increment(ctx context.Context, key string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.down {
+		return 0, errors.New("connection refused")
+	}
+	f.counter[key]++
+	return f.counter[key], nil
+}
+
+func (f *fakeCache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return nil
+}
+
+func (f *fakeCache) Exists(ctx context.Context, key string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.down {
+		return false, errors.New("connection refused")
+	}
+	return f.counter[key] > 0, nil
+}
+
+// testAuthService signs with a fixed HS256 secret
+func testAuthService() *services.AuthService {
+	return services.NewAuthService(services.NewKeySet(services.NewHMACKey([]byte("test-secret"))))
+}
+
+func setupUseCase(t *testing.T) *authUseCase.UseCase {
+	hash, err := services.HashPassword("s3cret-pass")
+	assert.NoError(t, err)
+
+	users := new(mocks.MockUserRepository)
+	users.On("FindByUsername", mock.Anything, "alice").Return(&user.User{Username: "alice", PasswordHash: hash, Role: user.RoleEditor}, nil)
+	users.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	return authUseCase.NewUseCase(testAuthService(), users, newFakeCache(), time.Minute, time.Hour)
+}
+
+func TestLogin_IssuesTokenPair(t *testing.T) {
+	uc := setupUseCase(t)
+	ctx := context.TODO()
+
+	resp, err := uc.Login(ctx, authUseCase.LoginRequest{Username: "alice", Password: "s3cret-pass"})
+	assert.NoError(t, err)
+
+	claims, err := uc.ValidateToken(ctx, resp.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, user.RoleEditor, claims.Role)
+
+	// A refresh token is not accepted as an access token
+	_, err = uc.ValidateToken(ctx, resp.RefreshToken)
+	assert.ErrorIs(t, err, authUseCase.ErrInvalidToken)
+}
+
+func TestRefresh_RotatesAndRejectsReuse(t *testing.T) {
+	uc := setupUseCase(t)
+	ctx := context.TODO()
+
+	login, err := uc.Login(ctx, authUseCase.LoginRequest{Username: "alice", Password: "s3cret-pass"})
+	assert.NoError(t, err)
+
+	refreshed, err := uc.Refresh(ctx, login.RefreshToken)
+	assert.NoError(t, err)
+	assert.NotEqual(t, login.RefreshToken, refreshed.RefreshToken)
+
+	_, err = uc.Refresh(ctx, login.RefreshToken)
+	assert.ErrorIs(t, err, authUseCase.ErrTokenRevoked)
+
+	_, err = uc.Refresh(ctx, refreshed.RefreshToken)
+	assert.NoError(t, err)
+}
+
+func TestRefresh_DisabledUser(t *testing.T) {
+	users := new(mocks.MockUserRepository)
+	users.On("FindByUsername", mock.Anything, "alice").Return(&user.User{Username: "alice", Role: user.RoleEditor, Disabled: true}, nil)
+	uc := authUseCase.NewUseCase(testAuthService(), users, newFakeCache(), time.Minute, time.Hour)
+
+	refresh, _, err := testAuthService().GenerateRefreshToken("alice", time.Hour)
+	assert.NoError(t, err)
+
+	_, err = uc.Refresh(context.TODO(), refresh)
+	assert.ErrorIs(t, err, authUseCase.ErrInvalidCredentials)
+}
+
+func TestLogout_RevokesTokens(t *testing.T) {
+	uc := setupUseCase(t)
+	ctx := context.TODO()
+
+	login, err := uc.Login(ctx, authUseCase.LoginRequest{Username: "alice", Password: "s3cret-pass"})
+	assert.NoError(t, err)
+	claims, err := uc.ValidateToken(ctx, login.Token)
+	assert.NoError(t, err)
+
+	assert.NoError(t, uc.Logout(ctx, claims, login.RefreshToken))
+
+	_, err = uc.ValidateToken(ctx, login.Token)
+	assert.ErrorIs(t, err, authUseCase.ErrTokenRevoked)
+	_, err = uc.Refresh(ctx, login.RefreshToken)
+	assert.ErrorIs(t, err, authUseCase.ErrTokenRevoked)
+}
+
+func TestLogout_RejectsRefreshTokenOfAnotherUser(t *testing.T) {
+	uc := setupUseCase(t)
+	ctx := context.TODO()
+
+	login, err := uc.Login(ctx, authUseCase.LoginRequest{Username: "alice", Password: "s3cret-pass"})
+	assert.NoError(t, err)
+	claims, err := uc.ValidateToken(ctx, login.Token)
+	assert.NoError(t, err)
+	other, _, err := testAuthService().GenerateRefreshToken("bob", time.Hour)
+	assert.NoError(t, err)
+
+	err = uc.Logout(ctx, claims, other)
+
+	assert.ErrorIs(t, err, authUseCase.ErrInvalidToken)
+}
+
+func TestRevocation_FailsClosedWhileCacheIsDown(t *testing.T) {
+	hash, err := services.HashPassword("s3cret-pass")
+	assert.NoError(t, err)
+	users := new(mocks.MockUserRepository)
+	users.On("FindByUsername", mock.Anything, "alice").Return(&user.User{Username: "alice", PasswordHash: hash, Role: user.RoleEditor}, nil)
+	users.On("Update", mock.Anything, mock.Anything).Return(nil)
+	cache := newFakeCache()
+	uc := authUseCase.NewUseCase(testAuthService(), users, cache, time.Minute, time.Hour)
+	ctx := context.TODO()
+
+	login, err := uc.Login(ctx, authUseCase.LoginRequest{Username: "alice", Password: "s3cret-pass"})
+	assert.NoError(t, err)
+	claims, err := uc.ValidateToken(ctx, login.Token)
+	assert.NoError(t, err)
+	assert.NoError(t, uc.Logout(ctx, claims, login.RefreshToken))
+
+	// Without the denylist, neither revoked nor new tokens are accepted or revoked
+	cache.down = true
+	_, err = uc.ValidateToken(ctx, login.Token)
+	assert.ErrorIs(t, err, authUseCase.ErrRevocationUnavailable)
+	_, err = uc.Refresh(ctx, login.RefreshToken)
+	assert.ErrorIs(t, err, authUseCase.ErrRevocationUnavailable)
+	assert.ErrorIs(t, uc.Logout(ctx, claims, ""), authUseCase.ErrRevocationUnavailable)
+
+	// The revocations made before the outage still hold once it is over
+	cache.down = false
+	_, err = uc.ValidateToken(ctx, login.Token)
+	assert.ErrorIs(t, err, authUseCase.ErrTokenRevoked)
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+)
+
+// revokedKeyPrefix namespaces revoked token IDs in the cache
+const revokedKeyPrefix = "auth:revoked:"
+
+// Denylist records revoked token IDs (jti) in the cache until the token would have expired anyway.
+type Denylist struct {
+	cache interfaces.Cache
+}
+
+func NewDenylist(cache interfaces.Cache) *Denylist {
+	return &Denylist{cache: cache}
+}
+
+// Revoke adds jti to the denylist until expiresAt. It reports whether this call revoked
+// the token, and false if it was already revoked; the check is atomic, so only one of
+// several concurrent callers wins.
+func (d *Denylist) Revoke(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		// Already expired, signature validation rejects it without the denylist
+		return true, nil
+	}
+
+	key := revokedKeyPrefix + jti
+	n, err := d.cache.Increment(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if n > 1 {
+		return false, nil
+	}
+	if err := d.cache.Expire(ctx, key, ttl); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// IsRevoked reports whether jti is on the denylist.
+func (d *Denylist) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	return d.cache.Exists(ctx, revokedKeyPrefix+jti)
+}
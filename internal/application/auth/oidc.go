@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/domain/services"
+	"github.com/OmidRasouli/weather-api/internal/domain/user"
+	"github.com/OmidRasouli/weather-api/pkg/logger"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrOIDCDisabled is returned by the OIDC login steps when no issuer is configured.
+	ErrOIDCDisabled = errors.New("OIDC login is not configured")
+	// ErrIdentityConflict is returned when the username of a new external identity
+	// already belongs to another account.
+	ErrIdentityConflict = errors.New("username is already used by another account")
+)
+
+// OIDCLogin is the start of an authorization-code login. State, Nonce and Verifier
+// must be kept by the client (e.g. in a cookie) and handed back to CompleteOIDCLogin.
+type OIDCLogin struct {
+	AuthURL  string
+	State    string
+	Nonce    string
+	Verifier string
+}
+
+// EnableOIDC delegates logins to idp. With passwordLogin false, Login is rejected and
+// accounts can only sign in through the issuer; refresh tokens keep working either way.
+func (uc *UseCase) EnableOIDC(idp interfaces.IdentityProvider, passwordLogin bool) {
+	uc.idp = idp
+	uc.passwordLogin = passwordLogin
+}
+
+// BeginOIDCLogin generates the state, nonce and PKCE verifier of a new login and the
+// issuer URL to redirect the user to.
+func (uc *UseCase) BeginOIDCLogin() (*OIDCLogin, error) {
+	if uc.idp == nil {
+		return nil, ErrOIDCDisabled
+	}
+
+	login := &OIDCLogin{}
+	for _, v := range []*string{&login.State, &login.Nonce, &login.Verifier} {
+		token, err := randomToken()
+		if err != nil {
+			return nil, err
+		}
+		*v = token
+	}
+	login.AuthURL = uc.idp.AuthCodeURL(login.State, login.Nonce, login.Verifier)
+	return login, nil
+}
+
+// CompleteOIDCLogin redeems the authorization code, links the identity to an account,
+// creating it on first login, and issues our own token pair. The role is taken from
+// the identity's groups on every login, so changes in the IdP apply at the next login.
+func (uc *UseCase) CompleteOIDCLogin(ctx context.Context, code string, nonce string, verifier string) (*LoginResponse, error) {
+	if uc.idp == nil {
+		return nil, ErrOIDCDisabled
+	}
+
+	id, err := uc.idp.Exchange(ctx, code, nonce, verifier)
+	if err != nil {
+		logger.Warnf("OIDC login failed: %v", err)
+		return nil, ErrInvalidCredentials
+	}
+	if id.Role == "" {
+		logger.Warnf("OIDC login of %s rejected: groups %v are not mapped to a role", id.Username, id.Groups)
+		return nil, ErrNoRole
+	}
+
+	u, err := uc.linkIdentity(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if u.Disabled {
+		return nil, ErrInvalidCredentials
+	}
+	return uc.issueTokens(u)
+}
+
+// linkIdentity returns the account of id, updated with its current role and login time
+func (uc *UseCase) linkIdentity(ctx context.Context, id *user.Identity) (*user.User, error) {
+	now := time.Now().UTC()
+
+	u, err := uc.users.FindByIdentity(ctx, id.Issuer, id.Subject)
+	if err == nil {
+		u.Role = id.Role
+		u.LastLoginAt = &now
+		u.UpdatedAt = now
+		if err := uc.users.Update(ctx, u); err != nil {
+			return nil, err
+		}
+		return u, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err := user.ValidateUsername(id.Username); err != nil {
+		logger.Warnf("OIDC login of subject %s rejected: %v", id.Subject, err)
+		return nil, ErrInvalidCredentials
+	}
+
+	// The account has no password hash, so it cannot use password login until an admin resets it
+	u = &user.User{
+		ID:              uuid.New(),
+		Username:        id.Username,
+		Role:            id.Role,
+		IdentityIssuer:  id.Issuer,
+		IdentitySubject: id.Subject,
+		LastLoginAt:     &now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := uc.users.Create(ctx, u); err != nil {
+		if errors.Is(err, user.ErrUsernameTaken) {
+			return nil, ErrIdentityConflict
+		}
+		return nil, err
+	}
+	logger.Infof("Created account %s for OIDC subject %s", u.Username, id.Subject)
+	return u, nil
+}
+
+// externalClaims verifies a token of the OIDC issuer and converts it to access claims of
+// the account linked to its issuer and subject. Identities that never logged in here and
+// disabled accounts are rejected, and the username claim is never used to find an account.
+func (uc *UseCase) externalClaims(ctx context.Context, tokenString string) (*services.Claims, error) {
+	id, err := uc.idp.VerifyToken(ctx, tokenString)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if id.Role == "" {
+		return nil, ErrNoRole
+	}
+
+	u, err := uc.users.FindByIdentity(ctx, id.Issuer, id.Subject)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Errorf("failed to look up account of OIDC subject %s: %v", id.Subject, err)
+		}
+		return nil, ErrInvalidToken
+	}
+	if u.Disabled {
+		return nil, ErrInvalidToken
+	}
+	return services.IdentityClaims(id, u.Username), nil
+}
+
+// randomToken returns 32 random bytes encoded as base64url, usable as state, nonce or PKCE verifier
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,185 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	authUseCase "github.com/OmidRasouli/weather-api/internal/application/auth"
+	"github.com/OmidRasouli/weather-api/internal/application/service/mocks"
+	"github.com/OmidRasouli/weather-api/internal/domain/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+const testIssuer = "https://idp.example.com"
+
+// fakeIdP returns a fixed identity for the code "good-code" and the token "external-token"
+type fakeIdP struct {
+	identity *user.Identity
+}
+
+func (f *fakeIdP) AuthCodeURL(state string, nonce string, verifier string) string {
+	return testIssuer + "/authorize?state=" + state
+}
+
+func (f *fakeIdP) Exchange(ctx context.Context, code string, nonce string, verifier string) (*user.Identity, error) {
+	if code != "good-code" {
+		return nil, errors.New("invalid_grant")
+	}
+	return f.identity, nil
+}
+
+func (f *fakeIdP) VerifyToken(ctx context.Context, rawToken string) (*user.Identity, error) {
+	if rawToken != "external-token" {
+		return nil, errors.New("invalid token")
+	}
+	return f.identity, nil
+}
+
+func newIdentity(role user.Role) *user.Identity {
+	return &user.Identity{
+		Issuer:    testIssuer,
+		Subject:   "00u1a2b3c",
+		Username:  "carol",
+		Groups:    []string{"weather-editors"},
+		Role:      role,
+		TokenID:   "ext-1",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+}
+
+func setupOIDCUseCase(users *mocks.MockUserRepository, identity *user.Identity, passwordLogin bool) *authUseCase.UseCase {
+	uc := authUseCase.NewUseCase(testAuthService(), users, newFakeCache(), time.Minute, time.Hour)
+	uc.EnableOIDC(&fakeIdP{identity: identity}, passwordLogin)
+	return uc
+}
+
+func TestBeginOIDCLogin(t *testing.T) {
+	uc := setupOIDCUseCase(new(mocks.MockUserRepository), newIdentity(user.RoleEditor), true)
+
+	login, err := uc.BeginOIDCLogin()
+	assert.NoError(t, err)
+	assert.Equal(t, testIssuer+"/authorize?state="+login.State, login.AuthURL)
+	assert.NotEmpty(t, login.Nonce)
+	assert.NotEmpty(t, login.Verifier)
+	assert.NotEqual(t, login.State, login.Nonce)
+
+	// Without an issuer the flow is unavailable
+	plain := authUseCase.NewUseCase(testAuthService(), new(mocks.MockUserRepository), nil, 0, 0)
+	_, err = plain.BeginOIDCLogin()
+	assert.ErrorIs(t, err, authUseCase.ErrOIDCDisabled)
+}
+
+func TestCompleteOIDCLogin_CreatesAccount(t *testing.T) {
+	users := new(mocks.MockUserRepository)
+	users.On("FindByIdentity", mock.Anything, testIssuer, "00u1a2b3c").Return(nil, gorm.ErrRecordNotFound)
+	users.On("Create", mock.Anything, mock.MatchedBy(func(u *user.User) bool {
+		return u.Username == "carol" && u.Role == user.RoleEditor && u.IdentityIssuer == testIssuer &&
+			u.IdentitySubject == "00u1a2b3c" && u.PasswordHash == ""
+	})).Return(nil)
+	uc := setupOIDCUseCase(users, newIdentity(user.RoleEditor), true)
+	ctx := context.TODO()
+
+	resp, err := uc.CompleteOIDCLogin(ctx, "good-code", "nonce", "verifier")
+	assert.NoError(t, err)
+
+	// The issued tokens are our own
+	claims, err := uc.ValidateToken(ctx, resp.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, "carol", claims.Subject)
+	assert.Equal(t, user.RoleEditor, claims.Role)
+	users.AssertExpectations(t)
+
+	_, err = uc.CompleteOIDCLogin(ctx, "bad-code", "nonce", "verifier")
+	assert.ErrorIs(t, err, authUseCase.ErrInvalidCredentials)
+}
+
+func TestCompleteOIDCLogin_SyncsRoleOfLinkedAccount(t *testing.T) {
+	existing := &user.User{Username: "carol", Role: user.RoleViewer, IdentityIssuer: testIssuer, IdentitySubject: "00u1a2b3c"}
+	users := new(mocks.MockUserRepository)
+	users.On("FindByIdentity", mock.Anything, testIssuer, "00u1a2b3c").Return(existing, nil)
+	users.On("Update", mock.Anything, existing).Return(nil)
+	uc := setupOIDCUseCase(users, newIdentity(user.RoleAdmin), true)
+
+	_, err := uc.CompleteOIDCLogin(context.TODO(), "good-code", "nonce", "verifier")
+	assert.NoError(t, err)
+	assert.Equal(t, user.RoleAdmin, existing.Role)
+	assert.NotNil(t, existing.LastLoginAt)
+
+	// Disabled accounts cannot log in through the issuer either
+	existing.Disabled = true
+	_, err = uc.CompleteOIDCLogin(context.TODO(), "good-code", "nonce", "verifier")
+	assert.ErrorIs(t, err, authUseCase.ErrInvalidCredentials)
+}
+
+func TestCompleteOIDCLogin_Refusals(t *testing.T) {
+	// A username held by a local account is not taken over
+	users := new(mocks.MockUserRepository)
+	users.On("FindByIdentity", mock.Anything, testIssuer, "00u1a2b3c").Return(nil, gorm.ErrRecordNotFound)
+	users.On("Create", mock.Anything, mock.Anything).Return(user.ErrUsernameTaken)
+	uc := setupOIDCUseCase(users, newIdentity(user.RoleEditor), true)
+	_, err := uc.CompleteOIDCLogin(context.TODO(), "good-code", "nonce", "verifier")
+	assert.ErrorIs(t, err, authUseCase.ErrIdentityConflict)
+
+	// Identities in no mapped group get no account
+	uc = setupOIDCUseCase(new(mocks.MockUserRepository), newIdentity(""), true)
+	_, err = uc.CompleteOIDCLogin(context.TODO(), "good-code", "nonce", "verifier")
+	assert.ErrorIs(t, err, authUseCase.ErrNoRole)
+}
+
+func TestValidateToken_ExternalToken(t *testing.T) {
+	// The linked account was renamed, so the subject must not come from preferred_username
+	linked := &user.User{Username: "carol.smith", Role: user.RoleEditor, IdentityIssuer: testIssuer, IdentitySubject: "00u1a2b3c"}
+	users := new(mocks.MockUserRepository)
+	users.On("FindByIdentity", mock.Anything, testIssuer, "00u1a2b3c").Return(linked, nil)
+	uc := setupOIDCUseCase(users, newIdentity(user.RoleEditor), true)
+	ctx := context.TODO()
+
+	claims, err := uc.ValidateToken(ctx, "external-token")
+	assert.NoError(t, err)
+	assert.Equal(t, "carol.smith", claims.Subject)
+	assert.Equal(t, testIssuer, claims.Issuer)
+	assert.True(t, claims.HasScope(user.ScopeWeatherWrite))
+
+	// Logging out revokes the external token by its jti
+	assert.NoError(t, uc.Logout(ctx, claims, ""))
+	_, err = uc.ValidateToken(ctx, "external-token")
+	assert.ErrorIs(t, err, authUseCase.ErrTokenRevoked)
+
+	_, err = uc.ValidateToken(ctx, "unknown-token")
+	assert.ErrorIs(t, err, authUseCase.ErrInvalidToken)
+
+	unmapped := setupOIDCUseCase(new(mocks.MockUserRepository), newIdentity(""), true)
+	_, err = unmapped.ValidateToken(ctx, "external-token")
+	assert.ErrorIs(t, err, authUseCase.ErrNoRole)
+}
+
+func TestValidateToken_ExternalTokenRefusals(t *testing.T) {
+	ctx := context.TODO()
+
+	// Identities that never logged in have no account, even when a local one shares the username
+	users := new(mocks.MockUserRepository)
+	users.On("FindByIdentity", mock.Anything, testIssuer, "00u1a2b3c").Return(nil, gorm.ErrRecordNotFound)
+	uc := setupOIDCUseCase(users, newIdentity(user.RoleEditor), true)
+	_, err := uc.ValidateToken(ctx, "external-token")
+	assert.ErrorIs(t, err, authUseCase.ErrInvalidToken)
+	users.AssertNotCalled(t, "FindByUsername", mock.Anything, mock.Anything)
+
+	// Disabling the linked account stops its tokens too
+	disabled := &user.User{Username: "carol", Disabled: true, IdentityIssuer: testIssuer, IdentitySubject: "00u1a2b3c"}
+	users = new(mocks.MockUserRepository)
+	users.On("FindByIdentity", mock.Anything, testIssuer, "00u1a2b3c").Return(disabled, nil)
+	uc = setupOIDCUseCase(users, newIdentity(user.RoleEditor), true)
+	_, err = uc.ValidateToken(ctx, "external-token")
+	assert.ErrorIs(t, err, authUseCase.ErrInvalidToken)
+}
+
+func TestLogin_PasswordLoginDisabled(t *testing.T) {
+	uc := setupOIDCUseCase(new(mocks.MockUserRepository), newIdentity(user.RoleEditor), false)
+
+	_, err := uc.Login(context.TODO(), authUseCase.LoginRequest{Username: "alice", Password: "s3cret-pass"})
+	assert.ErrorIs(t, err, authUseCase.ErrPasswordLoginDisabled)
+}
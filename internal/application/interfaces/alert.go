@@ -0,0 +1,30 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/alert"
+)
+
+type AlertRuleRepository interface {
+	Create(ctx context.Context, r *alert.Rule) error
+	FindByID(ctx context.Context, id string) (*alert.Rule, error)
+	FindAll(ctx context.Context) ([]*alert.Rule, error)
+	// FindEnabledForLocation returns the enabled rules whose city and country match or are empty.
+	FindEnabledForLocation(ctx context.Context, city string, country string) ([]*alert.Rule, error)
+	Update(ctx context.Context, r *alert.Rule) error
+	Delete(ctx context.Context, id string) error
+}
+
+type AlertDeliveryRepository interface {
+	Save(ctx context.Context, d *alert.Delivery) error
+	// FindByRule returns the most recent deliveries of a rule, newest first.
+	FindByRule(ctx context.Context, ruleID string, limit int) ([]*alert.Delivery, error)
+}
+
+// WebhookSender posts a signed payload to a webhook URL and returns the response status code.
+type WebhookSender interface {
+	Send(ctx context.Context, url string, secret string, deliveryID string, payload []byte) (int, error)
+	// CheckURL returns an error when url resolves to an address webhooks may not be sent to.
+	CheckURL(ctx context.Context, url string) error
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/apikey"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, k *apikey.Key) error
+	FindByID(ctx context.Context, id string) (*apikey.Key, error)
+	FindByPrefix(ctx context.Context, prefix string) (*apikey.Key, error)
+	FindAll(ctx context.Context) ([]*apikey.Key, error)
+	// Revoke marks a key as revoked at the given time; revoking twice keeps the first time.
+	Revoke(ctx context.Context, id string, at time.Time) error
+	// TouchLastUsed records when a key was last used to authenticate.
+	TouchLastUsed(ctx context.Context, id string, at time.Time) error
+}
@@ -10,12 +10,35 @@ type Cache interface {
 	SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error
 	Set(ctx context.Context, key string, value interface{}) error
 	Get(ctx context.Context, key string, dest interface{}) error
+	// GetWithTTL is Get that also returns the remaining TTL of the key, or a negative
+	// duration when the key does not expire.
+	GetWithTTL(ctx context.Context, key string, dest interface{}) (time.Duration, error)
 	GetTTL(ctx context.Context, key string) (time.Duration, error)
 	Exists(ctx context.Context, key string) (bool, error)
 	Delete(ctx context.Context, keys ...string) error
+	// Flush removes every key of the cache's namespace
 	Flush(ctx context.Context) error
+	// Scan returns one batch of the keys matching the glob pattern and the cursor of the
+	// next batch; iteration starts at cursor 0 and is done when 0 is returned. count is a
+	// hint of the batch size, 0 for the default.
+	Scan(ctx context.Context, cursor uint64, pattern string, count int64) ([]string, uint64, error)
+	// GetKeys returns every key matching the glob pattern
 	GetKeys(ctx context.Context, pattern string) ([]string, error)
 	Expire(ctx context.Context, key string, ttl time.Duration) error
 	Increment(ctx context.Context, key string) (int64, error)
 	Close() error
 }
+
+// CacheTierStats counts the lookups answered and missed by one tier of a cache since startup.
+type CacheTierStats struct {
+	Tier    string `json:"tier"`
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Entries int    `json:"entries,omitempty"`
+}
+
+// CacheEntry is a cached key and the seconds until it expires, or -1 when it does not expire.
+type CacheEntry struct {
+	Key        string `json:"key"`
+	TTLSeconds int64  `json:"ttlSeconds"`
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/forecast"
+)
+
+type ForecastRepository interface {
+	// Save upserts every entry of the forecast, replacing older predictions for the same time slot.
+	Save(ctx context.Context, f *forecast.Forecast) error
+	// FindUpcoming returns the stored entries for a city and country that are at or after the
+	// given time. The country must match exactly; an empty one matches only forecasts stored without one.
+	FindUpcoming(ctx context.Context, city string, country string, from time.Time) (*forecast.Forecast, error)
+}
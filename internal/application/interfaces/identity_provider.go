@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/user"
+)
+
+// IdentityProvider delegates authentication to an external OpenID Connect issuer.
+type IdentityProvider interface {
+	// AuthCodeURL returns the issuer's authorization endpoint URL for an authorization-code
+	// login bound to state, nonce and the PKCE verifier.
+	AuthCodeURL(state string, nonce string, verifier string) string
+	// Exchange redeems an authorization code and returns the identity of its verified ID token.
+	Exchange(ctx context.Context, code string, nonce string, verifier string) (*user.Identity, error)
+	// VerifyToken validates an ID or JWT access token issued by the issuer.
+	VerifyToken(ctx context.Context, rawToken string) (*user.Identity, error)
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+)
+
+// Locker takes short-lived locks shared by every replica, so that work on a key is done by
+// one of them at a time.
+type Locker interface {
+	// TryLock takes the lock on key for at most ttl without waiting. ok is false while another
+	// holder has it; token identifies this holder to Unlock.
+	TryLock(ctx context.Context, key string, ttl time.Duration) (token string, ok bool, err error)
+	// Unlock releases the lock on key if it is still held with token.
+	Unlock(ctx context.Context, key string, token string) error
+}
@@ -0,0 +1,60 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrQuotaExhausted is matched by errors.Is when a provider call was refused because
+// its call budget is used up.
+var ErrQuotaExhausted = errors.New("upstream API quota exhausted")
+
+// QuotaExceededError is returned instead of calling a provider whose per-minute or
+// per-day budget is used up.
+type QuotaExceededError struct {
+	Provider string
+	// Period is the budget that ran out: "minute" or "day"
+	Period     string
+	RetryAfter time.Duration
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s %s quota exhausted, retry in %s", e.Provider, e.Period, e.RetryAfter.Round(time.Second))
+}
+
+func (e *QuotaExceededError) Unwrap() error {
+	return ErrQuotaExhausted
+}
+
+// QuotaUsage reports how much of a provider's call budgets is used. A zero limit is unlimited.
+type QuotaUsage struct {
+	Provider       string    `json:"provider"`
+	MinuteUsed     int64     `json:"minuteUsed"`
+	MinuteLimit    int       `json:"minuteLimit"`
+	MinuteResetsAt time.Time `json:"minuteResetsAt"`
+	DayUsed        int64     `json:"dayUsed"`
+	DayLimit       int       `json:"dayLimit"`
+	DayResetsAt    time.Time `json:"dayResetsAt"`
+	// Shared is false while the counters are kept in memory because the cache is unavailable
+	Shared bool `json:"shared"`
+}
+
+type retryAcquireKey struct{}
+
+// WithRetryAcquire returns a context carrying acquire, which clients that retry upstream
+// requests call before each retry, so that every request sent is charged to a call budget
+// and not only the first.
+func WithRetryAcquire(ctx context.Context, acquire func(context.Context) error) context.Context {
+	return context.WithValue(ctx, retryAcquireKey{}, acquire)
+}
+
+// AcquireRetry takes a retry from the budget carried by ctx, if any. It returns an error,
+// such as a *QuotaExceededError, when the retry must not be sent.
+func AcquireRetry(ctx context.Context) error {
+	if acquire, ok := ctx.Value(retryAcquireKey{}).(func(context.Context) error); ok {
+		return acquire(ctx)
+	}
+	return nil
+}
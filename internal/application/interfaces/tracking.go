@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/tracking"
+)
+
+type TrackedLocationRepository interface {
+	Create(ctx context.Context, l *tracking.Location) error
+	FindByID(ctx context.Context, id string) (*tracking.Location, error)
+	FindAll(ctx context.Context) ([]*tracking.Location, error)
+	Update(ctx context.Context, l *tracking.Location) error
+	Delete(ctx context.Context, id string) error
+	// ClaimDue atomically selects up to limit enabled locations whose next run is at or before now
+	// and pushes their next run one interval ahead, so concurrent callers never claim the same location.
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]*tracking.Location, error)
+	// MarkRefreshed records the outcome of a refresh; a nil refreshErr clears the last error.
+	MarkRefreshed(ctx context.Context, id string, at time.Time, refreshErr error) error
+}
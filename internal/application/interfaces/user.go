@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/user"
+)
+
+type UserRepository interface {
+	// Create stores a new user, returning user.ErrUsernameTaken if the username is in use.
+	Create(ctx context.Context, u *user.User) error
+	FindByID(ctx context.Context, id string) (*user.User, error)
+	// FindByUsername looks a user up case-insensitively.
+	FindByUsername(ctx context.Context, username string) (*user.User, error)
+	// FindByIdentity returns the account linked to an external OIDC identity.
+	FindByIdentity(ctx context.Context, issuer string, subject string) (*user.User, error)
+	FindAll(ctx context.Context) ([]*user.User, error)
+	Update(ctx context.Context, u *user.User) error
+	Count(ctx context.Context) (int64, error)
+}
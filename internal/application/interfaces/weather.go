@@ -10,7 +10,15 @@ type WeatherRepository interface {
 	Save(ctx context.Context, w *weather.Weather) error
 	FindByID(ctx context.Context, id string) (*weather.Weather, error)
 	FindAll(ctx context.Context) ([]*weather.Weather, error)
+	FindByQuery(ctx context.Context, q weather.Query) ([]*weather.Weather, int64, error)
 	FindLatestByCity(ctx context.Context, city string) (*weather.Weather, error)
+	FindHistory(ctx context.Context, q weather.HistoryQuery) ([]*weather.HistoryBucket, error)
+	FindNearby(ctx context.Context, q weather.NearbyQuery) ([]*weather.NearbyResult, error)
 	Update(ctx context.Context, w *weather.Weather) error
 	Delete(ctx context.Context, id string) error
 }
+
+// WeatherObserver is notified after a newly fetched observation has been persisted.
+type WeatherObserver interface {
+	OnWeatherStored(ctx context.Context, w *weather.Weather)
+}
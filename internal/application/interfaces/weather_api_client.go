@@ -2,17 +2,93 @@ package interfaces
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 )
 
+// Kinds of upstream failure, matched with errors.Is on the errors returned by weather API clients.
+var (
+	// ErrCityNotFound means the provider does not know the requested location.
+	ErrCityNotFound = errors.New("city not found")
+	// ErrUpstreamUnauthorized means the provider rejected our credentials.
+	ErrUpstreamUnauthorized = errors.New("upstream API rejected the credentials")
+	// ErrUpstreamRateLimited means the provider is throttling our calls.
+	ErrUpstreamRateLimited = errors.New("upstream API rate limit exceeded")
+	// ErrUpstreamUnavailable means the provider timed out, could not be reached or failed with a 5xx.
+	ErrUpstreamUnavailable = errors.New("upstream API unavailable")
+)
+
+// ErrCircuitOpen is matched by errors.Is when a provider was not called because its
+// circuit breaker is open after repeated failures. It is also an ErrUpstreamUnavailable.
+var ErrCircuitOpen = fmt.Errorf("circuit breaker is open: %w", ErrUpstreamUnavailable)
+
+// UpstreamError is an error response of a weather provider.
+type UpstreamError struct {
+	Provider   string
+	StatusCode int
+	// Message is the reason given in the provider's error body, if any
+	Message string
+	// Kind is one of the upstream error kinds above, or nil when the response fits none
+	Kind error
+}
+
+func (e *UpstreamError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s API returned status %d", e.Provider, e.StatusCode)
+	}
+	return fmt.Sprintf("%s API returned status %d: %s", e.Provider, e.StatusCode, e.Message)
+}
+
+func (e *UpstreamError) Unwrap() error {
+	return e.Kind
+}
+
 type WeatherAPIResponse struct {
+	City        string
+	Country     string
+	Latitude    *float64
+	Longitude   *float64
 	Temperature float64
+	FeelsLike   float64
+	TempMin     float64
+	TempMax     float64
 	Description string
+	ConditionID int
+	Icon        string
 	Humidity    int
+	Pressure    int
+	Visibility  int
+	Clouds      int
 	WindSpeed   float64
+	WindDeg     int
+	WindGust    float64
+	Rain        float64
+	Snow        float64
+	Sunrise     *time.Time
+	Sunset      *time.Time
 	FetchedAt   time.Time
+	Provider    string
 }
 
 type WeatherAPIClient interface {
 	FetchWeatherData(ctx context.Context, city string, country string) (*WeatherAPIResponse, error)
+	FetchWeatherByCoordinates(ctx context.Context, lat float64, lon float64) (*WeatherAPIResponse, error)
+}
+
+type ForecastAPIEntry struct {
+	ForecastAt  time.Time
+	Temperature float64
+	Description string
+	Humidity    int
+	WindSpeed   float64
+}
+
+type ForecastAPIResponse struct {
+	Entries   []ForecastAPIEntry
+	FetchedAt time.Time
+}
+
+type ForecastAPIClient interface {
+	FetchForecast(ctx context.Context, city string, country string) (*ForecastAPIResponse, error)
 }
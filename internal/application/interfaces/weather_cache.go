@@ -0,0 +1,54 @@
+package interfaces
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/weather"
+)
+
+// WeatherKeyPrefix starts every key of the weather cache
+const WeatherKeyPrefix = "weather:"
+
+// WeatherCityKey is the key of the current observation for a city and country
+func WeatherCityKey(city string, country string) string {
+	return fmt.Sprintf("%scity:%s:%s", WeatherKeyPrefix, city, country)
+}
+
+// WeatherCoordinatesKey is the key of the current observation for a point. Coordinates are
+// rounded to two decimals (~1km) so nearby lookups share an entry.
+func WeatherCoordinatesKey(lat float64, lon float64) string {
+	return fmt.Sprintf("%scoord:%.2f:%.2f", WeatherKeyPrefix, lat, lon)
+}
+
+// WeatherIDKey is the key of a stored observation
+func WeatherIDKey(id string) string {
+	return WeatherKeyPrefix + "id:" + id
+}
+
+// WeatherLatestKey is the key of the most recently stored observation for a city
+func WeatherLatestKey(city string) string {
+	return WeatherKeyPrefix + "latest:" + city
+}
+
+// WeatherCache stores weather observations under the keys above. Lookups by city, country
+// or coordinates expire with the TTL they are written with; lookups by ID and the latest
+// observation of a city use the cache's default TTL.
+type WeatherCache interface {
+	// Get returns the observation under key and the remaining TTL of the entry
+	Get(ctx context.Context, key string) (*weather.Weather, time.Duration, error)
+	GetByID(ctx context.Context, id string) (*weather.Weather, error)
+	// GetLatest returns the most recently stored observation for a city
+	GetLatest(ctx context.Context, city string) (*weather.Weather, error)
+	Put(ctx context.Context, key string, w *weather.Weather, ttl time.Duration) error
+	PutByID(ctx context.Context, w *weather.Weather) error
+	PutLatest(ctx context.Context, w *weather.Weather) error
+	// Store caches a newly stored observation under key for ttl, by its ID and as the
+	// latest for its city
+	Store(ctx context.Context, key string, w *weather.Weather, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	// Invalidate removes the entries that may hold w: by ID, by its city and country, and
+	// the latest for its city
+	Invalidate(ctx context.Context, w *weather.Weather) error
+}
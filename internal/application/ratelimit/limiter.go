@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/pkg/logger"
+)
+
+// keyPrefix namespaces the window counters in the cache
+const keyPrefix = "ratelimit:"
+
+// Result is the outcome of one rate limit check.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time until the current window ends
+	Reset time.Duration
+	// RetryAfter is how long a rejected client should wait; zero when allowed
+	RetryAfter time.Duration
+}
+
+// Limiter enforces request limits with a sliding window counter: a request is counted
+// in the counter of the current fixed window, and the previous window's count is
+// weighted by how much of it still overlaps the sliding window. This needs only an
+// atomic increment per request, which interfaces.Cache provides.
+//
+// Counters are kept in the cache so all instances share them. When the cache is nil or
+// fails, the limiter falls back to counting in memory, per instance, until it recovers.
+type Limiter struct {
+	cache    interfaces.Cache
+	memory   *MemoryStore
+	window   time.Duration
+	degraded atomic.Bool
+	now      func() time.Time // testable clock
+}
+
+// NewLimiter creates a limiter counting over windows of the given length in cache.
+func NewLimiter(cache interfaces.Cache, window time.Duration) *Limiter {
+	if window <= 0 {
+		window = time.Minute
+	}
+	return &Limiter{
+		cache:  cache,
+		memory: NewMemoryStore(),
+		window: window,
+		now:    time.Now,
+	}
+}
+
+// Allow counts a request of the client identified by key and reports whether it stays within limit.
+// Rejected requests are counted as well, so a client has to slow down to get through again.
+func (l *Limiter) Allow(ctx context.Context, key string, limit int) Result {
+	now := l.now()
+	start := now.Truncate(l.window)
+	elapsed := now.Sub(start)
+	current := fmt.Sprintf("%s%s:%d", keyPrefix, key, start.Unix())
+	previous := fmt.Sprintf("%s%s:%d", keyPrefix, key, start.Add(-l.window).Unix())
+
+	count, prev := l.counts(ctx, current, previous, 2*l.window)
+
+	// Share of the previous window still inside the sliding window
+	weight := 1 - float64(elapsed)/float64(l.window)
+	estimate := float64(prev)*weight + float64(count)
+
+	result := Result{
+		Allowed:   estimate <= float64(limit),
+		Limit:     limit,
+		Remaining: max(0, limit-int(math.Ceil(estimate))),
+		Reset:     l.window - elapsed,
+	}
+	if !result.Allowed {
+		result.RetryAfter = l.retryAfter(float64(prev), float64(count), float64(limit), elapsed)
+	}
+	return result
+}
+
+// counts increments the current window counter and reads the previous one, from the
+// cache or, when it is unavailable, from memory
+func (l *Limiter) counts(ctx context.Context, current string, previous string, ttl time.Duration) (int64, int64) {
+	if l.cache != nil {
+		count, prev, err := l.cacheCounts(ctx, current, previous, ttl)
+		if err == nil {
+			if l.degraded.CompareAndSwap(true, false) {
+				logger.Infof("Rate limiting is using the shared cache again")
+			}
+			return count, prev
+		}
+		if l.degraded.CompareAndSwap(false, true) {
+			logger.Warnf("Rate limit cache unavailable, counting in memory per instance: %v", err)
+		}
+	}
+	return l.memory.Increment(current, ttl), l.memory.Get(previous)
+}
+
+func (l *Limiter) cacheCounts(ctx context.Context, current string, previous string, ttl time.Duration) (int64, int64, error) {
+	count, err := l.cache.Increment(ctx, current)
+	if err != nil {
+		return 0, 0, err
+	}
+	if count == 1 {
+		if err := l.cache.Expire(ctx, current, ttl); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	// A missing previous counter just means no requests in that window
+	var prev int64
+	if err := l.cache.Get(ctx, previous, &prev); err != nil {
+		prev = 0
+	}
+	return count, prev, nil
+}
+
+// retryAfter estimates when the sliding window count drops back below limit
+func (l *Limiter) retryAfter(prev float64, count float64, limit float64, elapsed time.Duration) time.Duration {
+	window := float64(l.window)
+	var wait float64
+	if count < limit && prev > 0 {
+		// Within this window, once enough of the previous window has slid out
+		wait = window*(1-(limit-count)/prev) - float64(elapsed)
+	} else {
+		// In the next window, once enough of this one has slid out
+		wait = window - float64(elapsed) + window*(1-limit/count)
+	}
+	return max(time.Second, time.Duration(wait).Round(time.Second))
+}
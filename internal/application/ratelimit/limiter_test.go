@@ -0,0 +1,126 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	testhelpers.InitTestLogger()
+	m.Run()
+}
+
+// fakeCache implements the counter operations the limiter uses; with down set every call fails
+type fakeCache struct {
+	interfaces.Cache
+	mu      sync.Mutex
+	counter map[string]int64
+	down    bool
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{counter: make(map[string]int64)}
+}
+
+func (f *fakeCache) Increment(ctx context.Context, key string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.down {
+		return 0, errors.New("connection refused")
+	}
+	f.counter[key]++
+	return f.counter[key], nil
+}
+
+func (f *fakeCache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return nil
+}
+
+func (f *fakeCache) Get(ctx context.Context, key string, dest interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n, ok := f.counter[key]
+	if !ok {
+		return errors.New("key not found")
+	}
+	*dest.(*int64) = n
+	return nil
+}
+
+// newTestLimiter returns a limiter whose clock, including that of its memory store, is *now
+func newTestLimiter(cache interfaces.Cache, now *time.Time) *Limiter {
+	l := NewLimiter(cache, time.Minute)
+	l.now = func() time.Time { return *now }
+	l.memory.now = l.now
+	return l
+}
+
+func TestLimiter_SlidingWindow(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := newTestLimiter(nil, &now)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		res := l.Allow(ctx, "ip:10.0.0.1", 3)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 2-i, res.Remaining)
+	}
+
+	res := l.Allow(ctx, "ip:10.0.0.1", 3)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, time.Minute, res.Reset)
+	assert.Greater(t, res.RetryAfter, time.Duration(0))
+
+	// Other clients have their own counters
+	assert.True(t, l.Allow(ctx, "ip:10.0.0.2", 3).Allowed)
+
+	// Right after the window ends the previous one still counts in full
+	now = now.Add(time.Minute)
+	assert.False(t, l.Allow(ctx, "ip:10.0.0.1", 3).Allowed)
+
+	// Three quarters into the window only a quarter of the previous one is left
+	now = now.Add(45 * time.Second)
+	res = l.Allow(ctx, "ip:10.0.0.1", 3)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 15*time.Second, res.Reset)
+}
+
+func TestLimiter_SharedCache(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := newFakeCache()
+	ctx := context.Background()
+
+	// Two instances count into the same cache
+	a := newTestLimiter(cache, &now)
+	b := newTestLimiter(cache, &now)
+	assert.True(t, a.Allow(ctx, "user:alice", 2).Allowed)
+	assert.True(t, b.Allow(ctx, "user:alice", 2).Allowed)
+	assert.False(t, a.Allow(ctx, "user:alice", 2).Allowed)
+	assert.Equal(t, int64(3), cache.counter["ratelimit:user:alice:1735732800"])
+}
+
+func TestLimiter_FallsBackToMemory(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := newFakeCache()
+	cache.down = true
+	l := newTestLimiter(cache, &now)
+	ctx := context.Background()
+
+	assert.True(t, l.Allow(ctx, "user:alice", 1).Allowed)
+	assert.False(t, l.Allow(ctx, "user:alice", 1).Allowed)
+	assert.Empty(t, cache.counter)
+
+	// Once the cache is back it is used again
+	cache.down = false
+	now = now.Add(2 * time.Minute)
+	assert.True(t, l.Allow(ctx, "user:alice", 1).Allowed)
+	assert.Len(t, cache.counter, 1)
+}
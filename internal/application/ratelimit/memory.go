@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepEvery is how many increments pass between removals of expired counters
+const sweepEvery = 1024
+
+type memoryCounter struct {
+	count     int64
+	expiresAt time.Time
+}
+
+// MemoryStore keeps expiring counters in process memory. It is the fallback of the
+// Limiter when the shared cache is unavailable.
+type MemoryStore struct {
+	mu       sync.Mutex
+	counters map[string]*memoryCounter
+	calls    int
+	now      func() time.Time // testable clock
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		counters: make(map[string]*memoryCounter),
+		now:      time.Now,
+	}
+}
+
+// Increment adds one to the counter of key, starting a new counter that expires after ttl
+// when there is none, and returns the new count.
+func (s *MemoryStore) Increment(key string, ttl time.Duration) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.calls++
+	if s.calls%sweepEvery == 0 {
+		for k, c := range s.counters {
+			if !now.Before(c.expiresAt) {
+				delete(s.counters, k)
+			}
+		}
+	}
+
+	c, ok := s.counters[key]
+	if !ok || !now.Before(c.expiresAt) {
+		c = &memoryCounter{expiresAt: now.Add(ttl)}
+		s.counters[key] = c
+	}
+	c.count++
+	return c.count
+}
+
+// Get returns the counter of key, or 0 when it does not exist or has expired.
+func (s *MemoryStore) Get(key string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.counters[key]
+	if !ok || !s.now().Before(c.expiresAt) {
+		return 0
+	}
+	return c.count
+}
@@ -0,0 +1,144 @@
+package scheduler
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/domain/tracking"
+	"github.com/OmidRasouli/weather-api/internal/domain/weather"
+	"github.com/OmidRasouli/weather-api/pkg/logger"
+)
+
+const (
+	DefaultPollInterval = 30 * time.Second
+	DefaultConcurrency  = 4
+	DefaultFetchTimeout = 30 * time.Second
+)
+
+// Refresher fetches and stores the current weather for a city unless a fresh observation is cached.
+type Refresher interface {
+	RefreshWeather(ctx context.Context, city string, country string) (*weather.Weather, error)
+}
+
+// Options tunes the scheduler. Zero values fall back to the defaults above.
+type Options struct {
+	// PollInterval is how often due locations are looked up.
+	PollInterval time.Duration
+	// Concurrency bounds the number of refreshes running at once.
+	Concurrency int
+	// Jitter is the upper bound of a random delay before each refresh, spreading upstream calls.
+	Jitter time.Duration
+	// FetchTimeout bounds a single refresh.
+	FetchTimeout time.Duration
+}
+
+// Scheduler periodically refreshes the weather of tracked locations.
+// Due locations are claimed through the repository, which guarantees that
+// replicas sharing the database never refresh the same location twice.
+type Scheduler struct {
+	repo       interfaces.TrackedLocationRepository
+	refresher  Refresher
+	opts       Options
+	timeSource func() time.Time // testable clock
+}
+
+func NewScheduler(repo interfaces.TrackedLocationRepository, refresher Refresher, opts Options) *Scheduler {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultPollInterval
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+	if opts.Jitter < 0 {
+		opts.Jitter = 0
+	}
+	if opts.FetchTimeout <= 0 {
+		opts.FetchTimeout = DefaultFetchTimeout
+	}
+	return &Scheduler{
+		repo:       repo,
+		refresher:  refresher,
+		opts:       opts,
+		timeSource: time.Now,
+	}
+}
+
+// Run polls for due locations until ctx is cancelled, then waits for in-flight refreshes to finish.
+func (s *Scheduler) Run(ctx context.Context) {
+	logger.Infof("Tracked location scheduler started (poll %s, concurrency %d)", s.opts.PollInterval, s.opts.Concurrency)
+
+	slots := make(chan struct{}, s.opts.Concurrency)
+	var wg sync.WaitGroup
+	defer func() {
+		wg.Wait()
+		logger.Info("Tracked location scheduler stopped")
+	}()
+
+	ticker := time.NewTicker(s.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.dispatch(ctx, slots, &wg)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatch claims at most as many due locations as there are free slots and refreshes them in the background
+func (s *Scheduler) dispatch(ctx context.Context, slots chan struct{}, wg *sync.WaitGroup) {
+	free := cap(slots) - len(slots)
+	if free == 0 || ctx.Err() != nil {
+		return
+	}
+
+	locations, err := s.repo.ClaimDue(ctx, s.timeSource().UTC(), free)
+	if err != nil {
+		logger.Errorf("failed to claim due tracked locations: %v", err)
+		return
+	}
+
+	for _, l := range locations {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(l *tracking.Location) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			s.refresh(ctx, l)
+		}(l)
+	}
+}
+
+// refresh waits a random jitter, then fetches the location's weather and records the outcome.
+// A refresh that has started is allowed to finish during shutdown; one still waiting is skipped
+// and runs again at its next scheduled time.
+func (s *Scheduler) refresh(ctx context.Context, l *tracking.Location) {
+	if s.opts.Jitter > 0 {
+		timer := time.NewTimer(time.Duration(rand.Int63n(int64(s.opts.Jitter))))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+
+	fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.opts.FetchTimeout)
+	defer cancel()
+
+	_, err := s.refresher.RefreshWeather(fetchCtx, l.City, l.Country)
+	if err != nil {
+		logger.Warnf("Scheduled refresh of %s,%s failed: %v", l.City, l.Country, err)
+	}
+	if err := s.repo.MarkRefreshed(fetchCtx, l.ID.String(), s.timeSource().UTC(), err); err != nil {
+		logger.Errorf("failed to record refresh of tracked location %s: %v", l.ID, err)
+	}
+}
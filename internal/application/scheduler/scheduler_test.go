@@ -0,0 +1,178 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/domain/tracking"
+	"github.com/OmidRasouli/weather-api/internal/domain/weather"
+	"github.com/OmidRasouli/weather-api/internal/testhelpers"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	testhelpers.InitTestLogger()
+	os.Exit(m.Run())
+}
+
+// fakeRepo hands out each due location once, honouring the claim limit like the Postgres implementation
+type fakeRepo struct {
+	interfaces.TrackedLocationRepository
+
+	mu        sync.Mutex
+	due       []*tracking.Location
+	refreshed map[string]error
+}
+
+func (r *fakeRepo) ClaimDue(_ context.Context, _ time.Time, limit int) ([]*tracking.Location, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if limit > len(r.due) {
+		limit = len(r.due)
+	}
+	claimed := r.due[:limit]
+	r.due = r.due[limit:]
+	return claimed, nil
+}
+
+func (r *fakeRepo) MarkRefreshed(_ context.Context, id string, _ time.Time, refreshErr error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.refreshed[id] = refreshErr
+	return nil
+}
+
+func (r *fakeRepo) pending() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.due)
+}
+
+func (r *fakeRepo) outcomes() map[string]error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make(map[string]error, len(r.refreshed))
+	for k, v := range r.refreshed {
+		out[k] = v
+	}
+	return out
+}
+
+// fakeRefresher records the peak number of concurrent fetches
+type fakeRefresher struct {
+	delay   time.Duration
+	failFor string
+	active  int32
+	peak    int32
+}
+
+func (f *fakeRefresher) RefreshWeather(ctx context.Context, city string, country string) (*weather.Weather, error) {
+	n := atomic.AddInt32(&f.active, 1)
+	defer atomic.AddInt32(&f.active, -1)
+	for {
+		p := atomic.LoadInt32(&f.peak)
+		if n <= p || atomic.CompareAndSwapInt32(&f.peak, p, n) {
+			break
+		}
+	}
+
+	select {
+	case <-time.After(f.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if city == f.failFor {
+		return nil, fmt.Errorf("upstream unavailable")
+	}
+	return &weather.Weather{City: city, Country: country}, nil
+}
+
+func newLocations(n int) []*tracking.Location {
+	locations := make([]*tracking.Location, 0, n)
+	for i := 0; i < n; i++ {
+		locations = append(locations, &tracking.Location{
+			ID:      uuid.New(),
+			City:    fmt.Sprintf("city-%d", i),
+			Country: "DE",
+		})
+	}
+	return locations
+}
+
+func TestScheduler_BoundedConcurrency(t *testing.T) {
+	locations := newLocations(10)
+	repo := &fakeRepo{due: locations, refreshed: map[string]error{}}
+	refresher := &fakeRefresher{delay: 20 * time.Millisecond, failFor: "city-3"}
+	s := NewScheduler(repo, refresher, Options{PollInterval: 5 * time.Millisecond, Concurrency: 3})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return len(repo.outcomes()) == len(locations) }, 2*time.Second, 5*time.Millisecond)
+	cancel()
+	<-done
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&refresher.peak), int32(3))
+	outcomes := repo.outcomes()
+	assert.EqualError(t, outcomes[locations[3].ID.String()], "upstream unavailable")
+	assert.NoError(t, outcomes[locations[0].ID.String()])
+}
+
+func TestScheduler_ShutdownWaitsForInFlight(t *testing.T) {
+	locations := newLocations(2)
+	repo := &fakeRepo{due: locations, refreshed: map[string]error{}}
+	refresher := &fakeRefresher{delay: 50 * time.Millisecond}
+	s := NewScheduler(repo, refresher, Options{PollInterval: time.Hour, Concurrency: 2})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&refresher.active) == 2 }, time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	// Both refreshes started before shutdown, so both completed and were recorded
+	outcomes := repo.outcomes()
+	assert.Len(t, outcomes, 2)
+	for _, err := range outcomes {
+		assert.NoError(t, err)
+	}
+}
+
+func TestScheduler_ShutdownSkipsJitterWait(t *testing.T) {
+	repo := &fakeRepo{due: newLocations(1), refreshed: map[string]error{}}
+	refresher := &fakeRefresher{}
+	s := NewScheduler(repo, refresher, Options{PollInterval: time.Hour, Concurrency: 1, Jitter: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return repo.pending() == 0 }, time.Second, time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop while a refresh was waiting for jitter")
+	}
+	assert.Empty(t, repo.outcomes())
+}
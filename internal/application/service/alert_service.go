@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/domain/alert"
+	"github.com/OmidRasouli/weather-api/internal/domain/weather"
+	"github.com/OmidRasouli/weather-api/pkg/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultWebhookMaxAttempts  = 3
+	DefaultWebhookRetryBackoff = time.Second
+	// DefaultDeliveryPageSize is the number of deliveries returned when no limit is given.
+	DefaultDeliveryPageSize = 50
+	// DeliveryTimeout bounds all the attempts of one delivery, backoff included.
+	DeliveryTimeout = 2 * time.Minute
+	// deliverySaveTimeout bounds recording the outcome of a delivery.
+	deliverySaveTimeout = 10 * time.Second
+)
+
+// AlertPayload is the JSON body posted to a rule's webhook.
+type AlertPayload struct {
+	DeliveryID  string           `json:"deliveryId"`
+	Rule        AlertPayloadRule `json:"rule"`
+	Value       float64          `json:"value"`
+	Weather     *weather.Weather `json:"weather"`
+	TriggeredAt time.Time        `json:"triggeredAt"`
+}
+
+// AlertPayloadRule identifies the rule that triggered a notification.
+type AlertPayloadRule struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Field     string         `json:"field"`
+	Operator  alert.Operator `json:"operator"`
+	Threshold float64        `json:"threshold"`
+}
+
+// AlertService manages alert rules and notifies their webhooks when a stored observation matches.
+type AlertService struct {
+	rules        interfaces.AlertRuleRepository
+	deliveries   interfaces.AlertDeliveryRepository
+	sender       interfaces.WebhookSender
+	maxAttempts  int
+	retryBackoff time.Duration
+	pending      sync.WaitGroup
+	timeSource   func() time.Time // testable clock
+
+	// ctx is the parent of every delivery, detached from the request that stored the
+	// observation; stop cancels it at shutdown
+	ctx  context.Context
+	stop context.CancelFunc
+}
+
+// NewAlertService creates the service; non-positive retry settings fall back to the defaults.
+func NewAlertService(rules interfaces.AlertRuleRepository, deliveries interfaces.AlertDeliveryRepository, sender interfaces.WebhookSender, maxAttempts int, retryBackoff time.Duration) *AlertService {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultWebhookMaxAttempts
+	}
+	if retryBackoff <= 0 {
+		retryBackoff = DefaultWebhookRetryBackoff
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	return &AlertService{
+		rules:        rules,
+		deliveries:   deliveries,
+		sender:       sender,
+		maxAttempts:  maxAttempts,
+		retryBackoff: retryBackoff,
+		timeSource:   time.Now,
+		ctx:          ctx,
+		stop:         stop,
+	}
+}
+
+// CreateRule validates and stores a rule. A signing secret is generated when none is given.
+func (s *AlertService) CreateRule(ctx context.Context, r *alert.Rule) (*alert.Rule, error) {
+	if r.Secret == "" {
+		secret, err := newSecret()
+		if err != nil {
+			return nil, err
+		}
+		r.Secret = secret
+	}
+	if err := s.validate(ctx, r); err != nil {
+		return nil, err
+	}
+
+	now := s.timeSource().UTC()
+	r.ID = uuid.New()
+	r.CreatedAt = now
+	r.UpdatedAt = now
+
+	if err := s.rules.Create(ctx, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (s *AlertService) ListRules(ctx context.Context) ([]*alert.Rule, error) {
+	return s.rules.FindAll(ctx)
+}
+
+func (s *AlertService) GetRule(ctx context.Context, id string) (*alert.Rule, error) {
+	return s.rules.FindByID(ctx, id)
+}
+
+// UpdateRule replaces the condition, target and enabled flag of a rule. The secret is kept unless a new one is given.
+func (s *AlertService) UpdateRule(ctx context.Context, id string, update *alert.Rule) (*alert.Rule, error) {
+	existing, err := s.rules.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	existing.Name = update.Name
+	existing.City = update.City
+	existing.Country = update.Country
+	existing.Field = update.Field
+	existing.Operator = update.Operator
+	existing.Threshold = update.Threshold
+	existing.WebhookURL = update.WebhookURL
+	existing.Enabled = update.Enabled
+	if update.Secret != "" {
+		existing.Secret = update.Secret
+	}
+	if err := s.validate(ctx, existing); err != nil {
+		return nil, err
+	}
+	existing.UpdatedAt = s.timeSource().UTC()
+
+	if err := s.rules.Update(ctx, existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// validate checks the rule, and that its webhook does not resolve to an internal address
+func (s *AlertService) validate(ctx context.Context, r *alert.Rule) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	if err := s.sender.CheckURL(ctx, r.WebhookURL); err != nil {
+		return fmt.Errorf("%w: %v", alert.ErrInvalidRule, err)
+	}
+	return nil
+}
+
+func (s *AlertService) DeleteRule(ctx context.Context, id string) error {
+	return s.rules.Delete(ctx, id)
+}
+
+// ListDeliveries returns the most recent webhook deliveries of a rule.
+func (s *AlertService) ListDeliveries(ctx context.Context, ruleID string, limit int) ([]*alert.Delivery, error) {
+	if _, err := s.rules.FindByID(ctx, ruleID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultDeliveryPageSize
+	}
+	return s.deliveries.FindByRule(ctx, ruleID, limit)
+}
+
+// OnWeatherStored evaluates the rules for the observation's location and delivers
+// webhooks for the matching ones in the background, so fetching is never delayed by slow receivers.
+func (s *AlertService) OnWeatherStored(ctx context.Context, w *weather.Weather) {
+	rules, err := s.rules.FindEnabledForLocation(ctx, w.City, w.Country)
+	if err != nil {
+		logger.Errorf("failed to load alert rules for %s,%s: %v", w.City, w.Country, err)
+		return
+	}
+
+	for _, rule := range rules {
+		if !rule.Matches(w) {
+			continue
+		}
+		s.pending.Add(1)
+		go func(rule *alert.Rule) {
+			defer s.pending.Done()
+			s.deliver(rule, w)
+		}(rule)
+	}
+}
+
+// Wait blocks until every in-flight delivery has finished.
+func (s *AlertService) Wait() {
+	s.pending.Wait()
+}
+
+// Shutdown waits for in-flight deliveries. Once ctx is done their remaining attempts are
+// abandoned, and they are recorded as failed.
+func (s *AlertService) Shutdown(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		s.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.stop()
+		<-done
+	}
+}
+
+// deliver posts the alert with exponential backoff between attempts and records the outcome in the delivery log
+func (s *AlertService) deliver(rule *alert.Rule, w *weather.Weather) {
+	ctx, cancel := context.WithTimeout(s.ctx, DeliveryTimeout)
+	defer cancel()
+
+	now := s.timeSource().UTC()
+	delivery := &alert.Delivery{
+		ID:        uuid.New(),
+		RuleID:    rule.ID,
+		WeatherID: w.ID,
+		URL:       rule.WebhookURL,
+		CreatedAt: now,
+	}
+
+	payload, err := json.Marshal(AlertPayload{
+		DeliveryID: delivery.ID.String(),
+		Rule: AlertPayloadRule{
+			ID:        rule.ID.String(),
+			Name:      rule.Name,
+			Field:     rule.Field,
+			Operator:  rule.Operator,
+			Threshold: rule.Threshold,
+		},
+		Value:       rule.Value(w),
+		Weather:     w,
+		TriggeredAt: now,
+	})
+	if err != nil {
+		logger.Errorf("failed to encode alert payload for rule %s: %v", rule.ID, err)
+		return
+	}
+
+	backoff := s.retryBackoff
+	for delivery.Attempts < s.maxAttempts {
+		if delivery.Attempts > 0 {
+			if err := sleepContext(ctx, backoff); err != nil {
+				delivery.Error = err.Error()
+				break
+			}
+			backoff *= 2
+		}
+
+		status, err := s.sender.Send(ctx, rule.WebhookURL, rule.Secret, delivery.ID.String(), payload)
+		delivery.Attempts++
+		delivery.StatusCode = status
+		if err == nil {
+			delivery.Succeeded = true
+			delivery.Error = ""
+			break
+		}
+		delivery.Error = err.Error()
+		if !retryableStatus(status) {
+			break
+		}
+	}
+
+	if !delivery.Succeeded {
+		logger.Warnf("Alert webhook for rule %s failed after %d attempt(s): %s", rule.ID, delivery.Attempts, delivery.Error)
+	}
+	delivery.UpdatedAt = s.timeSource().UTC()
+	// Recorded even when the delivery was cut short by shutdown
+	saveCtx, cancelSave := context.WithTimeout(context.Background(), deliverySaveTimeout)
+	defer cancelSave()
+	if err := s.deliveries.Save(saveCtx, delivery); err != nil {
+		logger.Errorf("failed to record alert delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// retryableStatus reports whether a failed attempt is worth retrying: transport errors, timeouts, throttling and 5xx
+func retryableStatus(status int) bool {
+	return status == 0 ||
+		status == http.StatusRequestTimeout ||
+		status == http.StatusTooManyRequests ||
+		status >= http.StatusInternalServerError
+}
+
+// newSecret returns a random 256-bit hex-encoded signing secret
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
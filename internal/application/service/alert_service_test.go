@@ -0,0 +1,195 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/service"
+	"github.com/OmidRasouli/weather-api/internal/application/service/mocks"
+	"github.com/OmidRasouli/weather-api/internal/domain/alert"
+	"github.com/OmidRasouli/weather-api/internal/domain/weather"
+	"github.com/OmidRasouli/weather-api/internal/infrastructure/webhook"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newReceiver returns a webhook receiver answering with the given statuses in order (the last one repeats)
+// and counting the requests whose signature verifies with secret
+func newReceiver(t *testing.T, secret string, statuses ...int) (*httptest.Server, *int32, *int32) {
+	var calls, verified int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&calls, 1))
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(webhook.TimestampHeader), 10, 64)
+		if webhook.Verify(secret, timestamp, body, r.Header.Get(webhook.SignatureHeader)) {
+			atomic.AddInt32(&verified, 1)
+		}
+
+		var payload service.AlertPayload
+		assert.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, 36.5, payload.Value)
+
+		if n > len(statuses) {
+			n = len(statuses)
+		}
+		w.WriteHeader(statuses[n-1])
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls, &verified
+}
+
+// newSender returns a webhook sender that may deliver to the tests' loopback receivers
+func newSender() *webhook.Sender {
+	return webhook.NewSender(time.Second).WithAddressFilter(func(netip.Addr) bool { return true })
+}
+
+func heatRule(url string) *alert.Rule {
+	return &alert.Rule{
+		ID:         uuid.New(),
+		Name:       "heat",
+		City:       "berlin",
+		Country:    "DE",
+		Field:      "temperature",
+		Operator:   alert.GreaterThan,
+		Threshold:  35,
+		WebhookURL: url,
+		Secret:     "s3cret",
+		Enabled:    true,
+	}
+}
+
+func TestOnWeatherStored_RetriesUntilDelivered(t *testing.T) {
+	receiver, calls, verified := newReceiver(t, "s3cret", http.StatusInternalServerError, http.StatusOK)
+	mockRules := new(mocks.MockAlertRuleRepository)
+	mockDeliveries := new(mocks.MockAlertDeliveryRepository)
+	svc := service.NewAlertService(mockRules, mockDeliveries, newSender(), 3, time.Millisecond)
+
+	ctx := context.TODO()
+	rule := heatRule(receiver.URL)
+	observation := &weather.Weather{ID: uuid.New(), City: "berlin", Country: "DE", Temperature: 36.5}
+
+	mockRules.On("FindEnabledForLocation", ctx, "berlin", "DE").Return([]*alert.Rule{rule}, nil)
+	mockDeliveries.On("Save", mock.Anything, mock.MatchedBy(func(d *alert.Delivery) bool {
+		return d.RuleID == rule.ID && d.WeatherID == observation.ID && d.Attempts == 2 && d.Succeeded && d.StatusCode == http.StatusOK
+	})).Return(nil)
+
+	svc.OnWeatherStored(ctx, observation)
+	svc.Wait()
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+	assert.Equal(t, int32(2), atomic.LoadInt32(verified))
+	mockDeliveries.AssertExpectations(t)
+}
+
+func TestOnWeatherStored_ClientErrorIsNotRetried(t *testing.T) {
+	receiver, calls, _ := newReceiver(t, "s3cret", http.StatusBadRequest)
+	mockRules := new(mocks.MockAlertRuleRepository)
+	mockDeliveries := new(mocks.MockAlertDeliveryRepository)
+	svc := service.NewAlertService(mockRules, mockDeliveries, newSender(), 3, time.Millisecond)
+
+	ctx := context.TODO()
+	observation := &weather.Weather{ID: uuid.New(), City: "berlin", Country: "DE", Temperature: 36.5}
+
+	mockRules.On("FindEnabledForLocation", ctx, "berlin", "DE").Return([]*alert.Rule{heatRule(receiver.URL)}, nil)
+	mockDeliveries.On("Save", mock.Anything, mock.MatchedBy(func(d *alert.Delivery) bool {
+		return d.Attempts == 1 && !d.Succeeded && d.StatusCode == http.StatusBadRequest && d.Error != ""
+	})).Return(nil)
+
+	svc.OnWeatherStored(ctx, observation)
+	svc.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+	mockDeliveries.AssertExpectations(t)
+}
+
+func TestOnWeatherStored_NoMatch(t *testing.T) {
+	receiver, calls, _ := newReceiver(t, "s3cret", http.StatusOK)
+	mockRules := new(mocks.MockAlertRuleRepository)
+	mockDeliveries := new(mocks.MockAlertDeliveryRepository)
+	svc := service.NewAlertService(mockRules, mockDeliveries, newSender(), 3, time.Millisecond)
+
+	ctx := context.TODO()
+	observation := &weather.Weather{ID: uuid.New(), City: "berlin", Country: "DE", Temperature: 20}
+
+	mockRules.On("FindEnabledForLocation", ctx, "berlin", "DE").Return([]*alert.Rule{heatRule(receiver.URL)}, nil)
+
+	svc.OnWeatherStored(ctx, observation)
+	svc.Wait()
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(calls))
+	mockDeliveries.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestCreateRule_GeneratesSecret(t *testing.T) {
+	mockRules := new(mocks.MockAlertRuleRepository)
+	svc := service.NewAlertService(mockRules, new(mocks.MockAlertDeliveryRepository), newSender(), 0, 0)
+
+	ctx := context.TODO()
+	rule := heatRule("https://203.0.113.10/hook")
+	rule.Secret = ""
+	mockRules.On("Create", ctx, rule).Return(nil)
+
+	result, err := svc.CreateRule(ctx, rule)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Secret, 64)
+}
+
+func TestCreateRule_InvalidField(t *testing.T) {
+	mockRules := new(mocks.MockAlertRuleRepository)
+	svc := service.NewAlertService(mockRules, new(mocks.MockAlertDeliveryRepository), newSender(), 0, 0)
+
+	rule := heatRule("https://203.0.113.10/hook")
+	rule.Field = "mood"
+
+	result, err := svc.CreateRule(context.TODO(), rule)
+
+	assert.ErrorIs(t, err, alert.ErrInvalidRule)
+	assert.Nil(t, result)
+	mockRules.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateRule_RefusesInternalWebhook(t *testing.T) {
+	mockRules := new(mocks.MockAlertRuleRepository)
+	svc := service.NewAlertService(mockRules, new(mocks.MockAlertDeliveryRepository), webhook.NewSender(time.Second), 0, 0)
+
+	for _, url := range []string{"http://169.254.169.254/latest/meta-data", "http://localhost:8080/admin", "http://10.0.0.5/hook"} {
+		result, err := svc.CreateRule(context.TODO(), heatRule(url))
+
+		assert.ErrorIs(t, err, alert.ErrInvalidRule, url)
+		assert.Nil(t, result)
+	}
+	mockRules.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestShutdown_AbandonsRetries(t *testing.T) {
+	receiver, calls, _ := newReceiver(t, "s3cret", http.StatusServiceUnavailable)
+	mockRules := new(mocks.MockAlertRuleRepository)
+	mockDeliveries := new(mocks.MockAlertDeliveryRepository)
+	svc := service.NewAlertService(mockRules, mockDeliveries, newSender(), 3, time.Hour)
+
+	ctx := context.TODO()
+	observation := &weather.Weather{ID: uuid.New(), City: "berlin", Country: "DE", Temperature: 36.5}
+
+	mockRules.On("FindEnabledForLocation", ctx, "berlin", "DE").Return([]*alert.Rule{heatRule(receiver.URL)}, nil)
+	mockDeliveries.On("Save", mock.Anything, mock.MatchedBy(func(d *alert.Delivery) bool {
+		return d.Attempts == 1 && !d.Succeeded && d.Error == context.Canceled.Error()
+	})).Return(nil)
+
+	svc.OnWeatherStored(ctx, observation)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	svc.Shutdown(shutdownCtx)
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+	mockDeliveries.AssertExpectations(t)
+}
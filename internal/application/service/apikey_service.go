@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/domain/apikey"
+	"github.com/OmidRasouli/weather-api/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// lastUsedResolution limits how often a key's last-used timestamp is written,
+// so busy clients don't cause a database write per request
+const lastUsedResolution = time.Minute
+
+// ErrInvalidAPIKey is returned when a presented API key is unknown, revoked or malformed.
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// APIKeyService issues, revokes and authenticates API keys for machine clients.
+type APIKeyService struct {
+	repo       interfaces.APIKeyRepository
+	timeSource func() time.Time // testable clock
+}
+
+func NewAPIKeyService(repo interfaces.APIKeyRepository) *APIKeyService {
+	return &APIKeyService{
+		repo:       repo,
+		timeSource: time.Now,
+	}
+}
+
+// CreateKey stores a new key and returns it with the plaintext, which is not retrievable later.
+func (s *APIKeyService) CreateKey(ctx context.Context, name string, scopes []string, createdBy string) (*apikey.Key, string, error) {
+	plaintext, prefix, hash, err := apikey.Generate()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := s.timeSource().UTC()
+	k := &apikey.Key{
+		ID:        uuid.New(),
+		Name:      name,
+		Prefix:    prefix,
+		Hash:      hash,
+		Scopes:    scopes,
+		CreatedBy: createdBy,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := k.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	if err := s.repo.Create(ctx, k); err != nil {
+		return nil, "", err
+	}
+	return k, plaintext, nil
+}
+
+func (s *APIKeyService) ListKeys(ctx context.Context) ([]*apikey.Key, error) {
+	return s.repo.FindAll(ctx)
+}
+
+// RevokeKey permanently disables a key. The record is kept so its usage stays visible.
+func (s *APIKeyService) RevokeKey(ctx context.Context, id string) (*apikey.Key, error) {
+	if err := s.repo.Revoke(ctx, id, s.timeSource().UTC()); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(ctx, id)
+}
+
+// Authenticate resolves a plaintext key to its record, rejecting unknown and revoked keys.
+func (s *APIKeyService) Authenticate(ctx context.Context, plaintext string) (*apikey.Key, error) {
+	prefix, err := apikey.ParsePrefix(plaintext)
+	if err != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	k, err := s.repo.FindByPrefix(ctx, prefix)
+	if err != nil {
+		return nil, ErrInvalidAPIKey
+	}
+	if subtle.ConstantTimeCompare([]byte(k.Hash), []byte(apikey.Hash(plaintext))) != 1 || k.Revoked() {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := s.timeSource().UTC()
+	if k.LastUsedAt == nil || now.Sub(*k.LastUsedAt) >= lastUsedResolution {
+		if err := s.repo.TouchLastUsed(ctx, k.ID.String(), now); err != nil {
+			logger.Warnf("failed to record use of api key %s: %v", k.Prefix, err)
+		} else {
+			k.LastUsedAt = &now
+		}
+	}
+	return k, nil
+}
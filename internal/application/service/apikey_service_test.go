@@ -0,0 +1,93 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/service"
+	"github.com/OmidRasouli/weather-api/internal/application/service/mocks"
+	"github.com/OmidRasouli/weather-api/internal/domain/apikey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateKey_StoresOnlyHash(t *testing.T) {
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	svc := service.NewAPIKeyService(mockRepo)
+
+	ctx := context.TODO()
+	var stored *apikey.Key
+	mockRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*apikey.Key)
+	}).Return(nil)
+
+	result, plaintext, err := svc.CreateKey(ctx, "ingest", []string{apikey.ScopeWrite}, "admin")
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(plaintext, "wapi_"+result.Prefix+"_"))
+	assert.Equal(t, apikey.Hash(plaintext), stored.Hash)
+	assert.NotContains(t, stored.Hash, plaintext)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateKey_UnknownScope(t *testing.T) {
+	mockRepo := new(mocks.MockAPIKeyRepository)
+	svc := service.NewAPIKeyService(mockRepo)
+
+	result, _, err := svc.CreateKey(context.TODO(), "ingest", []string{"delete"}, "admin")
+
+	assert.ErrorIs(t, err, apikey.ErrInvalidKey)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestAuthenticate(t *testing.T) {
+	plaintext, prefix, hash, err := apikey.Generate()
+	assert.NoError(t, err)
+	revokedAt := time.Now().Add(-time.Hour)
+	recentlyUsed := time.Now().Add(-10 * time.Second)
+
+	tests := []struct {
+		name      string
+		presented string
+		stored    *apikey.Key
+		wantErr   bool
+		wantTouch bool
+	}{
+		{"valid key records use", plaintext, &apikey.Key{Prefix: prefix, Hash: hash}, false, true},
+		{"recent use is not rewritten", plaintext, &apikey.Key{Prefix: prefix, Hash: hash, LastUsedAt: &recentlyUsed}, false, false},
+		{"wrong secret", plaintext + "x", &apikey.Key{Prefix: prefix, Hash: hash}, true, false},
+		{"revoked key", plaintext, &apikey.Key{Prefix: prefix, Hash: hash, RevokedAt: &revokedAt}, true, false},
+		{"malformed key", "not-a-key", nil, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockAPIKeyRepository)
+			svc := service.NewAPIKeyService(mockRepo)
+			ctx := context.TODO()
+
+			if tt.stored != nil {
+				mockRepo.On("FindByPrefix", ctx, prefix).Return(tt.stored, nil)
+			}
+			mockRepo.On("TouchLastUsed", ctx, mock.Anything, mock.Anything).Return(nil)
+
+			result, err := svc.Authenticate(ctx, tt.presented)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, service.ErrInvalidAPIKey)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, result.LastUsedAt)
+			}
+			if tt.wantTouch {
+				mockRepo.AssertCalled(t, "TouchLastUsed", ctx, mock.Anything, mock.Anything)
+			} else {
+				mockRepo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/domain/forecast"
+	"github.com/OmidRasouli/weather-api/pkg/logger"
+)
+
+// DefaultForecastTTL is how long a forecast stays cached when no TTL is configured.
+// OpenWeather publishes a new 3-hour forecast step roughly every hour.
+const DefaultForecastTTL = time.Hour
+
+type ForecastService struct {
+	repo       interfaces.ForecastRepository
+	apiClient  interfaces.ForecastAPIClient
+	cache      interfaces.Cache
+	ttl        time.Duration
+	timeSource func() time.Time // testable clock
+}
+
+func NewForecastService(repo interfaces.ForecastRepository, api interfaces.ForecastAPIClient, cache interfaces.Cache, ttl time.Duration) *ForecastService {
+	if ttl <= 0 {
+		ttl = DefaultForecastTTL
+	}
+	return &ForecastService{
+		repo:       repo,
+		apiClient:  api,
+		cache:      cache,
+		ttl:        ttl,
+		timeSource: time.Now,
+	}
+}
+
+// GetForecast returns the multi-day forecast for a city from cache, or fetches and stores it.
+// If the upstream API fails, the forecast previously stored in the database is served instead.
+func (s *ForecastService) GetForecast(ctx context.Context, city string, country string) (*forecast.Forecast, error) {
+	cacheKey := fmt.Sprintf("forecast:%s:%s", city, country)
+
+	var cached *forecast.Forecast
+	if err := s.cache.Get(ctx, cacheKey, &cached); err == nil && cached != nil {
+		logger.Infof("Retrieved forecast from cache for %s, %s", city, country)
+		return cached, nil
+	}
+
+	logger.Infof("Cache miss for forecast %s, %s. Fetching from API", city, country)
+	apiData, err := s.apiClient.FetchForecast(ctx, city, country)
+	if err != nil {
+		stored, findErr := s.repo.FindUpcoming(ctx, city, country, s.timeSource())
+		if findErr != nil {
+			return nil, err
+		}
+		logger.Warnf("Forecast API failed for %s, %s: %v. Serving stored forecast", city, country, err)
+		return stored, nil
+	}
+
+	f := &forecast.Forecast{
+		City:      city,
+		Country:   country,
+		FetchedAt: apiData.FetchedAt,
+		Entries:   make([]forecast.Entry, 0, len(apiData.Entries)),
+	}
+	for _, e := range apiData.Entries {
+		f.Entries = append(f.Entries, forecast.Entry{
+			ForecastAt:  e.ForecastAt,
+			Temperature: e.Temperature,
+			Description: e.Description,
+			Humidity:    e.Humidity,
+			WindSpeed:   e.WindSpeed,
+		})
+	}
+
+	if err := s.repo.Save(ctx, f); err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.SetWithTTL(ctx, cacheKey, f, s.ttl); err != nil {
+		logger.Errorf("Failed to cache forecast: %v", err)
+	}
+
+	return f, nil
+}
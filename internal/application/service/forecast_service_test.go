@@ -0,0 +1,93 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/application/service"
+	"github.com/OmidRasouli/weather-api/internal/application/service/mocks"
+	"github.com/OmidRasouli/weather-api/internal/domain/forecast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetForecast_CacheHit(t *testing.T) {
+	mockRepo := new(mocks.MockForecastRepository)
+	mockAPI := new(mocks.MockAPIClient)
+	mockCache := new(mocks.MockCache)
+	svc := service.NewForecastService(mockRepo, mockAPI, mockCache, time.Hour)
+
+	ctx := context.TODO()
+	cached := &forecast.Forecast{
+		City:    "tehran",
+		Country: "IR",
+		Entries: []forecast.Entry{{ForecastAt: time.Now(), Temperature: 25}},
+	}
+
+	mockCache.On("Get", ctx, "forecast:tehran:IR", mock.Anything).Run(func(args mock.Arguments) {
+		dest := args.Get(2).(**forecast.Forecast)
+		*dest = cached
+	}).Return(nil)
+
+	result, err := svc.GetForecast(ctx, "tehran", "IR")
+
+	assert.NoError(t, err)
+	assert.Equal(t, cached, result)
+	mockAPI.AssertNotCalled(t, "FetchForecast")
+	mockRepo.AssertNotCalled(t, "Save")
+}
+
+func TestGetForecast_CacheMiss(t *testing.T) {
+	mockRepo := new(mocks.MockForecastRepository)
+	mockAPI := new(mocks.MockAPIClient)
+	mockCache := new(mocks.MockCache)
+	svc := service.NewForecastService(mockRepo, mockAPI, mockCache, 2*time.Hour)
+
+	ctx := context.TODO()
+	now := time.Now()
+	apiResp := &interfaces.ForecastAPIResponse{
+		Entries: []interfaces.ForecastAPIEntry{
+			{ForecastAt: now.Add(3 * time.Hour), Temperature: 21, Description: "clear sky", Humidity: 30, WindSpeed: 2},
+			{ForecastAt: now.Add(6 * time.Hour), Temperature: 18, Description: "few clouds", Humidity: 35, WindSpeed: 3},
+		},
+		FetchedAt: now,
+	}
+
+	mockCache.On("Get", ctx, "forecast:tehran:IR", mock.Anything).Return(fmt.Errorf("cache miss"))
+	mockAPI.On("FetchForecast", ctx, "tehran", "IR").Return(apiResp, nil)
+	mockRepo.On("Save", ctx, mock.AnythingOfType("*forecast.Forecast")).Return(nil)
+	mockCache.On("SetWithTTL", ctx, "forecast:tehran:IR", mock.Anything, 2*time.Hour).Return(nil)
+
+	result, err := svc.GetForecast(ctx, "tehran", "IR")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "tehran", result.City)
+	assert.Len(t, result.Entries, 2)
+	assert.Equal(t, "few clouds", result.Entries[1].Description)
+	mockAPI.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestGetForecast_APIErrorServesStored(t *testing.T) {
+	mockRepo := new(mocks.MockForecastRepository)
+	mockAPI := new(mocks.MockAPIClient)
+	mockCache := new(mocks.MockCache)
+	svc := service.NewForecastService(mockRepo, mockAPI, mockCache, 0)
+
+	ctx := context.TODO()
+	stored := &forecast.Forecast{City: "tehran", Country: "IR"}
+
+	mockCache.On("Get", ctx, "forecast:tehran:IR", mock.Anything).Return(fmt.Errorf("cache miss"))
+	mockAPI.On("FetchForecast", ctx, "tehran", "IR").Return(nil, fmt.Errorf("upstream down"))
+	mockRepo.On("FindUpcoming", ctx, "tehran", "IR", mock.AnythingOfType("time.Time")).Return(stored, nil)
+
+	result, err := svc.GetForecast(ctx, "tehran", "IR")
+
+	assert.NoError(t, err)
+	assert.Equal(t, stored, result)
+	mockRepo.AssertNotCalled(t, "Save")
+}
@@ -6,6 +6,11 @@ import (
 	"time"
 
 	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/domain/alert"
+	"github.com/OmidRasouli/weather-api/internal/domain/apikey"
+	"github.com/OmidRasouli/weather-api/internal/domain/forecast"
+	"github.com/OmidRasouli/weather-api/internal/domain/tracking"
+	"github.com/OmidRasouli/weather-api/internal/domain/user"
 	"github.com/OmidRasouli/weather-api/internal/domain/weather"
 	"github.com/stretchr/testify/mock"
 )
@@ -20,6 +25,36 @@ type MockAPIClient struct {
 	mock.Mock
 }
 
+// MockForecastRepository mocks the forecast repository
+type MockForecastRepository struct {
+	mock.Mock
+}
+
+// MockTrackedLocationRepository mocks the tracked location repository
+type MockTrackedLocationRepository struct {
+	mock.Mock
+}
+
+// MockAlertRuleRepository mocks the alert rule repository
+type MockAlertRuleRepository struct {
+	mock.Mock
+}
+
+// MockAlertDeliveryRepository mocks the alert delivery log
+type MockAlertDeliveryRepository struct {
+	mock.Mock
+}
+
+// MockUserRepository mocks the user repository
+type MockUserRepository struct {
+	mock.Mock
+}
+
+// MockAPIKeyRepository mocks the API key repository
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
 // MockCache mocks the Redis client
 type MockCache struct {
 	mock.Mock
@@ -41,11 +76,26 @@ func (m *MockWeatherRepository) FindAll(ctx context.Context) ([]*weather.Weather
 	return args.Get(0).([]*weather.Weather), args.Error(1)
 }
 
+func (m *MockWeatherRepository) FindByQuery(ctx context.Context, q weather.Query) ([]*weather.Weather, int64, error) {
+	args := m.Called(ctx, q)
+	return args.Get(0).([]*weather.Weather), args.Get(1).(int64), args.Error(2)
+}
+
 func (m *MockWeatherRepository) FindLatestByCity(ctx context.Context, city string) (*weather.Weather, error) {
 	args := m.Called(ctx, city)
 	return args.Get(0).(*weather.Weather), args.Error(1)
 }
 
+func (m *MockWeatherRepository) FindHistory(ctx context.Context, q weather.HistoryQuery) ([]*weather.HistoryBucket, error) {
+	args := m.Called(ctx, q)
+	return args.Get(0).([]*weather.HistoryBucket), args.Error(1)
+}
+
+func (m *MockWeatherRepository) FindNearby(ctx context.Context, q weather.NearbyQuery) ([]*weather.NearbyResult, error) {
+	args := m.Called(ctx, q)
+	return args.Get(0).([]*weather.NearbyResult), args.Error(1)
+}
+
 func (m *MockWeatherRepository) Update(ctx context.Context, w *weather.Weather) error {
 	args := m.Called(ctx, w)
 	return args.Error(0)
@@ -62,12 +112,213 @@ func (m *MockAPIClient) FetchWeatherData(ctx context.Context, city string, count
 	return args.Get(0).(*interfaces.WeatherAPIResponse), args.Error(1)
 }
 
+func (m *MockAPIClient) FetchWeatherByCoordinates(ctx context.Context, lat float64, lon float64) (*interfaces.WeatherAPIResponse, error) {
+	args := m.Called(ctx, lat, lon)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*interfaces.WeatherAPIResponse), args.Error(1)
+}
+
+func (m *MockAPIClient) FetchForecast(ctx context.Context, city string, country string) (*interfaces.ForecastAPIResponse, error) {
+	args := m.Called(ctx, city, country)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*interfaces.ForecastAPIResponse), args.Error(1)
+}
+
+// MockTrackedLocationRepository methods
+func (m *MockTrackedLocationRepository) Create(ctx context.Context, l *tracking.Location) error {
+	args := m.Called(ctx, l)
+	return args.Error(0)
+}
+
+func (m *MockTrackedLocationRepository) FindByID(ctx context.Context, id string) (*tracking.Location, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*tracking.Location), args.Error(1)
+}
+
+func (m *MockTrackedLocationRepository) FindAll(ctx context.Context) ([]*tracking.Location, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*tracking.Location), args.Error(1)
+}
+
+func (m *MockTrackedLocationRepository) Update(ctx context.Context, l *tracking.Location) error {
+	args := m.Called(ctx, l)
+	return args.Error(0)
+}
+
+func (m *MockTrackedLocationRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockTrackedLocationRepository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*tracking.Location, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]*tracking.Location), args.Error(1)
+}
+
+func (m *MockTrackedLocationRepository) MarkRefreshed(ctx context.Context, id string, at time.Time, refreshErr error) error {
+	args := m.Called(ctx, id, at, refreshErr)
+	return args.Error(0)
+}
+
+// MockAlertRuleRepository methods
+func (m *MockAlertRuleRepository) Create(ctx context.Context, r *alert.Rule) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+func (m *MockAlertRuleRepository) FindByID(ctx context.Context, id string) (*alert.Rule, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*alert.Rule), args.Error(1)
+}
+
+func (m *MockAlertRuleRepository) FindAll(ctx context.Context) ([]*alert.Rule, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*alert.Rule), args.Error(1)
+}
+
+func (m *MockAlertRuleRepository) FindEnabledForLocation(ctx context.Context, city string, country string) ([]*alert.Rule, error) {
+	args := m.Called(ctx, city, country)
+	return args.Get(0).([]*alert.Rule), args.Error(1)
+}
+
+func (m *MockAlertRuleRepository) Update(ctx context.Context, r *alert.Rule) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+func (m *MockAlertRuleRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockAlertDeliveryRepository methods
+func (m *MockAlertDeliveryRepository) Save(ctx context.Context, d *alert.Delivery) error {
+	args := m.Called(ctx, d)
+	return args.Error(0)
+}
+
+func (m *MockAlertDeliveryRepository) FindByRule(ctx context.Context, ruleID string, limit int) ([]*alert.Delivery, error) {
+	args := m.Called(ctx, ruleID, limit)
+	return args.Get(0).([]*alert.Delivery), args.Error(1)
+}
+
+// MockUserRepository methods
+func (m *MockUserRepository) Create(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FindByID(ctx context.Context, id string) (*user.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindByUsername(ctx context.Context, username string) (*user.User, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindByIdentity(ctx context.Context, issuer string, subject string) (*user.User, error) {
+	args := m.Called(ctx, issuer, subject)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindAll(ctx context.Context) ([]*user.User, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*user.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Count(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockAPIKeyRepository methods
+func (m *MockAPIKeyRepository) Create(ctx context.Context, k *apikey.Key) error {
+	args := m.Called(ctx, k)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) FindByID(ctx context.Context, id string) (*apikey.Key, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*apikey.Key), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (*apikey.Key, error) {
+	args := m.Called(ctx, prefix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*apikey.Key), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) FindAll(ctx context.Context) ([]*apikey.Key, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*apikey.Key), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+// MockForecastRepository methods
+func (m *MockForecastRepository) Save(ctx context.Context, f *forecast.Forecast) error {
+	args := m.Called(ctx, f)
+	return args.Error(0)
+}
+
+func (m *MockForecastRepository) FindUpcoming(ctx context.Context, city string, country string, from time.Time) (*forecast.Forecast, error) {
+	args := m.Called(ctx, city, country, from)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*forecast.Forecast), args.Error(1)
+}
+
 // MockRedisClient methods
 func (m *MockCache) Get(ctx context.Context, key string, dest interface{}) error {
 	args := m.Called(ctx, key, dest)
 	return args.Error(0)
 }
 
+func (m *MockCache) GetWithTTL(ctx context.Context, key string, dest interface{}) (time.Duration, error) {
+	args := m.Called(ctx, key, dest)
+	return args.Get(0).(time.Duration), args.Error(1)
+}
+
 func (m *MockCache) Set(ctx context.Context, key string, value interface{}) error {
 	args := m.Called(ctx, key, value)
 	return args.Error(0)
@@ -98,6 +349,11 @@ func (m *MockCache) Flush(ctx context.Context) error {
 	return args.Error(0)
 }
 
+func (m *MockCache) Scan(ctx context.Context, cursor uint64, pattern string, count int64) ([]string, uint64, error) {
+	args := m.Called(ctx, cursor, pattern, count)
+	return args.Get(0).([]string), args.Get(1).(uint64), args.Error(2)
+}
+
 func (m *MockCache) GetKeys(ctx context.Context, pattern string) ([]string, error) {
 	args := m.Called(ctx, pattern)
 	return args.Get(0).([]string), args.Error(1)
@@ -125,5 +381,5 @@ func (m *MockCache) Close() error {
 
 // Helper function to create cache key
 func CreateCacheKey(city, country string) string {
-	return fmt.Sprintf("weather:%s:%s", city, country)
+	return fmt.Sprintf("weather:city:%s:%s", city, country)
 }
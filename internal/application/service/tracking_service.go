@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/domain/tracking"
+	"github.com/google/uuid"
+)
+
+// TrackingService manages the locations refreshed by the background scheduler.
+type TrackingService struct {
+	repo       interfaces.TrackedLocationRepository
+	timeSource func() time.Time // testable clock
+}
+
+func NewTrackingService(repo interfaces.TrackedLocationRepository) *TrackingService {
+	return &TrackingService{
+		repo:       repo,
+		timeSource: time.Now,
+	}
+}
+
+// CreateLocation starts tracking a city. New locations are due immediately.
+func (s *TrackingService) CreateLocation(ctx context.Context, l *tracking.Location) (*tracking.Location, error) {
+	if l.RefreshInterval == 0 {
+		l.RefreshInterval = tracking.DefaultRefreshInterval
+	}
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := s.timeSource().UTC()
+	l.ID = uuid.New()
+	l.NextRunAt = now
+	l.LastRefreshedAt = nil
+	l.LastError = ""
+	l.CreatedAt = now
+	l.UpdatedAt = now
+
+	if err := s.repo.Create(ctx, l); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (s *TrackingService) ListLocations(ctx context.Context) ([]*tracking.Location, error) {
+	return s.repo.FindAll(ctx)
+}
+
+func (s *TrackingService) GetLocation(ctx context.Context, id string) (*tracking.Location, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// UpdateLocation changes the interval or enabled flag and reschedules the next run accordingly.
+func (s *TrackingService) UpdateLocation(ctx context.Context, id string, update tracking.Update) (*tracking.Location, error) {
+	existing, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.timeSource().UTC()
+	if update.RefreshInterval != nil && *update.RefreshInterval != existing.RefreshInterval {
+		existing.RefreshInterval = *update.RefreshInterval
+		// Keep the cadence anchored to the last refresh so a shorter interval takes effect at once
+		existing.NextRunAt = now
+		if existing.LastRefreshedAt != nil {
+			existing.NextRunAt = existing.LastRefreshedAt.Add(existing.RefreshInterval)
+		}
+	}
+	if update.Enabled != nil {
+		if *update.Enabled && !existing.Enabled {
+			existing.NextRunAt = now
+		}
+		existing.Enabled = *update.Enabled
+	}
+	if err := existing.Validate(); err != nil {
+		return nil, err
+	}
+	existing.UpdatedAt = now
+
+	if err := s.repo.Update(ctx, existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+func (s *TrackingService) DeleteLocation(ctx context.Context, id string) error {
+	return s.repo.Delete(ctx, id)
+}
@@ -0,0 +1,67 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/service"
+	"github.com/OmidRasouli/weather-api/internal/application/service/mocks"
+	"github.com/OmidRasouli/weather-api/internal/domain/tracking"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateLocation_DefaultsInterval(t *testing.T) {
+	mockRepo := new(mocks.MockTrackedLocationRepository)
+	svc := service.NewTrackingService(mockRepo)
+
+	ctx := context.TODO()
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(l *tracking.Location) bool {
+		return l.City == "berlin" && l.RefreshInterval == tracking.DefaultRefreshInterval && !l.NextRunAt.IsZero()
+	})).Return(nil)
+
+	result, err := svc.CreateLocation(ctx, &tracking.Location{City: "berlin", Country: "DE", Enabled: true})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateLocation_IntervalTooShort(t *testing.T) {
+	mockRepo := new(mocks.MockTrackedLocationRepository)
+	svc := service.NewTrackingService(mockRepo)
+
+	result, err := svc.CreateLocation(context.TODO(), &tracking.Location{City: "berlin", Country: "DE", RefreshInterval: 10 * time.Second})
+
+	assert.ErrorIs(t, err, tracking.ErrInvalidLocation)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUpdateLocation_ReschedulesFromLastRefresh(t *testing.T) {
+	mockRepo := new(mocks.MockTrackedLocationRepository)
+	svc := service.NewTrackingService(mockRepo)
+
+	ctx := context.TODO()
+	lastRefreshed := time.Now().Add(-10 * time.Minute).UTC()
+	existing := &tracking.Location{
+		City:            "berlin",
+		Country:         "DE",
+		RefreshInterval: time.Hour,
+		Enabled:         true,
+		NextRunAt:       lastRefreshed.Add(time.Hour),
+		LastRefreshedAt: &lastRefreshed,
+	}
+	interval := 15 * time.Minute
+
+	mockRepo.On("FindByID", ctx, "loc-id").Return(existing, nil)
+	mockRepo.On("Update", ctx, existing).Return(nil)
+
+	result, err := svc.UpdateLocation(ctx, "loc-id", tracking.Update{RefreshInterval: &interval})
+
+	assert.NoError(t, err)
+	assert.Equal(t, interval, result.RefreshInterval)
+	assert.Equal(t, lastRefreshed.Add(interval), result.NextRunAt)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/domain/services"
+	"github.com/OmidRasouli/weather-api/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+// UserService manages the accounts that can log in to the API.
+type UserService struct {
+	repo       interfaces.UserRepository
+	timeSource func() time.Time // testable clock
+}
+
+func NewUserService(repo interfaces.UserRepository) *UserService {
+	return &UserService{
+		repo:       repo,
+		timeSource: time.Now,
+	}
+}
+
+// CreateUser validates the credentials and stores a new account with a bcrypt password hash.
+func (s *UserService) CreateUser(ctx context.Context, username string, password string, role user.Role) (*user.User, error) {
+	if _, err := user.ParseRole(string(role)); err != nil {
+		return nil, err
+	}
+	if err := user.ValidateUsername(username); err != nil {
+		return nil, err
+	}
+	if err := user.ValidatePassword(password); err != nil {
+		return nil, err
+	}
+
+	hash, err := services.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.timeSource().UTC()
+	u := &user.User{
+		ID:           uuid.New(),
+		Username:     username,
+		PasswordHash: hash,
+		Role:         role,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := s.repo.Create(ctx, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (s *UserService) ListUsers(ctx context.Context) ([]*user.User, error) {
+	return s.repo.FindAll(ctx)
+}
+
+func (s *UserService) GetUser(ctx context.Context, id string) (*user.User, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// SetDisabled disables or re-enables an account. Disabled accounts cannot log in.
+func (s *UserService) SetDisabled(ctx context.Context, id string, disabled bool) (*user.User, error) {
+	u, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	u.Disabled = disabled
+	u.UpdatedAt = s.timeSource().UTC()
+	if err := s.repo.Update(ctx, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// SetRole changes the role of an account. Tokens issued earlier keep their old role until they expire.
+func (s *UserService) SetRole(ctx context.Context, id string, role user.Role) (*user.User, error) {
+	if _, err := user.ParseRole(string(role)); err != nil {
+		return nil, err
+	}
+
+	u, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	u.Role = role
+	u.UpdatedAt = s.timeSource().UTC()
+	if err := s.repo.Update(ctx, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// ResetPassword replaces the password of an account. When password is empty a random
+// one is generated; the plaintext password is returned so it can be handed to the user.
+func (s *UserService) ResetPassword(ctx context.Context, id string, password string) (*user.User, string, error) {
+	if password == "" {
+		generated, err := randomPassword()
+		if err != nil {
+			return nil, "", err
+		}
+		password = generated
+	}
+	if err := user.ValidatePassword(password); err != nil {
+		return nil, "", err
+	}
+
+	u, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	hash, err := services.HashPassword(password)
+	if err != nil {
+		return nil, "", err
+	}
+	u.PasswordHash = hash
+	u.UpdatedAt = s.timeSource().UTC()
+	if err := s.repo.Update(ctx, u); err != nil {
+		return nil, "", err
+	}
+	return u, password, nil
+}
+
+// BootstrapAdmin creates the first admin account when the users table is empty.
+// It reports whether an account was created; once any user exists it does nothing.
+func (s *UserService) BootstrapAdmin(ctx context.Context, username string, password string) (bool, error) {
+	if username == "" || password == "" {
+		return false, nil
+	}
+
+	count, err := s.repo.Count(ctx)
+	if err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
+	}
+
+	if _, err := s.CreateUser(ctx, username, password, user.RoleAdmin); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// randomPassword returns a URL-safe password with 128 bits of entropy
+func randomPassword() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
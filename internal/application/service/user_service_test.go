@@ -0,0 +1,129 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/OmidRasouli/weather-api/internal/application/service"
+	"github.com/OmidRasouli/weather-api/internal/application/service/mocks"
+	"github.com/OmidRasouli/weather-api/internal/domain/services"
+	"github.com/OmidRasouli/weather-api/internal/domain/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateUser_HashesPassword(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	svc := service.NewUserService(mockRepo)
+
+	ctx := context.TODO()
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(u *user.User) bool {
+		return u.Username == "alice" && u.PasswordHash != "s3cret-pass" && services.ComparePassword(u.PasswordHash, "s3cret-pass")
+	})).Return(nil)
+
+	result, err := svc.CreateUser(ctx, "alice", "s3cret-pass", user.RoleViewer)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.ID)
+	assert.Equal(t, user.RoleViewer, result.Role)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateUser_PasswordTooShort(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	svc := service.NewUserService(mockRepo)
+
+	result, err := svc.CreateUser(context.TODO(), "alice", "short", user.RoleViewer)
+
+	assert.ErrorIs(t, err, user.ErrInvalidUser)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateUser_UnknownRole(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	svc := service.NewUserService(mockRepo)
+
+	result, err := svc.CreateUser(context.TODO(), "alice", "s3cret-pass", user.Role("owner"))
+
+	assert.ErrorIs(t, err, user.ErrInvalidUser)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestSetRole(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	svc := service.NewUserService(mockRepo)
+
+	ctx := context.TODO()
+	existing := &user.User{Username: "alice", Role: user.RoleViewer}
+	mockRepo.On("FindByID", ctx, "user-id").Return(existing, nil)
+	mockRepo.On("Update", ctx, existing).Return(nil)
+
+	result, err := svc.SetRole(ctx, "user-id", user.RoleEditor)
+
+	assert.NoError(t, err)
+	assert.Equal(t, user.RoleEditor, result.Role)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestResetPassword_GeneratesPassword(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	svc := service.NewUserService(mockRepo)
+
+	ctx := context.TODO()
+	existing := &user.User{Username: "alice", PasswordHash: "old"}
+	mockRepo.On("FindByID", ctx, "user-id").Return(existing, nil)
+	mockRepo.On("Update", ctx, existing).Return(nil)
+
+	result, password, err := svc.ResetPassword(ctx, "user-id", "")
+
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, len(password), user.MinPasswordLength)
+	assert.True(t, services.ComparePassword(result.PasswordHash, password))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBootstrapAdmin(t *testing.T) {
+	t.Run("creates admin when no users exist", func(t *testing.T) {
+		mockRepo := new(mocks.MockUserRepository)
+		svc := service.NewUserService(mockRepo)
+
+		ctx := context.TODO()
+		mockRepo.On("Count", ctx).Return(int64(0), nil)
+		mockRepo.On("Create", ctx, mock.MatchedBy(func(u *user.User) bool {
+			return u.Username == "admin" && u.Role == user.RoleAdmin
+		})).Return(nil)
+
+		created, err := svc.BootstrapAdmin(ctx, "admin", "bootstrap-pass")
+
+		assert.NoError(t, err)
+		assert.True(t, created)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("skips when users exist", func(t *testing.T) {
+		mockRepo := new(mocks.MockUserRepository)
+		svc := service.NewUserService(mockRepo)
+
+		ctx := context.TODO()
+		mockRepo.On("Count", ctx).Return(int64(2), nil)
+
+		created, err := svc.BootstrapAdmin(ctx, "admin", "bootstrap-pass")
+
+		assert.NoError(t, err)
+		assert.False(t, created)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("skips when credentials are not configured", func(t *testing.T) {
+		mockRepo := new(mocks.MockUserRepository)
+		svc := service.NewUserService(mockRepo)
+
+		created, err := svc.BootstrapAdmin(context.TODO(), "", "")
+
+		assert.NoError(t, err)
+		assert.False(t, created)
+		mockRepo.AssertNotCalled(t, "Count", mock.Anything)
+	})
+}
@@ -2,7 +2,8 @@ package service
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"sync"
 	"time"
 
 	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
@@ -10,12 +11,44 @@ import (
 	"github.com/OmidRasouli/weather-api/pkg/logger"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
 )
 
+const (
+	// DefaultSoftTTL is how long a cached observation is served without being refreshed.
+	DefaultSoftTTL = 5 * time.Minute
+	// DefaultHardTTL is how long a cached observation is served at all.
+	DefaultHardTTL = 10 * time.Minute
+	// refreshTimeout bounds a background refresh of a cached observation
+	refreshTimeout = 30 * time.Second
+
+	// lockKeyPrefix namespaces the distributed locks taken on cache keys
+	lockKeyPrefix = "lock:"
+	// lockTTL bounds how long a crashed holder can keep a key locked
+	lockTTL = 30 * time.Second
+	// lockWait is how long a replica waits for another one's observation before fetching its own
+	lockWait = 15 * time.Second
+	// lockPoll is how often a waiting replica checks the cache for the holder's observation
+	lockPoll = 50 * time.Millisecond
+	// loadTimeout bounds a fetch shared by concurrent callers, including the wait for the lock
+	loadTimeout = lockWait + refreshTimeout
+)
+
+// staleRadiusKm is how far from the requested coordinates a stored observation may be
+// when it is served in place of an unavailable upstream API
+const staleRadiusKm = 2.0
+
 type WeatherService struct {
 	repo       interfaces.WeatherRepository
 	apiClient  interfaces.WeatherAPIClient
-	cache      interfaces.Cache
+	cache      interfaces.WeatherCache
+	observers  []interfaces.WeatherObserver
+	locker     interfaces.Locker
+	flights    singleflight.Group // in-process deduplication of fetches per cache key
+	softTTL    time.Duration
+	hardTTL    time.Duration
+	refreshing sync.Map // cache keys with a background refresh in flight
+	pending    sync.WaitGroup
 	timeSource func() time.Time // testable clock
 }
 
@@ -23,45 +56,325 @@ func (s *WeatherService) GetWeather(ctx *gin.Context, param any) (any, any) {
 	panic("unimplemented")
 }
 
-func NewWeatherService(repo interfaces.WeatherRepository, api interfaces.WeatherAPIClient, cache interfaces.Cache) *WeatherService {
+func NewWeatherService(repo interfaces.WeatherRepository, api interfaces.WeatherAPIClient, cache interfaces.WeatherCache) *WeatherService {
 	return &WeatherService{
 		repo:       repo,
 		apiClient:  api,
 		cache:      cache,
+		softTTL:    DefaultSoftTTL,
+		hardTTL:    DefaultHardTTL,
 		timeSource: time.Now,
 	}
 }
 
-// FetchAndStoreWeather fetches weather data from the API or cache and stores it
-func (s *WeatherService) FetchAndStoreWeather(ctx context.Context, city string, country string) (*weather.Weather, error) {
-	// Create a cache key based on city and country
-	cacheKey := fmt.Sprintf("weather:%s:%s", city, country)
+// WithCacheTTLs sets how long cached observations are fresh (soft) and how long they are
+// served at all (hard). Between the two a cached observation is served marked stale while
+// it is refreshed in the background. Zero values keep the defaults.
+func (s *WeatherService) WithCacheTTLs(soft time.Duration, hard time.Duration) *WeatherService {
+	if hard > 0 {
+		s.hardTTL = hard
+	}
+	if soft > 0 {
+		s.softTTL = soft
+	}
+	if s.softTTL > s.hardTTL {
+		s.softTTL = s.hardTTL
+	}
+	return s
+}
+
+// WithLocker makes replicas take turns fetching each key, so concurrent cache misses across
+// replicas cause one upstream call and one stored observation.
+func (s *WeatherService) WithLocker(l interfaces.Locker) *WeatherService {
+	s.locker = l
+	return s
+}
+
+// Wait blocks until every background refresh has finished.
+func (s *WeatherService) Wait() {
+	s.pending.Wait()
+}
+
+// WithObserver registers an observer that is called whenever a fetched observation is stored.
+func (s *WeatherService) WithObserver(o interfaces.WeatherObserver) *WeatherService {
+	s.observers = append(s.observers, o)
+	return s
+}
+
+// FetchAndStoreWeather returns the weather for a city from cache, or fetches it from the API
+// and stores it. A cached observation past the soft TTL is served marked stale and refreshed
+// in the background; past the hard TTL it has expired and is fetched synchronously.
+func (s *WeatherService) FetchAndStoreWeather(ctx context.Context, city string, country string) (*weather.Observation, error) {
+	cacheKey := interfaces.WeatherCityKey(city, country)
 
-	// Try to get from cache first
-	var weatherData *weather.Weather
-	err := s.cache.Get(ctx, cacheKey, &weatherData)
-	if err == nil {
-		// Cache hit!
+	// Try to get from cache first; a stale entry is refreshed with fetch
+	fetch := func(ctx context.Context) (*weather.Observation, error) {
+		return s.fetchCity(ctx, cacheKey, city, country)
+	}
+	if cached := s.fromCache(ctx, cacheKey, fetch); cached != nil {
 		logger.Infof("Retrieved weather data from cache for %s, %s", city, country)
-		return weatherData, nil
+		return cached, nil
 	}
 
 	// Cache miss, fetch from API
 	logger.Infof("Cache miss for %s, %s. Fetching from API", city, country)
-	apiData, err := s.apiClient.FetchWeatherData(ctx, city, country)
+	w, err := s.fetchCity(ctx, cacheKey, city, country)
+	if err != nil && errors.Is(err, interfaces.ErrCircuitOpen) {
+		// The latest row of the city may be of a namesake in another country, so filter by both
+		stored, _, findErr := s.repo.FindByQuery(ctx, weather.Query{City: city, Country: country, SortBy: "fetched_at", Descending: true, Limit: 1})
+		if findErr == nil && len(stored) > 0 {
+			logger.Warnf("Weather API unavailable for %s, %s: %v. Serving last stored observation", city, country, err)
+			return s.markStale(stored[0]), nil
+		}
+	}
+	return w, err
+}
+
+// RefreshWeather brings the weather for a city up to date: a cached observation within the
+// soft TTL is kept, anything older is fetched synchronously. Unlike FetchAndStoreWeather it
+// never answers with a stale observation, which suits scheduled refreshes.
+func (s *WeatherService) RefreshWeather(ctx context.Context, city string, country string) (*weather.Weather, error) {
+	cacheKey := interfaces.WeatherCityKey(city, country)
+
+	if cached := s.freshFromCache(ctx, cacheKey); cached != nil {
+		return cached.Weather, nil
+	}
+	o, err := s.fetchCity(ctx, cacheKey, city, country)
+	if err != nil {
+		return nil, err
+	}
+	return o.Weather, nil
+}
+
+// fetchCity fetches the weather for a city from the API and stores it, once for all concurrent callers
+func (s *WeatherService) fetchCity(ctx context.Context, cacheKey string, city string, country string) (*weather.Observation, error) {
+	return s.load(ctx, cacheKey, func(ctx context.Context) (*weather.Weather, error) {
+		apiData, err := s.apiClient.FetchWeatherData(ctx, city, country)
+		if err != nil {
+			return nil, err
+		}
+
+		// Keep the requested city/country rather than the provider's spelling
+		apiData.City, apiData.Country = city, country
+		return s.storeAPIData(ctx, cacheKey, apiData)
+	})
+}
+
+// FetchAndStoreWeatherByCoordinates fetches weather data for a latitude/longitude from the API or cache and stores it
+func (s *WeatherService) FetchAndStoreWeatherByCoordinates(ctx context.Context, lat float64, lon float64) (*weather.Observation, error) {
+	// Coordinates are rounded to two decimals (~1km) so nearby lookups share a cache entry
+	cacheKey := interfaces.WeatherCoordinatesKey(lat, lon)
+
+	fetch := func(ctx context.Context) (*weather.Observation, error) {
+		return s.fetchCoordinates(ctx, cacheKey, lat, lon)
+	}
+	if cached := s.fromCache(ctx, cacheKey, fetch); cached != nil {
+		logger.Infof("Retrieved weather data from cache for %.4f, %.4f", lat, lon)
+		return cached, nil
+	}
+
+	logger.Infof("Cache miss for %.4f, %.4f. Fetching from API", lat, lon)
+	w, err := s.fetchCoordinates(ctx, cacheKey, lat, lon)
+	if err != nil && errors.Is(err, interfaces.ErrCircuitOpen) {
+		nearby, findErr := s.repo.FindNearby(ctx, weather.NearbyQuery{Latitude: lat, Longitude: lon, RadiusKm: staleRadiusKm, Limit: 1})
+		if findErr == nil && len(nearby) > 0 {
+			logger.Warnf("Weather API unavailable for %.4f, %.4f: %v. Serving last stored observation", lat, lon, err)
+			return s.markStale(nearby[0].Weather), nil
+		}
+	}
+	return w, err
+}
+
+// fetchCoordinates fetches the weather for a latitude/longitude from the API and stores it, once for all concurrent callers
+func (s *WeatherService) fetchCoordinates(ctx context.Context, cacheKey string, lat float64, lon float64) (*weather.Observation, error) {
+	return s.load(ctx, cacheKey, func(ctx context.Context) (*weather.Weather, error) {
+		apiData, err := s.apiClient.FetchWeatherByCoordinates(ctx, lat, lon)
+		if err != nil {
+			return nil, err
+		}
+
+		// Fall back to the requested coordinates if the provider didn't echo them
+		if apiData.Latitude == nil || apiData.Longitude == nil {
+			apiData.Latitude, apiData.Longitude = &lat, &lon
+		}
+		return s.storeAPIData(ctx, cacheKey, apiData)
+	})
+}
+
+// load runs fetch for cacheKey once at a time. Concurrent callers in this process share one
+// call; with a locker, replicas take turns on the key and those that waited read the holder's
+// observation from the cache instead of fetching and storing their own.
+//
+// The shared call is detached from the caller that started it, so it is neither abandoned
+// nor failed for the others when that caller goes away; each caller stops waiting for it
+// when its own ctx is done.
+func (s *WeatherService) load(ctx context.Context, cacheKey string, fetch func(ctx context.Context) (*weather.Weather, error)) (*weather.Observation, error) {
+	flight := s.flights.DoChan(cacheKey, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+		defer cancel()
+
+		if s.locker != nil {
+			return s.loadLocked(ctx, cacheKey, fetch)
+		}
+		// A flight that just ended may have stored the observation after our cache lookup
+		if cached := s.freshFromCache(ctx, cacheKey); cached != nil {
+			return cached, nil
+		}
+		return fetched(fetch(ctx))
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-flight:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*weather.Observation), nil
+	}
+}
+
+// loadLocked runs fetch while holding the distributed lock on cacheKey, or returns the
+// observation stored by the replica holding it. When the lock cannot be taken in time, or
+// the locker fails, it fetches without the lock rather than failing the request.
+func (s *WeatherService) loadLocked(ctx context.Context, cacheKey string, fetch func(ctx context.Context) (*weather.Weather, error)) (*weather.Observation, error) {
+	lockKey := lockKeyPrefix + cacheKey
+	deadline := s.timeSource().Add(lockWait)
+	for {
+		token, ok, err := s.locker.TryLock(ctx, lockKey, lockTTL)
+		if err != nil {
+			logger.Warnf("Failed to lock %s, fetching without the lock: %v", cacheKey, err)
+			return fetched(fetch(ctx))
+		}
+		if ok {
+			defer func() {
+				if err := s.locker.Unlock(context.WithoutCancel(ctx), lockKey, token); err != nil {
+					logger.Warnf("Failed to unlock %s: %v", cacheKey, err)
+				}
+			}()
+			// The previous holder may have stored the observation while we waited
+			if cached := s.freshFromCache(ctx, cacheKey); cached != nil {
+				return cached, nil
+			}
+			return fetched(fetch(ctx))
+		}
+
+		if !s.timeSource().Before(deadline) {
+			logger.Warnf("Timed out waiting for the lock on %s, fetching without it", cacheKey)
+			return fetched(fetch(ctx))
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lockPoll):
+		}
+		if cached := s.freshFromCache(ctx, cacheKey); cached != nil {
+			return cached, nil
+		}
+	}
+}
+
+// freshFromCache returns the observation cached under key if it is within the soft TTL
+func (s *WeatherService) freshFromCache(ctx context.Context, key string) *weather.Observation {
+	cached, remaining, err := s.cache.Get(ctx, key)
+	if err != nil || s.cacheAge(remaining) >= s.softTTL {
+		return nil
+	}
+	return &weather.Observation{Weather: cached, Age: s.cacheAge(remaining)}
+}
+
+// fromCache returns the observation cached under key with its age set, or nil on a miss.
+// One past the soft TTL is marked stale and refreshed with fetch in the background.
+func (s *WeatherService) fromCache(ctx context.Context, key string, fetch func(ctx context.Context) (*weather.Observation, error)) *weather.Observation {
+	cached, remaining, err := s.cache.Get(ctx, key)
+	if err != nil {
+		return nil
+	}
+
+	o := &weather.Observation{Weather: cached, Age: s.cacheAge(remaining)}
+	if o.Age >= s.softTTL {
+		o.Stale = true
+		s.revalidate(key, fetch)
+	}
+	return o
+}
+
+// cacheAge derives the age of a cache entry from its remaining TTL: entries are written with the hard TTL
+func (s *WeatherService) cacheAge(remaining time.Duration) time.Duration {
+	if remaining <= 0 || remaining >= s.hardTTL {
+		return 0
+	}
+	return s.hardTTL - remaining
+}
+
+// revalidate refreshes the entry under key in the background unless a refresh of it is already running
+func (s *WeatherService) revalidate(key string, fetch func(ctx context.Context) (*weather.Observation, error)) {
+	if _, running := s.refreshing.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+		defer s.refreshing.Delete(key)
+
+		// Detached from the request, which is answered with the stale entry meanwhile
+		ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+		defer cancel()
+		if _, err := fetch(ctx); err != nil {
+			logger.Warnf("Background refresh of %s failed: %v", key, err)
+			return
+		}
+		logger.Infof("Refreshed stale cache entry %s", key)
+	}()
+}
+
+// markStale serves an observation from storage as stale, aged since it was stored
+func (s *WeatherService) markStale(w *weather.Weather) *weather.Observation {
+	o := &weather.Observation{Weather: w, Stale: true}
+	if age := s.timeSource().Sub(w.CreatedAt); age > 0 {
+		o.Age = age
+	}
+	return o
+}
+
+// fetched wraps the result of a fetch for this request, which has no age
+func fetched(w *weather.Weather, err error) (*weather.Observation, error) {
 	if err != nil {
 		return nil, err
 	}
+	return &weather.Observation{Weather: w}, nil
+}
 
-	weatherData = &weather.Weather{
+// storeAPIData persists an API response as a new weather record and caches it under cacheKey,
+// its ID and as the latest for its city
+func (s *WeatherService) storeAPIData(ctx context.Context, cacheKey string, apiData *interfaces.WeatherAPIResponse) (*weather.Weather, error) {
+	weatherData := &weather.Weather{
 		ID:          uuid.New(),
-		City:        city,
-		Country:     country,
+		City:        apiData.City,
+		Country:     apiData.Country,
+		Latitude:    apiData.Latitude,
+		Longitude:   apiData.Longitude,
 		Temperature: apiData.Temperature,
+		FeelsLike:   apiData.FeelsLike,
+		TempMin:     apiData.TempMin,
+		TempMax:     apiData.TempMax,
 		Description: apiData.Description,
+		ConditionID: apiData.ConditionID,
+		Icon:        apiData.Icon,
 		Humidity:    apiData.Humidity,
+		Pressure:    apiData.Pressure,
+		Visibility:  apiData.Visibility,
+		Clouds:      apiData.Clouds,
 		WindSpeed:   apiData.WindSpeed,
+		WindDeg:     apiData.WindDeg,
+		WindGust:    apiData.WindGust,
+		Rain:        apiData.Rain,
+		Snow:        apiData.Snow,
+		Sunrise:     apiData.Sunrise,
+		Sunset:      apiData.Sunset,
 		FetchedAt:   apiData.FetchedAt,
+		Provider:    apiData.Provider,
 		CreatedAt:   s.timeSource(),
 		UpdatedAt:   s.timeSource(),
 	}
@@ -70,33 +383,72 @@ func (s *WeatherService) FetchAndStoreWeather(ctx context.Context, city string,
 	if err := s.repo.Save(ctx, weatherData); err != nil {
 		return nil, err
 	}
+	for _, o := range s.observers {
+		o.OnWeatherStored(ctx, weatherData)
+	}
 
-	// Store in cache for future requests
-	if err := s.cache.Set(ctx, cacheKey, weatherData); err != nil {
+	// Store in cache for future requests; entries are written with the hard TTL, from which fromCache derives their age
+	if err := s.cache.Store(ctx, cacheKey, weatherData, s.hardTTL); err != nil {
 		// Log the error but don't fail the request
 		logger.Errorf("Failed to cache weather data: %v", err)
 	}
-	// Also warm ID-based cache
-	if err := s.cache.Set(ctx, weatherData.ID.String(), weatherData); err != nil {
-		logger.Errorf("Failed to cache weather data by ID: %v", err)
-	}
 
 	return weatherData, nil
 }
 
+// FindNearbyWeather returns stored observations within radiusKm of a point, nearest first
+func (s *WeatherService) FindNearbyWeather(ctx context.Context, q weather.NearbyQuery) ([]*weather.NearbyResult, error) {
+	q.Normalize()
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.FindNearby(ctx, q)
+}
+
+// GetLatestWeatherByCity returns the most recently stored observation for a city, from cache when possible
 func (s *WeatherService) GetLatestWeatherByCity(ctx context.Context, city string) (*weather.Weather, error) {
-	return s.repo.FindLatestByCity(ctx, city)
+	if cached, err := s.cache.GetLatest(ctx, city); err == nil {
+		return cached, nil
+	}
+
+	w, err := s.repo.FindLatestByCity(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.PutLatest(ctx, w); err != nil {
+		logger.Errorf("Failed to cache latest weather for %s: %v", city, err)
+	}
+	return w, nil
+}
+
+// ListWeather returns one page of weather records matching the given query
+func (s *WeatherService) ListWeather(ctx context.Context, q weather.Query) (*weather.Page, error) {
+	q.Normalize()
+
+	items, total, err := s.repo.FindByQuery(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &weather.Page{
+		Items:  items,
+		Total:  total,
+		Limit:  q.Limit,
+		Offset: q.Offset,
+	}, nil
 }
 
-func (s *WeatherService) GetAllWeather(ctx context.Context) ([]*weather.Weather, error) {
-	return s.repo.FindAll(ctx)
+// GetWeatherHistory returns bucketed min/max/avg aggregates for a city over a time range
+func (s *WeatherService) GetWeatherHistory(ctx context.Context, q weather.HistoryQuery) ([]*weather.HistoryBucket, error) {
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.FindHistory(ctx, q)
 }
 
 func (s *WeatherService) GetWeatherByID(ctx context.Context, id string) (*weather.Weather, error) {
-	var cachedWeather *weather.Weather
-	err := s.cache.Get(ctx, id, &cachedWeather)
-	if err == nil && cachedWeather != nil {
-		return cachedWeather, nil
+	if cached, err := s.cache.GetByID(ctx, id); err == nil {
+		return cached, nil
 	}
 
 	w, err := s.repo.FindByID(ctx, id)
@@ -105,7 +457,7 @@ func (s *WeatherService) GetWeatherByID(ctx context.Context, id string) (*weathe
 	}
 
 	if w != nil {
-		_ = s.cache.Set(ctx, id, w)
+		_ = s.cache.PutByID(ctx, w)
 	}
 
 	return w, nil
@@ -117,8 +469,8 @@ func (s *WeatherService) UpdateWeather(ctx context.Context, id string, update *w
 		return nil, err
 	}
 
-	// Keep track of old city/country for cache eviction if changed
-	oldCity, oldCountry := existing.City, existing.Country
+	// Keep the record as it was for cache eviction
+	before := *existing
 
 	if update.City != "" {
 		existing.City = update.City
@@ -144,22 +496,23 @@ func (s *WeatherService) UpdateWeather(ctx context.Context, id string, update *w
 		return nil, err
 	}
 
-	// Refresh ID-based cache
-	if err := s.cache.Set(ctx, id, existing); err != nil {
-		logger.Errorf("failed to update ID cache key %s: %v", id, err)
+	// Evict the entries of the old city/country, and the latest entry of the new one, which
+	// is reloaded from storage on its next lookup
+	if err := s.cache.Invalidate(ctx, &before); err != nil {
+		logger.Errorf("failed to evict cached weather %s: %v", id, err)
 	}
-
-	// Evict old city-country cache if city/country changed
-	if oldCity != existing.City || oldCountry != existing.Country {
-		oldKey := fmt.Sprintf("weather:%s:%s", oldCity, oldCountry)
-		if err := s.cache.Delete(ctx, oldKey); err != nil {
-			logger.Errorf("failed to delete cache key %s: %v", oldKey, err)
+	if before.City != existing.City {
+		if err := s.cache.Delete(ctx, interfaces.WeatherLatestKey(existing.City)); err != nil {
+			logger.Errorf("failed to evict latest weather for %s: %v", existing.City, err)
 		}
 	}
 
-	// Refresh new city-country cache
-	newKey := fmt.Sprintf("weather:%s:%s", existing.City, existing.Country)
-	if err := s.cache.Set(ctx, newKey, existing); err != nil {
+	// Refresh the ID and city-country entries
+	if err := s.cache.PutByID(ctx, existing); err != nil {
+		logger.Errorf("failed to update cached weather %s: %v", id, err)
+	}
+	newKey := interfaces.WeatherCityKey(existing.City, existing.Country)
+	if err := s.cache.Put(ctx, newKey, existing, s.hardTTL); err != nil {
 		logger.Errorf("failed to set cache key %s: %v", newKey, err)
 	}
 
@@ -178,17 +531,15 @@ func (s *WeatherService) DeleteWeather(ctx context.Context, id string) error {
 		return err
 	}
 
-	// Evict ID-based cache key
-	if err := s.cache.Delete(ctx, id); err != nil {
-		logger.Errorf("failed to delete cache key %s: %v", id, err)
-	}
-
-	// Evict city-country cache key if we have the data
+	// Evict the ID entry, and the city-country and latest entries if we have the data
+	var err error
 	if w != nil {
-		ccKey := fmt.Sprintf("weather:%s:%s", w.City, w.Country)
-		if err := s.cache.Delete(ctx, ccKey); err != nil {
-			logger.Errorf("failed to delete cache key %s: %v", ccKey, err)
-		}
+		err = s.cache.Invalidate(ctx, w)
+	} else {
+		err = s.cache.Delete(ctx, interfaces.WeatherIDKey(id))
+	}
+	if err != nil {
+		logger.Errorf("failed to evict cached weather %s: %v", id, err)
 	}
 
 	return nil
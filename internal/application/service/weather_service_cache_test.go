@@ -9,6 +9,8 @@ import (
 	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
 	"github.com/OmidRasouli/weather-api/internal/application/service"
 	"github.com/OmidRasouli/weather-api/internal/application/service/mocks"
+	"github.com/OmidRasouli/weather-api/internal/domain/weather"
+	infraCache "github.com/OmidRasouli/weather-api/internal/infrastructure/cache"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -18,7 +20,7 @@ func TestFetchAndStoreWeather_CacheError(t *testing.T) {
 	mockAPI := new(mocks.MockAPIClient)
 	mockCache := new(mocks.MockCache)
 
-	svc := service.NewWeatherService(mockRepo, mockAPI, mockCache)
+	svc := service.NewWeatherService(mockRepo, mockAPI, infraCache.NewWeatherCache(mockCache))
 
 	ctx := context.TODO()
 	apiResp := &interfaces.WeatherAPIResponse{
@@ -30,10 +32,11 @@ func TestFetchAndStoreWeather_CacheError(t *testing.T) {
 	}
 
 	cacheKey := mocks.CreateCacheKey("tehran", "IR")
-	mockCache.On("Get", ctx, cacheKey, mock.Anything).Return(fmt.Errorf("cache error"))
-	mockAPI.On("FetchWeatherData", ctx, "tehran", "IR").Return(apiResp, nil)
-	mockRepo.On("Save", ctx, mock.AnythingOfType("*weather.Weather")).Return(nil)
-	mockCache.On("Set", ctx, cacheKey, mock.Anything).Return(fmt.Errorf("cache write error"))
+	mockCache.On("GetWithTTL", mock.Anything, cacheKey, mock.Anything).Return(time.Duration(0), fmt.Errorf("cache error"))
+	mockAPI.On("FetchWeatherData", mock.Anything, "tehran", "IR").Return(apiResp, nil)
+	mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*weather.Weather")).Return(nil)
+	mockCache.On("SetWithTTL", mock.Anything, cacheKey, mock.Anything, service.DefaultHardTTL).Return(fmt.Errorf("cache write error"))
+	mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("cache write error"))
 
 	result, err := svc.FetchAndStoreWeather(ctx, "tehran", "IR")
 
@@ -44,3 +47,93 @@ func TestFetchAndStoreWeather_CacheError(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 	mockCache.AssertExpectations(t)
 }
+
+func TestFetchAndStoreWeather_FreshCacheHit(t *testing.T) {
+	mockRepo := new(mocks.MockWeatherRepository)
+	mockAPI := new(mocks.MockAPIClient)
+	mockCache := new(mocks.MockCache)
+	svc := service.NewWeatherService(mockRepo, mockAPI, infraCache.NewWeatherCache(mockCache)).WithCacheTTLs(5*time.Minute, 10*time.Minute)
+
+	ctx := context.TODO()
+	cacheKey := mocks.CreateCacheKey("tehran", "IR")
+	mockCache.On("GetWithTTL", mock.Anything, cacheKey, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(2).(**weather.Weather) = &weather.Weather{City: "tehran", Country: "IR", Temperature: 28.5}
+	}).Return(8*time.Minute, nil)
+
+	result, err := svc.FetchAndStoreWeather(ctx, "tehran", "IR")
+	svc.Wait()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 28.5, result.Temperature)
+	assert.Equal(t, 2*time.Minute, result.Age)
+	assert.False(t, result.Stale)
+	mockAPI.AssertNotCalled(t, "FetchWeatherData", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFetchAndStoreWeather_StaleWhileRevalidate(t *testing.T) {
+	mockRepo := new(mocks.MockWeatherRepository)
+	mockAPI := new(mocks.MockAPIClient)
+	mockCache := new(mocks.MockCache)
+	svc := service.NewWeatherService(mockRepo, mockAPI, infraCache.NewWeatherCache(mockCache)).WithCacheTTLs(5*time.Minute, 10*time.Minute)
+
+	ctx := context.TODO()
+	cacheKey := mocks.CreateCacheKey("tehran", "IR")
+	mockCache.On("GetWithTTL", mock.Anything, cacheKey, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(2).(**weather.Weather) = &weather.Weather{City: "tehran", Country: "IR", Temperature: 28.5}
+	}).Return(3*time.Minute, nil)
+
+	// The refresh is held until both requests have been answered
+	release := make(chan struct{})
+	mockAPI.On("FetchWeatherData", mock.Anything, "tehran", "IR").Run(func(mock.Arguments) {
+		<-release
+	}).Return(&interfaces.WeatherAPIResponse{Temperature: 31.0, FetchedAt: time.Now()}, nil)
+	mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*weather.Weather")).Return(nil)
+	mockCache.On("SetWithTTL", mock.Anything, cacheKey, mock.Anything, 10*time.Minute).Return(nil)
+	mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	for i := 0; i < 2; i++ {
+		result, err := svc.FetchAndStoreWeather(ctx, "tehran", "IR")
+		assert.NoError(t, err)
+		assert.Equal(t, 28.5, result.Temperature)
+		assert.Equal(t, 7*time.Minute, result.Age)
+		assert.True(t, result.Stale)
+	}
+	close(release)
+	svc.Wait()
+
+	// Both stale hits share one refresh, which stores and re-caches the new observation
+	mockAPI.AssertNumberOfCalls(t, "FetchWeatherData", 1)
+	mockRepo.AssertNumberOfCalls(t, "Save", 1)
+	mockCache.AssertCalled(t, "SetWithTTL", mock.Anything, cacheKey, mock.Anything, 10*time.Minute)
+}
+
+func TestRefreshWeather(t *testing.T) {
+	mockRepo := new(mocks.MockWeatherRepository)
+	mockAPI := new(mocks.MockAPIClient)
+	mockCache := new(mocks.MockCache)
+	svc := service.NewWeatherService(mockRepo, mockAPI, infraCache.NewWeatherCache(mockCache)).WithCacheTTLs(5*time.Minute, 10*time.Minute)
+
+	ctx := context.TODO()
+	cacheKey := mocks.CreateCacheKey("tehran", "IR")
+	cached := func(args mock.Arguments) {
+		*args.Get(2).(**weather.Weather) = &weather.Weather{City: "tehran", Country: "IR", Temperature: 28.5}
+	}
+	mockCache.On("GetWithTTL", mock.Anything, cacheKey, mock.Anything).Run(cached).Return(8*time.Minute, nil).Once()
+	mockCache.On("GetWithTTL", mock.Anything, cacheKey, mock.Anything).Run(cached).Return(3*time.Minute, nil)
+	mockAPI.On("FetchWeatherData", mock.Anything, "tehran", "IR").Return(&interfaces.WeatherAPIResponse{Temperature: 31.0, FetchedAt: time.Now()}, nil)
+	mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*weather.Weather")).Return(nil)
+	mockCache.On("SetWithTTL", mock.Anything, cacheKey, mock.Anything, 10*time.Minute).Return(nil)
+	mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	// A fresh entry is kept
+	result, err := svc.RefreshWeather(ctx, "tehran", "IR")
+	assert.NoError(t, err)
+	assert.Equal(t, 28.5, result.Temperature)
+	mockAPI.AssertNotCalled(t, "FetchWeatherData", mock.Anything, "tehran", "IR")
+
+	// A stale one is replaced synchronously
+	result, err = svc.RefreshWeather(ctx, "tehran", "IR")
+	assert.NoError(t, err)
+	assert.Equal(t, 31.0, result.Temperature)
+	mockAPI.AssertNumberOfCalls(t, "FetchWeatherData", 1)
+}
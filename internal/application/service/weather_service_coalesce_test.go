@@ -0,0 +1,234 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/application/service"
+	"github.com/OmidRasouli/weather-api/internal/application/service/mocks"
+	infraCache "github.com/OmidRasouli/weather-api/internal/infrastructure/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// memoryCache is a cache shared by the services under test; entries never age
+type memoryCache struct {
+	interfaces.Cache
+	mu      sync.Mutex
+	entries map[string][]byte
+	ttls    map[string]time.Duration
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{entries: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+}
+
+func (c *memoryCache) GetWithTTL(ctx context.Context, key string, dest interface{}) (time.Duration, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, ok := c.entries[key]
+	if !ok {
+		return 0, errors.New("key not found")
+	}
+	return c.ttls[key], json.Unmarshal(data, dest)
+}
+
+func (c *memoryCache) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = data
+	c.ttls[key] = ttl
+	return nil
+}
+
+func (c *memoryCache) Set(ctx context.Context, key string, value interface{}) error {
+	return c.SetWithTTL(ctx, key, value, 0)
+}
+
+// memoryLocker is a lock shared by the services under test; with err set every TryLock fails
+type memoryLocker struct {
+	mu    sync.Mutex
+	held  map[string]string
+	locks int
+	err   error
+}
+
+func newMemoryLocker() *memoryLocker {
+	return &memoryLocker{held: make(map[string]string)}
+}
+
+func (l *memoryLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return "", false, l.err
+	}
+	if _, ok := l.held[key]; ok {
+		return "", false, nil
+	}
+	l.locks++
+	token := time.Now().String()
+	l.held[key] = token
+	return token, true, nil
+}
+
+func (l *memoryLocker) Unlock(ctx context.Context, key string, token string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held[key] == token {
+		delete(l.held, key)
+	}
+	return nil
+}
+
+func TestFetchAndStoreWeather_CoalescesConcurrentMisses(t *testing.T) {
+	mockRepo := new(mocks.MockWeatherRepository)
+	mockAPI := new(mocks.MockAPIClient)
+	svc := service.NewWeatherService(mockRepo, mockAPI, infraCache.NewWeatherCache(newMemoryCache()))
+
+	// The upstream call is held until every request has been made
+	release := make(chan struct{})
+	mockAPI.On("FetchWeatherData", mock.Anything, "tehran", "IR").Run(func(mock.Arguments) {
+		<-release
+	}).Return(&interfaces.WeatherAPIResponse{Temperature: 31.0, FetchedAt: time.Now()}, nil)
+	mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*weather.Weather")).Return(nil)
+
+	var wg sync.WaitGroup
+	temperatures := make([]float64, 10)
+	for i := range temperatures {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, err := svc.FetchAndStoreWeather(context.Background(), "tehran", "IR")
+			if assert.NoError(t, err) {
+				temperatures[i] = result.Temperature
+			}
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for _, temperature := range temperatures {
+		assert.Equal(t, 31.0, temperature)
+	}
+	mockAPI.AssertNumberOfCalls(t, "FetchWeatherData", 1)
+	mockRepo.AssertNumberOfCalls(t, "Save", 1)
+}
+
+func TestFetchAndStoreWeather_SharedFetchOutlivesItsCaller(t *testing.T) {
+	mockRepo := new(mocks.MockWeatherRepository)
+	mockAPI := new(mocks.MockAPIClient)
+	svc := service.NewWeatherService(mockRepo, mockAPI, infraCache.NewWeatherCache(newMemoryCache()))
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var fetchCtx context.Context
+	mockAPI.On("FetchWeatherData", mock.Anything, "tehran", "IR").Run(func(args mock.Arguments) {
+		fetchCtx = args.Get(0).(context.Context)
+		close(started)
+		<-release
+	}).Return(&interfaces.WeatherAPIResponse{Temperature: 31.0, FetchedAt: time.Now()}, nil)
+	mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*weather.Weather")).Return(nil)
+
+	// The caller that starts the fetch gives up while it is running
+	first, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := svc.FetchAndStoreWeather(first, "tehran", "IR")
+		firstErr <- err
+	}()
+	<-started
+
+	second := make(chan float64, 1)
+	go func() {
+		result, err := svc.FetchAndStoreWeather(context.Background(), "tehran", "IR")
+		if assert.NoError(t, err) {
+			second <- result.Temperature
+		}
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-firstErr, context.Canceled)
+	assert.NoError(t, fetchCtx.Err())
+
+	// The fetch goes on for the caller still waiting, and is stored once
+	close(release)
+	assert.Equal(t, 31.0, <-second)
+	mockAPI.AssertNumberOfCalls(t, "FetchWeatherData", 1)
+	mockRepo.AssertNumberOfCalls(t, "Save", 1)
+}
+
+func TestFetchAndStoreWeather_CoalescesAcrossReplicas(t *testing.T) {
+	mockRepo := new(mocks.MockWeatherRepository)
+	cache := newMemoryCache()
+	locker := newMemoryLocker()
+
+	// Replica a holds the lock while its upstream call is in flight
+	started := make(chan struct{})
+	release := make(chan struct{})
+	apiA := new(mocks.MockAPIClient)
+	apiA.On("FetchWeatherData", mock.Anything, "tehran", "IR").Run(func(mock.Arguments) {
+		close(started)
+		<-release
+	}).Return(&interfaces.WeatherAPIResponse{Temperature: 31.0, FetchedAt: time.Now()}, nil)
+	apiB := new(mocks.MockAPIClient)
+	mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*weather.Weather")).Return(nil)
+
+	a := service.NewWeatherService(mockRepo, apiA, infraCache.NewWeatherCache(cache)).WithLocker(locker)
+	b := service.NewWeatherService(mockRepo, apiB, infraCache.NewWeatherCache(cache)).WithLocker(locker)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, err := a.FetchAndStoreWeather(context.Background(), "tehran", "IR")
+		assert.NoError(t, err)
+	}()
+	<-started
+
+	// Replica b waits for a's observation instead of fetching its own
+	go func() {
+		defer wg.Done()
+		result, err := b.FetchAndStoreWeather(context.Background(), "tehran", "IR")
+		if assert.NoError(t, err) {
+			assert.Equal(t, 31.0, result.Temperature)
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	apiA.AssertNumberOfCalls(t, "FetchWeatherData", 1)
+	apiB.AssertNotCalled(t, "FetchWeatherData", mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertNumberOfCalls(t, "Save", 1)
+	assert.Equal(t, 1, locker.locks)
+	assert.Empty(t, locker.held)
+}
+
+func TestFetchAndStoreWeather_LockerErrorStillFetches(t *testing.T) {
+	mockRepo := new(mocks.MockWeatherRepository)
+	mockAPI := new(mocks.MockAPIClient)
+	locker := newMemoryLocker()
+	locker.err = errors.New("connection refused")
+	svc := service.NewWeatherService(mockRepo, mockAPI, infraCache.NewWeatherCache(newMemoryCache())).WithLocker(locker)
+
+	mockAPI.On("FetchWeatherData", mock.Anything, "tehran", "IR").Return(&interfaces.WeatherAPIResponse{Temperature: 31.0, FetchedAt: time.Now()}, nil)
+	mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*weather.Weather")).Return(nil)
+
+	result, err := svc.FetchAndStoreWeather(context.Background(), "tehran", "IR")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 31.0, result.Temperature)
+	mockAPI.AssertNumberOfCalls(t, "FetchWeatherData", 1)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/OmidRasouli/weather-api/internal/application/service"
 	"github.com/OmidRasouli/weather-api/internal/application/service/mocks"
 	"github.com/OmidRasouli/weather-api/internal/domain/weather"
+	infraCache "github.com/OmidRasouli/weather-api/internal/infrastructure/cache"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -51,11 +52,11 @@ func TestWeatherService_GetWeatherByID(t *testing.T) {
 					FetchedAt:   now,
 				}
 				// Cache miss (return an error to indicate miss), then repo hit, then cache set
-				f.cache.On("Get", a.ctx, a.id.String(), mock.Anything).Return(fmt.Errorf("cache miss"))
+				f.cache.On("GetWithTTL", a.ctx, "weather:id:"+a.id.String(), mock.Anything).Return(time.Duration(0), fmt.Errorf("cache miss"))
 				f.repo.On("FindByID", a.ctx, mock.MatchedBy(func(u string) bool {
 					return u == a.id.String()
 				})).Return(expected, nil)
-				f.cache.On("Set", a.ctx, a.id.String(), mock.Anything, mock.Anything).Return(nil)
+				f.cache.On("Set", a.ctx, "weather:id:"+a.id.String(), mock.Anything).Return(nil)
 			},
 			wantErr: false,
 			expected: &weather.Weather{
@@ -77,7 +78,7 @@ func TestWeatherService_GetWeatherByID(t *testing.T) {
 			},
 			setupMock: func(f fields, a args) {
 				// Cache miss (return an error), then repo not found
-				f.cache.On("Get", a.ctx, a.id.String(), mock.Anything).Return(fmt.Errorf("cache miss"))
+				f.cache.On("GetWithTTL", a.ctx, "weather:id:"+a.id.String(), mock.Anything).Return(time.Duration(0), fmt.Errorf("cache miss"))
 				f.repo.On("FindByID", a.ctx, mock.MatchedBy(func(u string) bool {
 					return u == a.id.String()
 				})).Return((*weather.Weather)(nil), fmt.Errorf("not found"))
@@ -97,7 +98,7 @@ func TestWeatherService_GetWeatherByID(t *testing.T) {
 			if tt.setupMock != nil {
 				tt.setupMock(f, tt.args)
 			}
-			service := service.NewWeatherService(repo, api, cache)
+			service := service.NewWeatherService(repo, api, infraCache.NewWeatherCache(cache))
 			got, err := service.GetWeatherByID(tt.args.ctx, tt.args.id.String())
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -121,3 +122,81 @@ func TestWeatherService_GetWeatherByID(t *testing.T) {
 		})
 	}
 }
+
+func TestWeatherService_ListWeather(t *testing.T) {
+	repo := new(mocks.MockWeatherRepository)
+	api := new(mocks.MockAPIClient)
+	cache := new(mocks.MockCache)
+	svc := service.NewWeatherService(repo, api, infraCache.NewWeatherCache(cache))
+
+	ctx := context.TODO()
+	items := []*weather.Weather{
+		{ID: uuid.New(), City: "tehran", Country: "IR", Temperature: 30.5},
+	}
+
+	// An empty query should be normalized to the default sort and page size
+	expectedQuery := weather.Query{
+		City:   "tehran",
+		SortBy: weather.DefaultSortField,
+		Limit:  weather.DefaultPageSize,
+	}
+	repo.On("FindByQuery", ctx, expectedQuery).Return(items, int64(1), nil)
+
+	page, err := svc.ListWeather(ctx, weather.Query{City: "tehran", Limit: 0})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+	assert.Equal(t, weather.DefaultPageSize, page.Limit)
+	assert.Equal(t, 0, page.Offset)
+	assert.Len(t, page.Items, 1)
+	repo.AssertExpectations(t)
+}
+
+func TestWeatherService_ListWeather_ClampsLimit(t *testing.T) {
+	repo := new(mocks.MockWeatherRepository)
+	svc := service.NewWeatherService(repo, new(mocks.MockAPIClient), infraCache.NewWeatherCache(new(mocks.MockCache)))
+
+	ctx := context.TODO()
+	repo.On("FindByQuery", ctx, mock.MatchedBy(func(q weather.Query) bool {
+		return q.Limit == weather.MaxPageSize && q.SortBy == "humidity" && q.Descending
+	})).Return([]*weather.Weather{}, int64(0), nil)
+
+	page, err := svc.ListWeather(ctx, weather.Query{SortBy: "humidity", Descending: true, Limit: 1000})
+
+	assert.NoError(t, err)
+	assert.Equal(t, weather.MaxPageSize, page.Limit)
+	repo.AssertExpectations(t)
+}
+
+func TestWeatherService_GetWeatherHistory(t *testing.T) {
+	repo := new(mocks.MockWeatherRepository)
+	svc := service.NewWeatherService(repo, new(mocks.MockAPIClient), infraCache.NewWeatherCache(new(mocks.MockCache)))
+
+	ctx := context.TODO()
+	to := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	q := weather.HistoryQuery{City: "tehran", From: to.Add(-24 * time.Hour), To: to, Bucket: time.Hour}
+	buckets := []*weather.HistoryBucket{
+		{Start: q.From, Samples: 2, MinTemperature: 10, MaxTemperature: 12, AvgTemperature: 11},
+	}
+	repo.On("FindHistory", ctx, q).Return(buckets, nil)
+
+	got, err := svc.GetWeatherHistory(ctx, q)
+
+	assert.NoError(t, err)
+	assert.Equal(t, buckets, got)
+	repo.AssertExpectations(t)
+}
+
+func TestWeatherService_GetWeatherHistory_TooManyBuckets(t *testing.T) {
+	repo := new(mocks.MockWeatherRepository)
+	svc := service.NewWeatherService(repo, new(mocks.MockAPIClient), infraCache.NewWeatherCache(new(mocks.MockCache)))
+
+	to := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	q := weather.HistoryQuery{City: "tehran", From: to.AddDate(-1, 0, 0), To: to, Bucket: time.Minute}
+
+	got, err := svc.GetWeatherHistory(context.TODO(), q)
+
+	assert.ErrorIs(t, err, weather.ErrInvalidHistoryQuery)
+	assert.Nil(t, got)
+	repo.AssertNotCalled(t, "FindHistory", mock.Anything, mock.Anything)
+}
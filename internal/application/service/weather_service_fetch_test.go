@@ -10,6 +10,7 @@ import (
 	"github.com/OmidRasouli/weather-api/internal/application/service"
 	"github.com/OmidRasouli/weather-api/internal/application/service/mocks"
 	"github.com/OmidRasouli/weather-api/internal/domain/weather"
+	infraCache "github.com/OmidRasouli/weather-api/internal/infrastructure/cache"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -19,7 +20,7 @@ func TestFetchAndStoreWeather_CacheHit(t *testing.T) {
 	mockRepo := new(mocks.MockWeatherRepository)
 	mockAPI := new(mocks.MockAPIClient)
 	mockCache := new(mocks.MockCache)
-	service := service.NewWeatherService(mockRepo, mockAPI, mockCache)
+	service := service.NewWeatherService(mockRepo, mockAPI, infraCache.NewWeatherCache(mockCache))
 
 	ctx := context.TODO()
 	cacheKey := mocks.CreateCacheKey("tehran", "IR")
@@ -36,10 +37,10 @@ func TestFetchAndStoreWeather_CacheHit(t *testing.T) {
 	}
 
 	// Setup Cache to return the cached entry
-	mockCache.On("Get", ctx, cacheKey, mock.Anything).Run(func(args mock.Arguments) {
+	mockCache.On("GetWithTTL", mock.Anything, cacheKey, mock.Anything).Run(func(args mock.Arguments) {
 		dest := args.Get(2).(**weather.Weather)
 		*dest = cachedWeather
-	}).Return(nil)
+	}).Return(9*time.Minute, nil)
 
 	// Execute
 	result, err := service.FetchAndStoreWeather(ctx, "tehran", "IR")
@@ -60,7 +61,7 @@ func TestFetchAndStoreWeather_Success(t *testing.T) {
 	mockRepo := new(mocks.MockWeatherRepository)
 	mockAPI := new(mocks.MockAPIClient)
 	mockCache := new(mocks.MockCache)
-	service := service.NewWeatherService(mockRepo, mockAPI, mockCache)
+	service := service.NewWeatherService(mockRepo, mockAPI, infraCache.NewWeatherCache(mockCache))
 
 	ctx := context.TODO()
 	apiResp := &interfaces.WeatherAPIResponse{
@@ -72,10 +73,11 @@ func TestFetchAndStoreWeather_Success(t *testing.T) {
 	}
 
 	cacheKey := mocks.CreateCacheKey("tehran", "IR")
-	mockCache.On("Get", ctx, cacheKey, mock.Anything).Return(fmt.Errorf("cache miss"))
-	mockAPI.On("FetchWeatherData", ctx, "tehran", "IR").Return(apiResp, nil)
-	mockRepo.On("Save", ctx, mock.AnythingOfType("*weather.Weather")).Return(nil)
-	mockCache.On("Set", ctx, cacheKey, mock.Anything).Return(nil)
+	mockCache.On("GetWithTTL", mock.Anything, cacheKey, mock.Anything).Return(time.Duration(0), fmt.Errorf("cache miss"))
+	mockAPI.On("FetchWeatherData", mock.Anything, "tehran", "IR").Return(apiResp, nil)
+	mockRepo.On("Save", mock.Anything, mock.AnythingOfType("*weather.Weather")).Return(nil)
+	mockCache.On("SetWithTTL", mock.Anything, cacheKey, mock.Anything, 10*time.Minute).Return(nil)
+	mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	// Execute
 	result, err := service.FetchAndStoreWeather(ctx, "tehran", "IR")
@@ -89,3 +91,171 @@ func TestFetchAndStoreWeather_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 	mockCache.AssertExpectations(t)
 }
+
+func TestFetchAndStoreWeatherByCoordinates_Success(t *testing.T) {
+	mockRepo := new(mocks.MockWeatherRepository)
+	mockAPI := new(mocks.MockAPIClient)
+	mockCache := new(mocks.MockCache)
+	service := service.NewWeatherService(mockRepo, mockAPI, infraCache.NewWeatherCache(mockCache))
+
+	ctx := context.TODO()
+	lat, lon := 35.6892, 51.389
+	apiLat, apiLon := 35.69, 51.39
+	apiResp := &interfaces.WeatherAPIResponse{
+		City:        "Tehran",
+		Country:     "IR",
+		Latitude:    &apiLat,
+		Longitude:   &apiLon,
+		Temperature: 30.5,
+		FeelsLike:   29.8,
+		Description: "sunny",
+		Pressure:    1012,
+		WindGust:    4.2,
+		FetchedAt:   time.Now(),
+		Provider:    "openweather",
+	}
+
+	mockCache.On("GetWithTTL", mock.Anything, "weather:coord:35.69:51.39", mock.Anything).Return(time.Duration(0), fmt.Errorf("cache miss"))
+	mockAPI.On("FetchWeatherByCoordinates", mock.Anything, lat, lon).Return(apiResp, nil)
+	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(w *weather.Weather) bool {
+		return w.City == "Tehran" && *w.Latitude == apiLat && *w.Longitude == apiLon && w.Provider == "openweather" &&
+			w.FeelsLike == 29.8 && w.Pressure == 1012 && w.WindGust == 4.2
+	})).Return(nil)
+	mockCache.On("SetWithTTL", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	result, err := service.FetchAndStoreWeatherByCoordinates(ctx, lat, lon)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Tehran", result.City)
+	assert.Equal(t, apiLat, *result.Latitude)
+
+	mockAPI.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+	mockCache.AssertCalled(t, "SetWithTTL", mock.Anything, "weather:coord:35.69:51.39", mock.Anything, 10*time.Minute)
+}
+
+func TestFindNearbyWeather_InvalidRadius(t *testing.T) {
+	mockRepo := new(mocks.MockWeatherRepository)
+	service := service.NewWeatherService(mockRepo, new(mocks.MockAPIClient), infraCache.NewWeatherCache(new(mocks.MockCache)))
+
+	result, err := service.FindNearbyWeather(context.TODO(), weather.NearbyQuery{Latitude: 35.7, Longitude: 51.4, RadiusKm: 5000})
+
+	assert.ErrorIs(t, err, weather.ErrInvalidNearbyQuery)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "FindNearby", mock.Anything, mock.Anything)
+}
+
+type recordingObserver struct {
+	stored []*weather.Weather
+}
+
+func (o *recordingObserver) OnWeatherStored(_ context.Context, w *weather.Weather) {
+	o.stored = append(o.stored, w)
+}
+
+func TestFetchAndStoreWeatherByCoordinates_NotifiesObservers(t *testing.T) {
+	mockRepo := new(mocks.MockWeatherRepository)
+	mockAPI := new(mocks.MockAPIClient)
+	mockCache := new(mocks.MockCache)
+	observer := &recordingObserver{}
+	service := service.NewWeatherService(mockRepo, mockAPI, infraCache.NewWeatherCache(mockCache)).WithObserver(observer)
+
+	ctx := context.TODO()
+	apiResp := &interfaces.WeatherAPIResponse{City: "Berlin", Country: "DE", Temperature: 36.5, FetchedAt: time.Now()}
+
+	mockCache.On("GetWithTTL", mock.Anything, mock.Anything, mock.Anything).Return(time.Duration(0), fmt.Errorf("cache miss"))
+	mockAPI.On("FetchWeatherByCoordinates", mock.Anything, 52.52, 13.41).Return(apiResp, nil)
+	mockRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	mockCache.On("SetWithTTL", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockCache.On("Set", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	result, err := service.FetchAndStoreWeatherByCoordinates(ctx, 52.52, 13.41)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*weather.Weather{result.Weather}, observer.stored)
+}
+
+func TestFetchAndStoreWeather_ServesStoredWhenCircuitOpen(t *testing.T) {
+	mockRepo := new(mocks.MockWeatherRepository)
+	mockAPI := new(mocks.MockAPIClient)
+	mockCache := new(mocks.MockCache)
+	service := service.NewWeatherService(mockRepo, mockAPI, infraCache.NewWeatherCache(mockCache))
+
+	ctx := context.TODO()
+	stored := &weather.Weather{City: "tehran", Country: "IR", Temperature: 29.0, FetchedAt: time.Now().Add(-time.Hour)}
+
+	mockCache.On("GetWithTTL", mock.Anything, mocks.CreateCacheKey("tehran", "IR"), mock.Anything).Return(time.Duration(0), fmt.Errorf("cache miss"))
+	mockAPI.On("FetchWeatherData", mock.Anything, "tehran", "IR").
+		Return((*interfaces.WeatherAPIResponse)(nil), fmt.Errorf("all weather providers failed: %w", interfaces.ErrCircuitOpen))
+	latest := weather.Query{City: "tehran", Country: "IR", SortBy: "fetched_at", Descending: true, Limit: 1}
+	mockRepo.On("FindByQuery", ctx, latest).Return([]*weather.Weather{stored}, int64(1), nil)
+
+	result, err := service.FetchAndStoreWeather(ctx, "tehran", "IR")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 29.0, result.Temperature)
+	assert.True(t, result.Stale)
+	mockRepo.AssertNotCalled(t, "FindLatestByCity", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	mockCache.AssertNotCalled(t, "SetWithTTL", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFetchAndStoreWeather_CircuitOpenWithoutStoredObservationInCountry(t *testing.T) {
+	mockRepo := new(mocks.MockWeatherRepository)
+	mockAPI := new(mocks.MockAPIClient)
+	mockCache := new(mocks.MockCache)
+	service := service.NewWeatherService(mockRepo, mockAPI, infraCache.NewWeatherCache(mockCache))
+
+	ctx := context.TODO()
+	mockCache.On("GetWithTTL", mock.Anything, mocks.CreateCacheKey("paris", "FR"), mock.Anything).Return(time.Duration(0), fmt.Errorf("cache miss"))
+	mockAPI.On("FetchWeatherData", mock.Anything, "paris", "FR").Return((*interfaces.WeatherAPIResponse)(nil), interfaces.ErrCircuitOpen)
+	// Only Paris, US has been stored, which must not be served for Paris, FR
+	latest := weather.Query{City: "paris", Country: "FR", SortBy: "fetched_at", Descending: true, Limit: 1}
+	mockRepo.On("FindByQuery", ctx, latest).Return([]*weather.Weather{}, int64(0), nil)
+
+	result, err := service.FetchAndStoreWeather(ctx, "paris", "FR")
+
+	assert.ErrorIs(t, err, interfaces.ErrCircuitOpen)
+	assert.Nil(t, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestFetchAndStoreWeather_OtherErrorsAreReturned(t *testing.T) {
+	mockRepo := new(mocks.MockWeatherRepository)
+	mockAPI := new(mocks.MockAPIClient)
+	mockCache := new(mocks.MockCache)
+	service := service.NewWeatherService(mockRepo, mockAPI, infraCache.NewWeatherCache(mockCache))
+
+	ctx := context.TODO()
+	mockCache.On("GetWithTTL", mock.Anything, mocks.CreateCacheKey("tehran", "IR"), mock.Anything).Return(time.Duration(0), fmt.Errorf("cache miss"))
+	mockAPI.On("FetchWeatherData", mock.Anything, "tehran", "IR").Return((*interfaces.WeatherAPIResponse)(nil), fmt.Errorf("API returned status 500"))
+
+	result, err := service.FetchAndStoreWeather(ctx, "tehran", "IR")
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	mockRepo.AssertNotCalled(t, "FindByQuery", mock.Anything, mock.Anything)
+}
+
+func TestFetchAndStoreWeatherByCoordinates_ServesNearestWhenCircuitOpen(t *testing.T) {
+	mockRepo := new(mocks.MockWeatherRepository)
+	mockAPI := new(mocks.MockAPIClient)
+	mockCache := new(mocks.MockCache)
+	service := service.NewWeatherService(mockRepo, mockAPI, infraCache.NewWeatherCache(mockCache))
+
+	ctx := context.TODO()
+	lat, lon := 35.6892, 51.389
+	stored := &weather.Weather{City: "Tehran", Latitude: &lat, Longitude: &lon, Temperature: 31.0}
+
+	mockCache.On("GetWithTTL", mock.Anything, "weather:coord:35.69:51.39", mock.Anything).Return(time.Duration(0), fmt.Errorf("cache miss"))
+	mockAPI.On("FetchWeatherByCoordinates", mock.Anything, lat, lon).Return(nil, interfaces.ErrCircuitOpen)
+	mockRepo.On("FindNearby", ctx, weather.NearbyQuery{Latitude: lat, Longitude: lon, RadiusKm: 2, Limit: 1}).
+		Return([]*weather.NearbyResult{{Weather: stored, DistanceKm: 0.3}}, nil)
+
+	result, err := service.FetchAndStoreWeatherByCoordinates(ctx, lat, lon)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Tehran", result.City)
+	assert.True(t, result.Stale)
+}
@@ -0,0 +1,95 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/OmidRasouli/weather-api/config"
+	"github.com/OmidRasouli/weather-api/internal/application/service"
+	"github.com/OmidRasouli/weather-api/internal/application/service/mocks"
+	"github.com/OmidRasouli/weather-api/internal/domain/weather"
+	"github.com/OmidRasouli/weather-api/internal/infrastructure/cache"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newRedisCache starts an in-process Redis and returns a cache connected to it
+func newRedisCache(t *testing.T) *cache.Redis {
+	s := miniredis.RunT(t)
+	port, _ := strconv.Atoi(s.Port())
+	rd := cache.NewRedisClient(config.RedisConfig{Host: s.Host(), Port: port, TTL: 600})
+	t.Cleanup(func() { _ = rd.Close() })
+	return rd
+}
+
+func TestWeatherService_GetLatestWeatherByCity_Cached(t *testing.T) {
+	repo := new(mocks.MockWeatherRepository)
+	svc := service.NewWeatherService(repo, new(mocks.MockAPIClient), cache.NewWeatherCache(newRedisCache(t)))
+	ctx := context.Background()
+
+	stored := &weather.Weather{ID: uuid.New(), City: "tehran", Country: "IR", Temperature: 30.5}
+	repo.On("FindLatestByCity", mock.Anything, "tehran").Return(stored, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		got, err := svc.GetLatestWeatherByCity(ctx, "tehran")
+		assert.NoError(t, err)
+		assert.Equal(t, stored.ID, got.ID)
+		assert.Equal(t, 30.5, got.Temperature)
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestWeatherService_UpdateWeather_EvictsCachedEntries(t *testing.T) {
+	repo := new(mocks.MockWeatherRepository)
+	svc := service.NewWeatherService(repo, new(mocks.MockAPIClient), cache.NewWeatherCache(newRedisCache(t)))
+	ctx := context.Background()
+
+	id := uuid.New()
+	stored := &weather.Weather{ID: id, City: "tehran", Country: "IR", Temperature: 30.5}
+	repo.On("FindLatestByCity", mock.Anything, "tehran").Return(stored, nil).Once()
+	_, err := svc.GetLatestWeatherByCity(ctx, "tehran")
+	assert.NoError(t, err)
+
+	current := *stored
+	repo.On("FindByID", mock.Anything, id.String()).Return(&current, nil).Once()
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+	_, err = svc.UpdateWeather(ctx, id.String(), &weather.Weather{Temperature: 12})
+	assert.NoError(t, err)
+
+	// The latest entry was evicted, so the next lookup reads storage again
+	updated := &weather.Weather{ID: id, City: "tehran", Country: "IR", Temperature: 12}
+	repo.On("FindLatestByCity", mock.Anything, "tehran").Return(updated, nil).Once()
+	got, err := svc.GetLatestWeatherByCity(ctx, "tehran")
+	assert.NoError(t, err)
+	assert.Equal(t, 12.0, got.Temperature)
+
+	// The ID entry holds the update
+	got, err = svc.GetWeatherByID(ctx, id.String())
+	assert.NoError(t, err)
+	assert.Equal(t, 12.0, got.Temperature)
+	repo.AssertExpectations(t)
+}
+
+func TestWeatherService_DeleteWeather_EvictsCachedEntries(t *testing.T) {
+	repo := new(mocks.MockWeatherRepository)
+	svc := service.NewWeatherService(repo, new(mocks.MockAPIClient), cache.NewWeatherCache(newRedisCache(t)))
+	ctx := context.Background()
+
+	id := uuid.New()
+	stored := &weather.Weather{ID: id, City: "tehran", Country: "IR"}
+	repo.On("FindByID", mock.Anything, id.String()).Return(stored, nil).Twice()
+	_, err := svc.GetWeatherByID(ctx, id.String())
+	assert.NoError(t, err)
+
+	repo.On("Delete", mock.Anything, id.String()).Return(nil).Once()
+	assert.NoError(t, svc.DeleteWeather(ctx, id.String()))
+
+	repo.On("FindByID", mock.Anything, id.String()).Return((*weather.Weather)(nil), errors.New("record not found")).Once()
+	_, err = svc.GetWeatherByID(ctx, id.String())
+	assert.Error(t, err)
+	repo.AssertExpectations(t)
+}
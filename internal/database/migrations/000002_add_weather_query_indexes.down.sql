@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_weather_fetched_at;
+DROP INDEX IF EXISTS idx_weather_city_country;
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_weather_city_country ON weather (city, country);
+CREATE INDEX IF NOT EXISTS idx_weather_fetched_at ON weather (fetched_at);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS forecast (
+    id UUID PRIMARY KEY,
+    city TEXT NOT NULL,
+    country TEXT NOT NULL,
+    forecast_at TIMESTAMP NOT NULL,
+    temperature DOUBLE PRECISION,
+    description TEXT,
+    humidity INTEGER,
+    wind_speed DOUBLE PRECISION,
+    fetched_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_forecast_city_country_forecast_at ON forecast (city, country, forecast_at);
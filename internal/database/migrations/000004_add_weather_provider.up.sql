@@ -0,0 +1 @@
+ALTER TABLE weather ADD COLUMN IF NOT EXISTS provider TEXT NOT NULL DEFAULT 'openweather';
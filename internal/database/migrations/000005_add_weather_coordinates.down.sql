@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_weather_latitude_longitude;
+
+ALTER TABLE weather DROP COLUMN IF EXISTS longitude;
+ALTER TABLE weather DROP COLUMN IF EXISTS latitude;
@@ -0,0 +1,4 @@
+ALTER TABLE weather ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION;
+ALTER TABLE weather ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION;
+
+CREATE INDEX IF NOT EXISTS idx_weather_latitude_longitude ON weather (latitude, longitude);
@@ -0,0 +1,14 @@
+ALTER TABLE weather DROP COLUMN IF EXISTS sunset;
+ALTER TABLE weather DROP COLUMN IF EXISTS sunrise;
+ALTER TABLE weather DROP COLUMN IF EXISTS snow;
+ALTER TABLE weather DROP COLUMN IF EXISTS rain;
+ALTER TABLE weather DROP COLUMN IF EXISTS wind_gust;
+ALTER TABLE weather DROP COLUMN IF EXISTS wind_deg;
+ALTER TABLE weather DROP COLUMN IF EXISTS clouds;
+ALTER TABLE weather DROP COLUMN IF EXISTS visibility;
+ALTER TABLE weather DROP COLUMN IF EXISTS pressure;
+ALTER TABLE weather DROP COLUMN IF EXISTS icon;
+ALTER TABLE weather DROP COLUMN IF EXISTS condition_id;
+ALTER TABLE weather DROP COLUMN IF EXISTS temp_max;
+ALTER TABLE weather DROP COLUMN IF EXISTS temp_min;
+ALTER TABLE weather DROP COLUMN IF EXISTS feels_like;
@@ -0,0 +1,14 @@
+ALTER TABLE weather ADD COLUMN IF NOT EXISTS feels_like DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE weather ADD COLUMN IF NOT EXISTS temp_min DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE weather ADD COLUMN IF NOT EXISTS temp_max DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE weather ADD COLUMN IF NOT EXISTS condition_id INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE weather ADD COLUMN IF NOT EXISTS icon TEXT NOT NULL DEFAULT '';
+ALTER TABLE weather ADD COLUMN IF NOT EXISTS pressure INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE weather ADD COLUMN IF NOT EXISTS visibility INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE weather ADD COLUMN IF NOT EXISTS clouds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE weather ADD COLUMN IF NOT EXISTS wind_deg INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE weather ADD COLUMN IF NOT EXISTS wind_gust DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE weather ADD COLUMN IF NOT EXISTS rain DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE weather ADD COLUMN IF NOT EXISTS snow DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE weather ADD COLUMN IF NOT EXISTS sunrise TIMESTAMP;
+ALTER TABLE weather ADD COLUMN IF NOT EXISTS sunset TIMESTAMP;
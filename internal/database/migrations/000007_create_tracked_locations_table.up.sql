@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS tracked_locations (
+    id UUID PRIMARY KEY,
+    city TEXT NOT NULL,
+    country TEXT NOT NULL,
+    refresh_interval_seconds BIGINT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_at TIMESTAMP NOT NULL,
+    last_refreshed_at TIMESTAMP,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tracked_locations_city_country ON tracked_locations (city, country);
+CREATE INDEX IF NOT EXISTS idx_tracked_locations_next_run_at ON tracked_locations (next_run_at) WHERE enabled;
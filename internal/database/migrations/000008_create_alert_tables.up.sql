@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL DEFAULT '',
+    city TEXT NOT NULL DEFAULT '',
+    country TEXT NOT NULL DEFAULT '',
+    field TEXT NOT NULL,
+    operator TEXT NOT NULL,
+    threshold DOUBLE PRECISION NOT NULL,
+    webhook_url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_rules_location ON alert_rules (lower(city), upper(country)) WHERE enabled;
+
+CREATE TABLE IF NOT EXISTS alert_deliveries (
+    id UUID PRIMARY KEY,
+    rule_id UUID NOT NULL REFERENCES alert_rules (id) ON DELETE CASCADE,
+    weather_id UUID NOT NULL,
+    url TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    succeeded BOOLEAN NOT NULL DEFAULT FALSE,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_deliveries_rule_id_created_at ON alert_deliveries (rule_id, created_at DESC);
@@ -0,0 +1,6 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'viewer';
+
+-- Before roles every account could modify data, so existing non-admins become editors
+UPDATE users SET role = CASE WHEN is_admin THEN 'admin' ELSE 'editor' END;
+
+ALTER TABLE users DROP COLUMN IF EXISTS is_admin;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_users_identity;
+
+ALTER TABLE users DROP COLUMN IF EXISTS identity_subject;
+ALTER TABLE users DROP COLUMN IF EXISTS identity_issuer;
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS identity_issuer TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS identity_subject TEXT NOT NULL DEFAULT '';
+
+-- Each external identity is linked to at most one account; local accounts have no identity
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_identity ON users (identity_issuer, identity_subject) WHERE identity_issuer <> '';
@@ -0,0 +1,190 @@
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/weather"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidRule is returned when an alert rule fails validation.
+var ErrInvalidRule = errors.New("invalid alert rule")
+
+// Operator compares an observed value against a rule threshold.
+type Operator string
+
+const (
+	GreaterThan        Operator = ">"
+	GreaterThanOrEqual Operator = ">="
+	LessThan           Operator = "<"
+	LessThanOrEqual    Operator = "<="
+	Equal              Operator = "=="
+	NotEqual           Operator = "!="
+)
+
+// Operators lists the supported comparison operators.
+var Operators = []Operator{GreaterThan, GreaterThanOrEqual, LessThan, LessThanOrEqual, Equal, NotEqual}
+
+// Compare reports whether value op threshold holds.
+func (o Operator) Compare(value float64, threshold float64) bool {
+	switch o {
+	case GreaterThan:
+		return value > threshold
+	case GreaterThanOrEqual:
+		return value >= threshold
+	case LessThan:
+		return value < threshold
+	case LessThanOrEqual:
+		return value <= threshold
+	case Equal:
+		return value == threshold
+	case NotEqual:
+		return value != threshold
+	default:
+		return false
+	}
+}
+
+func (o Operator) valid() bool {
+	for _, op := range Operators {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldValues extracts the numeric observation fields a rule can test, in metric units
+var fieldValues = map[string]func(w *weather.Weather) float64{
+	"temperature": func(w *weather.Weather) float64 { return w.Temperature },
+	"feels_like":  func(w *weather.Weather) float64 { return w.FeelsLike },
+	"temp_min":    func(w *weather.Weather) float64 { return w.TempMin },
+	"temp_max":    func(w *weather.Weather) float64 { return w.TempMax },
+	"humidity":    func(w *weather.Weather) float64 { return float64(w.Humidity) },
+	"pressure":    func(w *weather.Weather) float64 { return float64(w.Pressure) },
+	"visibility":  func(w *weather.Weather) float64 { return float64(w.Visibility) },
+	"clouds":      func(w *weather.Weather) float64 { return float64(w.Clouds) },
+	"wind_speed":  func(w *weather.Weather) float64 { return w.WindSpeed },
+	"wind_deg":    func(w *weather.Weather) float64 { return float64(w.WindDeg) },
+	"wind_gust":   func(w *weather.Weather) float64 { return w.WindGust },
+	"rain":        func(w *weather.Weather) float64 { return w.Rain },
+	"snow":        func(w *weather.Weather) float64 { return w.Snow },
+}
+
+// IsField reports whether field can be used in a rule.
+func IsField(field string) bool {
+	_, ok := fieldValues[field]
+	return ok
+}
+
+// Rule triggers a webhook when a stored observation for a location satisfies Field Operator Threshold.
+// An empty City or Country matches any value.
+type Rule struct {
+	ID         uuid.UUID
+	Name       string
+	City       string
+	Country    string
+	Field      string
+	Operator   Operator
+	Threshold  float64
+	WebhookURL string
+	Secret     string // HMAC-SHA256 key used to sign webhook payloads
+	Enabled    bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Validate checks the condition and webhook target of the rule.
+func (r *Rule) Validate() error {
+	if !IsField(r.Field) {
+		return fmt.Errorf("%w: unsupported field %q", ErrInvalidRule, r.Field)
+	}
+	if !r.Operator.valid() {
+		return fmt.Errorf("%w: unsupported operator %q", ErrInvalidRule, r.Operator)
+	}
+	u, err := url.Parse(r.WebhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("%w: webhook URL must be an absolute http(s) URL", ErrInvalidRule)
+	}
+	if !PublicHost(u.Hostname()) {
+		return fmt.Errorf("%w: webhook URL must not point to a local or private address", ErrInvalidRule)
+	}
+	if r.Secret == "" {
+		return fmt.Errorf("%w: secret is required", ErrInvalidRule)
+	}
+	return nil
+}
+
+// nonPublicPrefixes are the ranges refused besides those netip classifies as loopback, private,
+// link-local, multicast or unspecified: "this network", carrier-grade NAT and benchmarking.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+}
+
+// PublicAddress reports whether webhooks may be delivered to ip. Loopback, private,
+// link-local (including cloud metadata at 169.254.169.254) and other internal addresses
+// are refused, so that rules cannot reach the service's own network.
+func PublicAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// PublicHost reports whether a webhook host may be public without resolving it: localhost
+// names and literal non-public addresses are refused. Names are checked again once resolved.
+func PublicHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return PublicAddress(ip)
+	}
+	return true
+}
+
+// Matches reports whether w satisfies the rule's condition. Location filtering is done by the repository.
+func (r *Rule) Matches(w *weather.Weather) bool {
+	value, ok := fieldValues[r.Field]
+	if !ok {
+		return false
+	}
+	return r.Operator.Compare(value(w), r.Threshold)
+}
+
+// Value returns the observed value of the rule's field.
+func (r *Rule) Value(w *weather.Weather) float64 {
+	if value, ok := fieldValues[r.Field]; ok {
+		return value(w)
+	}
+	return 0
+}
+
+// Delivery records the outcome of sending one notification to a rule's webhook.
+type Delivery struct {
+	ID         uuid.UUID
+	RuleID     uuid.UUID
+	WeatherID  uuid.UUID
+	URL        string
+	Attempts   int
+	StatusCode int
+	Succeeded  bool
+	Error      string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
@@ -0,0 +1,120 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+// keyPrefix marks a string as an API key of this service, so leaked keys are easy to spot
+const keyPrefix = "wapi"
+
+const (
+	// ScopeRead allows the key to read weather data.
+	ScopeRead = "read"
+	// ScopeWrite allows the key to fetch, store and update weather data.
+	ScopeWrite = "write"
+)
+
+var (
+	// ErrInvalidKey is returned when a key fails validation.
+	ErrInvalidKey = errors.New("invalid api key")
+	// ErrMalformedKey is returned when a presented key does not have the wapi_<prefix>_<secret> shape.
+	ErrMalformedKey = errors.New("malformed api key")
+)
+
+// tokenScopes maps key scopes to the scopes carried by user tokens
+var tokenScopes = map[string]string{
+	ScopeRead:  user.ScopeWeatherRead,
+	ScopeWrite: user.ScopeWeatherWrite,
+}
+
+// Key is a long-lived credential for machine clients. Only the SHA-256 hash of the
+// secret is stored; the public prefix identifies the key for lookup.
+type Key struct {
+	ID         uuid.UUID
+	Name       string
+	Prefix     string
+	Hash       string
+	Scopes     []string
+	CreatedBy  string
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Validate checks the name and scopes of the key.
+func (k *Key) Validate() error {
+	if n := utf8.RuneCountInString(k.Name); n < 1 || n > 100 {
+		return fmt.Errorf("%w: name must be between 1 and 100 characters", ErrInvalidKey)
+	}
+	if len(k.Scopes) == 0 {
+		return fmt.Errorf("%w: at least one scope is required", ErrInvalidKey)
+	}
+	for _, s := range k.Scopes {
+		if _, ok := tokenScopes[s]; !ok {
+			return fmt.Errorf("%w: unknown scope %q", ErrInvalidKey, s)
+		}
+	}
+	return nil
+}
+
+// Revoked reports whether the key has been revoked.
+func (k *Key) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// TokenScopes returns the key scopes as the scopes route guards check, e.g. weather:read.
+func (k *Key) TokenScopes() []string {
+	result := make([]string, 0, len(k.Scopes))
+	for _, s := range k.Scopes {
+		if ts, ok := tokenScopes[s]; ok && !slices.Contains(result, ts) {
+			result = append(result, ts)
+		}
+	}
+	return result
+}
+
+// Generate returns a new plaintext key of the form wapi_<prefix>_<secret> together
+// with its prefix and hash. The plaintext is shown once and never stored.
+func Generate() (plaintext string, prefix string, hash string, err error) {
+	p := make([]byte, 4)
+	if _, err := rand.Read(p); err != nil {
+		return "", "", "", err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+
+	prefix = hex.EncodeToString(p)
+	plaintext = keyPrefix + "_" + prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	return plaintext, prefix, Hash(plaintext), nil
+}
+
+// ParsePrefix extracts the lookup prefix from a plaintext key.
+func ParsePrefix(plaintext string) (string, error) {
+	parts := strings.SplitN(plaintext, "_", 3)
+	if len(parts) != 3 || parts[0] != keyPrefix || len(parts[1]) != 8 || parts[2] == "" {
+		return "", ErrMalformedKey
+	}
+	return parts[1], nil
+}
+
+// Hash returns the hex-encoded SHA-256 of a plaintext key. Keys carry 256 bits of
+// randomness, so a fast hash is sufficient and keeps per-request checks cheap.
+func Hash(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,36 @@
+package forecast
+
+import (
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/weather"
+)
+
+// Entry is the predicted weather for a single point in time.
+type Entry struct {
+	ForecastAt  time.Time
+	Temperature float64
+	Description string
+	Humidity    int
+	WindSpeed   float64
+}
+
+// Forecast is a multi-day series of predicted conditions for a city.
+type Forecast struct {
+	City      string
+	Country   string
+	FetchedAt time.Time
+	Entries   []Entry
+}
+
+// InUnits returns a copy of f with every entry's temperature and wind speed converted from metric to u.
+func (f *Forecast) InUnits(u weather.Units) *Forecast {
+	converted := *f
+	converted.Entries = make([]Entry, len(f.Entries))
+	for i, e := range f.Entries {
+		e.Temperature = weather.ConvertTemperature(e.Temperature, u)
+		e.WindSpeed = weather.ConvertSpeed(e.WindSpeed, u)
+		converted.Entries[i] = e
+	}
+	return &converted
+}
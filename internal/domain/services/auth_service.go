@@ -2,70 +2,137 @@ package services
 
 import (
 	"errors"
-	"os"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/OmidRasouli/weather-api/internal/domain/user"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-type AuthService struct{}
+// TokenUse distinguishes access tokens from refresh tokens, so one cannot be used as the other.
+type TokenUse string
 
-func NewAuthService() *AuthService {
-	return &AuthService{}
+const (
+	TokenUseAccess  TokenUse = "access"
+	TokenUseRefresh TokenUse = "refresh"
+)
+
+// Claims are the JWT claims issued by the API: the registered claims (including a
+// unique jti used for revocation) plus the account role, the space-separated scopes
+// it grants and what the token may be used for. Refresh tokens carry no role or scopes.
+type Claims struct {
+	Role     user.Role `json:"role,omitempty"`
+	Scope    string    `json:"scope,omitempty"`
+	TokenUse TokenUse  `json:"token_use"`
+	jwt.RegisteredClaims
+}
+
+// Scopes returns the scopes listed in the scope claim.
+func (c *Claims) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
+
+// HasScope reports whether the token grants scope.
+func (c *Claims) HasScope(scope string) bool {
+	return slices.Contains(c.Scopes(), scope)
 }
 
-func (s *AuthService) secret() ([]byte, error) {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		return nil, errors.New("JWT_SECRET not set")
+// IdentityClaims converts an identity verified by an external issuer into access token
+// claims, so routes treat its tokens like our own. The subject is username, the name of
+// the account linked to the identity.
+func IdentityClaims(id *user.Identity, username string) *Claims {
+	return &Claims{
+		Role:     id.Role,
+		Scope:    strings.Join(id.Role.Scopes(), " "),
+		TokenUse: TokenUseAccess,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id.TokenID,
+			Issuer:    id.Issuer,
+			Subject:   username,
+			ExpiresAt: jwt.NewNumericDate(id.ExpiresAt),
+		},
 	}
-	return []byte(secret), nil
 }
 
-func (s *AuthService) GenerateToken(username string, ttl time.Duration) (string, time.Time, error) {
-	sec, err := s.secret()
+// AuthService signs tokens with the active key of its key set and verifies them
+// against every key in the set, selected by the kid header.
+type AuthService struct {
+	keys *KeySet
+}
+
+func NewAuthService(keys *KeySet) *AuthService {
+	return &AuthService{keys: keys}
+}
+
+// JWKS returns the public keys other services use to verify our tokens.
+func (s *AuthService) JWKS() JWKS {
+	return s.keys.JWKS()
+}
+
+// GenerateToken issues an access token for username carrying the role and the scopes it grants.
+func (s *AuthService) GenerateToken(username string, role user.Role, ttl time.Duration) (string, time.Time, error) {
+	return s.sign(Claims{
+		Role:     role,
+		Scope:    strings.Join(role.Scopes(), " "),
+		TokenUse: TokenUseAccess,
+	}, username, ttl)
+}
+
+// GenerateRefreshToken issues a refresh token for username. It only identifies the
+// account; the role is looked up again when the token is exchanged.
+func (s *AuthService) GenerateRefreshToken(username string, ttl time.Duration) (string, time.Time, error) {
+	return s.sign(Claims{TokenUse: TokenUseRefresh}, username, ttl)
+}
+
+// sign fills in the registered claims, including a fresh jti, and signs the token
+func (s *AuthService) sign(claims Claims, username string, ttl time.Duration) (string, time.Time, error) {
+	key, err := s.keys.Active()
 	if err != nil {
 		return "", time.Time{}, err
 	}
 
-	exp := time.Now().Add(ttl)
-	claims := jwt.RegisteredClaims{
+	now := time.Now()
+	exp := now.Add(ttl)
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
 		Subject:   username,
 		ExpiresAt: jwt.NewNumericDate(exp),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		IssuedAt:  jwt.NewNumericDate(now),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signed, err := token.SignedString(sec)
+	token := jwt.NewWithClaims(key.Method, claims)
+	if key.ID != "" {
+		token.Header["kid"] = key.ID
+	}
+	signed, err := token.SignedString(key.private)
 	return signed, exp, err
 }
 
-func (s *AuthService) ValidateToken(tokenString string) (*jwt.RegisteredClaims, error) {
-	sec, err := s.secret()
-	if err != nil {
-		return nil, err
-	}
-
-	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(t *jwt.Token) (interface{}, error) {
-		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+// ValidateToken verifies the signature with the key named by the kid header (the HMAC
+// key for tokens without one) and checks that the algorithm matches that key.
+func (s *AuthService) ValidateToken(tokenString string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, ok := s.keys.Lookup(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		if t.Method.Alg() != key.Method.Alg() {
 			return nil, errors.New("unexpected signing method")
 		}
-		return sec, nil
-	})
+		return key.public, nil
+	}, jwt.WithValidMethods(s.keys.Algorithms()))
 
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*jwt.RegisteredClaims); ok && token.Valid {
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
 		return claims, nil
 	}
 
 	return nil, errors.New("invalid token")
 }
-
-func (s *AuthService) ValidateCredentials(username, password string) bool {
-	adminUsername := os.Getenv("ADMIN_USERNAME")
-	adminPassword := os.Getenv("ADMIN_PASSWORD")
-	return username == adminUsername && password == adminPassword
-}
@@ -0,0 +1,17 @@
+package services
+
+import "golang.org/x/crypto/bcrypt"
+
+// HashPassword returns the bcrypt hash of a plaintext password.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// ComparePassword reports whether password matches a hash produced by HashPassword.
+func ComparePassword(hash string, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
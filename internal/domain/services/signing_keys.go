@@ -0,0 +1,185 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrNoSigningKey is returned when a token is requested but no signing key is configured.
+var ErrNoSigningKey = errors.New("no JWT signing key configured")
+
+// SigningKey is a key used to sign or verify tokens. Asymmetric keys are identified by
+// their kid, the RFC 7638 thumbprint of the public key; the HMAC key has no kid.
+// Verification-only keys have no private part.
+type SigningKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	private interface{}
+	public  interface{}
+}
+
+// NewHMACKey wraps a shared secret for HS256. It is never published in the JWKS.
+func NewHMACKey(secret []byte) *SigningKey {
+	return &SigningKey{Method: jwt.SigningMethodHS256, private: secret, public: secret}
+}
+
+// NewAsymmetricKey wraps an RSA (RS256) or Ed25519 (EdDSA) key. Private keys can sign
+// and verify, public keys only verify.
+func NewAsymmetricKey(key interface{}) (*SigningKey, error) {
+	k := &SigningKey{}
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		k.Method, k.private, k.public = jwt.SigningMethodRS256, key, &key.PublicKey
+	case *rsa.PublicKey:
+		k.Method, k.public = jwt.SigningMethodRS256, key
+	case ed25519.PrivateKey:
+		k.Method, k.private, k.public = jwt.SigningMethodEdDSA, key, key.Public()
+	case ed25519.PublicKey:
+		k.Method, k.public = jwt.SigningMethodEdDSA, key
+	default:
+		return nil, fmt.Errorf("unsupported key type %T: use RSA or Ed25519", key)
+	}
+
+	if pub, ok := k.public.(*rsa.PublicKey); ok && pub.N.BitLen() < 2048 {
+		return nil, fmt.Errorf("RSA key of %d bits is too small, use at least 2048", pub.N.BitLen())
+	}
+
+	thumbprint, err := json.Marshal(k.jwk().thumbprintMembers())
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(thumbprint)
+	k.ID = base64.RawURLEncoding.EncodeToString(sum[:])
+	return k, nil
+}
+
+// CanSign reports whether the key holds a private part.
+func (k *SigningKey) CanSign() bool {
+	return k.private != nil
+}
+
+// JWK is the JSON Web Key representation of a public key.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set as served from /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// thumbprintMembers returns the required members of the JWK in lexicographic order, as hashed by RFC 7638
+func (j JWK) thumbprintMembers() interface{} {
+	if j.Kty == "RSA" {
+		return struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{j.E, j.Kty, j.N}
+	}
+	return struct {
+		Crv string `json:"crv"`
+		Kty string `json:"kty"`
+		X   string `json:"x"`
+	}{j.Crv, j.Kty, j.X}
+}
+
+// jwk converts the public part of an asymmetric key
+func (k *SigningKey) jwk() JWK {
+	j := JWK{Kid: k.ID, Use: "sig", Alg: k.Method.Alg()}
+	switch pub := k.public.(type) {
+	case *rsa.PublicKey:
+		j.Kty = "RSA"
+		j.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		j.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		j.Kty = "OKP"
+		j.Crv = "Ed25519"
+		j.X = base64.RawURLEncoding.EncodeToString(pub)
+	}
+	return j
+}
+
+// KeySet holds the key new tokens are signed with and every key tokens are still
+// accepted from. Keeping a retired key in the set lets tokens it signed stay valid
+// until they expire, which makes rotation zero-downtime.
+type KeySet struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+// NewKeySet creates a key set signing with active (which may be nil to disable issuing)
+// and additionally verifying with the given keys.
+func NewKeySet(active *SigningKey, verification ...*SigningKey) *KeySet {
+	ks := &KeySet{active: active, keys: make(map[string]*SigningKey)}
+	for _, k := range verification {
+		ks.keys[k.ID] = k
+	}
+	if active != nil {
+		ks.keys[active.ID] = active
+	}
+	return ks
+}
+
+// Active returns the signing key, or ErrNoSigningKey when none is configured.
+func (ks *KeySet) Active() (*SigningKey, error) {
+	if ks.active == nil || !ks.active.CanSign() {
+		return nil, ErrNoSigningKey
+	}
+	return ks.active, nil
+}
+
+// Lookup returns the verification key for kid; the HMAC key is found under the empty kid.
+func (ks *KeySet) Lookup(kid string) (*SigningKey, bool) {
+	k, ok := ks.keys[kid]
+	return k, ok
+}
+
+// Algorithms lists the algorithms of all keys in the set.
+func (ks *KeySet) Algorithms() []string {
+	seen := make(map[string]bool)
+	var algs []string
+	for _, k := range ks.keys {
+		if alg := k.Method.Alg(); !seen[alg] {
+			seen[alg] = true
+			algs = append(algs, alg)
+		}
+	}
+	return algs
+}
+
+// JWKS returns the public asymmetric keys of the set, active key first and the rest by kid.
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	if ks.active != nil && ks.active.ID != "" {
+		set.Keys = append(set.Keys, ks.active.jwk())
+	}
+
+	others := make([]string, 0, len(ks.keys))
+	for id, k := range ks.keys {
+		if id != "" && k != ks.active {
+			others = append(others, id)
+		}
+	}
+	sort.Strings(others)
+	for _, id := range others {
+		set.Keys = append(set.Keys, ks.keys[id].jwk())
+	}
+	return set
+}
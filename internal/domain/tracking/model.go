@@ -0,0 +1,58 @@
+package tracking
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultRefreshInterval is used when a location is created without an interval.
+	DefaultRefreshInterval = 30 * time.Minute
+	// MinRefreshInterval is the shortest interval a location may be refreshed at.
+	MinRefreshInterval = time.Minute
+	// MaxRefreshInterval is the longest interval a location may be refreshed at.
+	MaxRefreshInterval = 7 * 24 * time.Hour
+)
+
+// ErrInvalidLocation is returned when a tracked location fails validation.
+var ErrInvalidLocation = errors.New("invalid tracked location")
+
+// Location is a city whose weather is refreshed in the background on a fixed interval.
+type Location struct {
+	ID              uuid.UUID
+	City            string
+	Country         string
+	RefreshInterval time.Duration
+	Enabled         bool
+	NextRunAt       time.Time
+	LastRefreshedAt *time.Time
+	LastError       string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Validate checks the location and its refresh interval.
+func (l *Location) Validate() error {
+	if l.City == "" {
+		return fmt.Errorf("%w: city is required", ErrInvalidLocation)
+	}
+	if l.Country == "" {
+		return fmt.Errorf("%w: country is required", ErrInvalidLocation)
+	}
+	if l.RefreshInterval < MinRefreshInterval || l.RefreshInterval > MaxRefreshInterval {
+		return fmt.Errorf("%w: refresh interval must be between %s and %s", ErrInvalidLocation, MinRefreshInterval, MaxRefreshInterval)
+	}
+	if l.RefreshInterval%time.Second != 0 {
+		return fmt.Errorf("%w: refresh interval must be a whole number of seconds", ErrInvalidLocation)
+	}
+	return nil
+}
+
+// Update holds the mutable settings of a tracked location; nil fields are left unchanged.
+type Update struct {
+	RefreshInterval *time.Duration
+	Enabled         *bool
+}
@@ -0,0 +1,29 @@
+package user
+
+import "time"
+
+// Identity is a user authenticated by an external OpenID Connect issuer. Issuer and
+// Subject together identify the person; Username is only a display name and may change.
+type Identity struct {
+	Issuer   string
+	Subject  string
+	Username string
+	Groups   []string
+	// Role is derived from Groups and is empty when none of them maps to a role
+	Role Role
+	// TokenID and ExpiresAt describe the token the identity was read from
+	TokenID   string
+	ExpiresAt time.Time
+}
+
+// RoleForGroups returns the most privileged role mapped to any of groups, or fallback
+// when none of them is mapped.
+func RoleForGroups(groups []string, mapping map[string]Role, fallback Role) Role {
+	best := fallback
+	for _, g := range groups {
+		if r, ok := mapping[g]; ok && roleRank[r] > roleRank[best] {
+			best = r
+		}
+	}
+	return best
+}
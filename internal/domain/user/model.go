@@ -0,0 +1,59 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MinPasswordLength is the shortest password accepted for an account.
+	MinPasswordLength = 8
+	// MaxPasswordLength is the longest password accepted; bcrypt ignores bytes past 72.
+	MaxPasswordLength = 72
+)
+
+var (
+	// ErrInvalidUser is returned when a user or password fails validation.
+	ErrInvalidUser = errors.New("invalid user")
+	// ErrUsernameTaken is returned when creating a user whose username already exists.
+	ErrUsernameTaken = errors.New("username already exists")
+)
+
+// User is an account that can log in to the API.
+type User struct {
+	ID           uuid.UUID
+	Username     string
+	PasswordHash string
+	Role         Role
+	Disabled     bool
+	// IdentityIssuer and IdentitySubject link accounts created by an OIDC login to
+	// their external identity; both are empty for local accounts
+	IdentityIssuer  string
+	IdentitySubject string
+	LastLoginAt     *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// ValidateUsername checks that a username is 3-64 characters long.
+func ValidateUsername(username string) error {
+	if n := utf8.RuneCountInString(username); n < 3 || n > 64 {
+		return fmt.Errorf("%w: username must be between 3 and 64 characters", ErrInvalidUser)
+	}
+	return nil
+}
+
+// ValidatePassword checks the length bounds of a plaintext password.
+func ValidatePassword(password string) error {
+	if utf8.RuneCountInString(password) < MinPasswordLength {
+		return fmt.Errorf("%w: password must be at least %d characters", ErrInvalidUser, MinPasswordLength)
+	}
+	if len(password) > MaxPasswordLength {
+		return fmt.Errorf("%w: password must be at most %d bytes", ErrInvalidUser, MaxPasswordLength)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package user
+
+import "fmt"
+
+// Role is the coarse permission level of an account. Each role includes the ones below it.
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+)
+
+// Scopes carried in access tokens. Routes require scopes rather than roles where
+// a credential other than a user token may be allowed in the future.
+const (
+	ScopeWeatherRead   = "weather:read"
+	ScopeWeatherWrite  = "weather:write"
+	ScopeWeatherDelete = "weather:delete"
+	ScopeAdmin         = "admin"
+)
+
+// roleRank orders the roles from least to most privileged
+var roleRank = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+// roleScopes lists the scopes granted to each role
+var roleScopes = map[Role][]string{
+	RoleViewer: {ScopeWeatherRead},
+	RoleEditor: {ScopeWeatherRead, ScopeWeatherWrite},
+	RoleAdmin:  {ScopeWeatherRead, ScopeWeatherWrite, ScopeWeatherDelete, ScopeAdmin},
+}
+
+// ParseRole converts a role name into a Role, rejecting unknown names.
+func ParseRole(s string) (Role, error) {
+	r := Role(s)
+	if _, ok := roleRank[r]; !ok {
+		return "", fmt.Errorf("%w: unknown role %q", ErrInvalidUser, s)
+	}
+	return r, nil
+}
+
+// Includes reports whether r grants at least the privileges of required.
+// Unknown roles include nothing.
+func (r Role) Includes(required Role) bool {
+	rank, ok := roleRank[r]
+	return ok && rank >= roleRank[required]
+}
+
+// Scopes returns the scopes granted to the role.
+func (r Role) Scopes() []string {
+	return append([]string(nil), roleScopes[r]...)
+}
@@ -0,0 +1,83 @@
+package weather
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultHistoryWindow is the time range covered when a history query omits "from".
+	DefaultHistoryWindow = 24 * time.Hour
+	// DefaultHistoryBucket is the bucket width used when a history query omits "bucket".
+	DefaultHistoryBucket = time.Hour
+	// MinHistoryBucket is the narrowest bucket a history query may request.
+	MinHistoryBucket = time.Minute
+	// MaxHistoryBuckets caps how many buckets a single history query can produce.
+	MaxHistoryBuckets = 1000
+)
+
+// ErrInvalidHistoryQuery is returned when a history query has an invalid range or bucket.
+var ErrInvalidHistoryQuery = errors.New("invalid history query")
+
+// HistoryQuery selects the observations of one city over a time range, grouped into buckets.
+type HistoryQuery struct {
+	City    string
+	Country string
+	From    time.Time
+	To      time.Time
+	Bucket  time.Duration
+}
+
+// Validate checks the range and bucket width of the query.
+func (q HistoryQuery) Validate() error {
+	if q.City == "" {
+		return fmt.Errorf("%w: city is required", ErrInvalidHistoryQuery)
+	}
+	if !q.From.Before(q.To) {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidHistoryQuery)
+	}
+	if q.Bucket < MinHistoryBucket {
+		return fmt.Errorf("%w: bucket must be at least %s", ErrInvalidHistoryQuery, MinHistoryBucket)
+	}
+	if q.Bucket%time.Second != 0 {
+		return fmt.Errorf("%w: bucket must be a whole number of seconds", ErrInvalidHistoryQuery)
+	}
+	if int64(q.To.Sub(q.From)/q.Bucket) > MaxHistoryBuckets {
+		return fmt.Errorf("%w: range spans more than %d buckets", ErrInvalidHistoryQuery, MaxHistoryBuckets)
+	}
+	return nil
+}
+
+// HistoryBucket aggregates the observations that fall into one time bucket.
+type HistoryBucket struct {
+	Start          time.Time `json:"start"`
+	Samples        int       `json:"samples"`
+	MinTemperature float64   `json:"minTemperature"`
+	MaxTemperature float64   `json:"maxTemperature"`
+	AvgTemperature float64   `json:"avgTemperature"`
+	MinHumidity    int       `json:"minHumidity"`
+	MaxHumidity    int       `json:"maxHumidity"`
+	AvgHumidity    float64   `json:"avgHumidity"`
+	MinWindSpeed   float64   `json:"minWindSpeed"`
+	MaxWindSpeed   float64   `json:"maxWindSpeed"`
+	AvgWindSpeed   float64   `json:"avgWindSpeed"`
+}
+
+// ParseBucket parses a bucket width such as "15m", "1h" or "1d".
+// In addition to time.ParseDuration syntax it accepts a whole number of days with a "d" suffix.
+func ParseBucket(s string) (time.Duration, error) {
+	if n := len(s); n > 1 && s[n-1] == 'd' {
+		days, err := strconv.Atoi(s[:n-1])
+		if err != nil || days <= 0 {
+			return 0, fmt.Errorf("%w: invalid bucket %q", ErrInvalidHistoryQuery, s)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid bucket %q", ErrInvalidHistoryQuery, s)
+	}
+	return d, nil
+}
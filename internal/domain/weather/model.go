@@ -10,11 +10,39 @@ type Weather struct {
 	ID          uuid.UUID
 	City        string
 	Country     string
+	Latitude    *float64
+	Longitude   *float64
 	Temperature float64
+	FeelsLike   float64
+	TempMin     float64
+	TempMax     float64
 	Description string
+	ConditionID int    // provider condition code, e.g. OpenWeather 800 for clear sky
+	Icon        string // provider icon identifier, e.g. "01d"
 	Humidity    int
+	Pressure    int // hPa
+	Visibility  int // meters
+	Clouds      int // cloud cover in percent
 	WindSpeed   float64
+	WindDeg     int // meteorological degrees
+	WindGust    float64
+	Rain        float64 // precipitation volume for the last hour, mm
+	Snow        float64 // snow volume for the last hour, mm
+	Sunrise     *time.Time
+	Sunset      *time.Time
 	FetchedAt   time.Time
+	Provider    string
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
+
+// Observation is a weather record as served for a fetch, together with how fresh it is.
+// Freshness belongs to the response rather than the record and is never serialized with it.
+type Observation struct {
+	*Weather
+	// Age is how long ago the record was fetched, zero when it was fetched for this request
+	Age time.Duration `json:"-"`
+	// Stale is set when the record is served past its freshness: from cache while it is
+	// being refreshed, or from storage while the upstream API is unavailable
+	Stale bool `json:"-"`
+}
@@ -0,0 +1,59 @@
+package weather
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// DefaultNearbyRadiusKm is the search radius used when a nearby query omits one.
+	DefaultNearbyRadiusKm = 25.0
+	// MaxNearbyRadiusKm caps the search radius of a nearby query.
+	MaxNearbyRadiusKm = 500.0
+	// EarthRadiusKm is the mean Earth radius used for haversine distances.
+	EarthRadiusKm = 6371.0
+)
+
+// ErrInvalidNearbyQuery is returned when a nearby query has out-of-range coordinates or radius.
+var ErrInvalidNearbyQuery = errors.New("invalid nearby query")
+
+// NearbyQuery selects stored observations within a radius of a point.
+type NearbyQuery struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+	Limit     int
+}
+
+// Normalize fills in the default radius and page size.
+func (q *NearbyQuery) Normalize() {
+	if q.RadiusKm <= 0 {
+		q.RadiusKm = DefaultNearbyRadiusKm
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultPageSize
+	}
+	if q.Limit > MaxPageSize {
+		q.Limit = MaxPageSize
+	}
+}
+
+// Validate checks the coordinates and radius of the query.
+func (q NearbyQuery) Validate() error {
+	if q.Latitude < -90 || q.Latitude > 90 {
+		return fmt.Errorf("%w: latitude must be between -90 and 90", ErrInvalidNearbyQuery)
+	}
+	if q.Longitude < -180 || q.Longitude > 180 {
+		return fmt.Errorf("%w: longitude must be between -180 and 180", ErrInvalidNearbyQuery)
+	}
+	if q.RadiusKm > MaxNearbyRadiusKm {
+		return fmt.Errorf("%w: radius must be at most %.0f km", ErrInvalidNearbyQuery, MaxNearbyRadiusKm)
+	}
+	return nil
+}
+
+// NearbyResult is a stored observation together with its distance from the query point.
+type NearbyResult struct {
+	*Weather
+	DistanceKm float64 `json:"distanceKm"`
+}
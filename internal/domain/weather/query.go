@@ -0,0 +1,89 @@
+package weather
+
+import "time"
+
+const (
+	// DefaultPageSize is used when a query does not specify a limit.
+	DefaultPageSize = 20
+	// MaxPageSize caps the number of records returned in a single page.
+	MaxPageSize = 100
+	// DefaultSortField is the field records are ordered by when none is given.
+	DefaultSortField = "fetched_at"
+)
+
+// SortFields lists the fields of Weather that listings can be ordered by.
+var SortFields = []string{
+	"id",
+	"city",
+	"country",
+	"latitude",
+	"longitude",
+	"temperature",
+	"feels_like",
+	"temp_min",
+	"temp_max",
+	"description",
+	"condition_id",
+	"icon",
+	"humidity",
+	"pressure",
+	"visibility",
+	"clouds",
+	"wind_speed",
+	"wind_deg",
+	"wind_gust",
+	"rain",
+	"snow",
+	"sunrise",
+	"sunset",
+	"fetched_at",
+	"provider",
+	"created_at",
+	"updated_at",
+}
+
+// IsSortField reports whether field is one of SortFields.
+func IsSortField(field string) bool {
+	for _, f := range SortFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Query describes filtering, sorting and pagination for listing weather records.
+type Query struct {
+	City        string
+	Country     string
+	FetchedFrom time.Time
+	FetchedTo   time.Time
+	SortBy      string
+	Descending  bool
+	Limit       int
+	Offset      int
+}
+
+// Normalize fills in defaults and clamps the pagination values.
+func (q *Query) Normalize() {
+	if !IsSortField(q.SortBy) {
+		q.SortBy = DefaultSortField
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultPageSize
+	}
+	if q.Limit > MaxPageSize {
+		q.Limit = MaxPageSize
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+}
+
+// Page is a single page of weather records together with the total match count.
+type Page struct {
+	Items  []*Weather
+	Total  int64
+	Limit  int
+	Offset int
+}
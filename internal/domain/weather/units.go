@@ -0,0 +1,77 @@
+package weather
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Units is a measurement system for temperatures and wind speeds.
+// Stored values are always Metric; other systems are applied on read.
+type Units string
+
+const (
+	// Metric reports temperatures in Celsius and wind speed in m/s.
+	Metric Units = "metric"
+	// Imperial reports temperatures in Fahrenheit and wind speed in mph.
+	Imperial Units = "imperial"
+	// Standard reports temperatures in Kelvin and wind speed in m/s.
+	Standard Units = "standard"
+)
+
+const metersPerSecondToMph = 2.2369362920544
+
+// ParseUnits parses a units name case-insensitively. An empty string yields Metric.
+func ParseUnits(s string) (Units, error) {
+	switch u := Units(strings.ToLower(strings.TrimSpace(s))); u {
+	case "":
+		return Metric, nil
+	case Metric, Imperial, Standard:
+		return u, nil
+	default:
+		return "", fmt.Errorf("unsupported units %q (expected metric, imperial or standard)", s)
+	}
+}
+
+// ConvertTemperature converts a Celsius temperature to the given units.
+func ConvertTemperature(celsius float64, u Units) float64 {
+	switch u {
+	case Imperial:
+		return celsius*9/5 + 32
+	case Standard:
+		return celsius + 273.15
+	default:
+		return celsius
+	}
+}
+
+// ConvertSpeed converts a speed in m/s to the given units.
+func ConvertSpeed(metersPerSecond float64, u Units) float64 {
+	if u == Imperial {
+		return metersPerSecond * metersPerSecondToMph
+	}
+	return metersPerSecond
+}
+
+// InUnits returns a copy of w with temperatures and wind speeds converted from metric to u.
+func (w *Weather) InUnits(u Units) *Weather {
+	converted := *w
+	converted.Temperature = ConvertTemperature(w.Temperature, u)
+	converted.FeelsLike = ConvertTemperature(w.FeelsLike, u)
+	converted.TempMin = ConvertTemperature(w.TempMin, u)
+	converted.TempMax = ConvertTemperature(w.TempMax, u)
+	converted.WindSpeed = ConvertSpeed(w.WindSpeed, u)
+	converted.WindGust = ConvertSpeed(w.WindGust, u)
+	return &converted
+}
+
+// InUnits returns a copy of b with temperature and wind speed aggregates converted from metric to u.
+func (b *HistoryBucket) InUnits(u Units) *HistoryBucket {
+	converted := *b
+	converted.MinTemperature = ConvertTemperature(b.MinTemperature, u)
+	converted.MaxTemperature = ConvertTemperature(b.MaxTemperature, u)
+	converted.AvgTemperature = ConvertTemperature(b.AvgTemperature, u)
+	converted.MinWindSpeed = ConvertSpeed(b.MinWindSpeed, u)
+	converted.MaxWindSpeed = ConvertSpeed(b.MaxWindSpeed, u)
+	converted.AvgWindSpeed = ConvertSpeed(b.AvgWindSpeed, u)
+	return &converted
+}
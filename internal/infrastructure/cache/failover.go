@@ -0,0 +1,223 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/pkg/logger"
+)
+
+// DefaultCheckInterval is how often a Failover checks its primary cache when none is configured
+const DefaultCheckInterval = 5 * time.Second
+
+// Failover serves from a primary cache while it answers health checks and from an in-memory
+// cache while it does not. The primary is checked every interval, and as soon as one of its
+// calls fails, and is switched back in once it answers again. The memory cache is emptied on
+// every switch, so entries written during an outage are never served after it.
+type Failover struct {
+	primary  interfaces.Cache
+	fallback *Memory
+	interval time.Duration
+	checkMu  sync.Mutex // serializes checks so that each switch is made and logged once
+	degraded atomic.Bool
+	checking atomic.Bool
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewFailover checks primary once and then keeps checking it in the background until Close;
+// a zero interval falls back to DefaultCheckInterval.
+func NewFailover(primary interfaces.Cache, fallback *Memory, interval time.Duration) *Failover {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	f := &Failover{
+		primary:  primary,
+		fallback: fallback,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	f.check()
+	go f.monitor()
+	return f
+}
+
+// Degraded reports whether calls are served from memory because the primary is unavailable.
+func (f *Failover) Degraded() bool {
+	return f.degraded.Load()
+}
+
+func (f *Failover) monitor() {
+	defer close(f.done)
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-f.stop:
+			return
+		case <-ticker.C:
+			f.check()
+		}
+	}
+}
+
+// check switches to memory when the primary fails its health check and back when it passes
+func (f *Failover) check() {
+	f.checkMu.Lock()
+	defer f.checkMu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), f.interval)
+	defer cancel()
+
+	err := f.primary.HealthCheck(ctx)
+	if err != nil && !f.degraded.Load() {
+		logger.Warnf("Cache is unavailable, serving from memory until it recovers: %v", err)
+		_ = f.fallback.Flush(ctx)
+		f.degraded.Store(true)
+	} else if err == nil && f.degraded.Load() {
+		logger.Infof("Cache is available again")
+		f.degraded.Store(false)
+		_ = f.fallback.Flush(ctx)
+	}
+}
+
+// observe checks the primary right away when one of its calls failed for another reason
+// than a missing key, unless a check is already running
+func (f *Failover) observe(err error) {
+	if err == nil || errors.Is(err, ErrNotFound) || !f.checking.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer f.checking.Store(false)
+		f.check()
+	}()
+}
+
+func (f *Failover) active() interfaces.Cache {
+	if f.degraded.Load() {
+		return f.fallback
+	}
+	return f.primary
+}
+
+// HealthCheck checks the primary, so it fails while calls are served from memory
+func (f *Failover) HealthCheck(ctx context.Context) error {
+	return f.primary.HealthCheck(ctx)
+}
+
+// SetWithTTL sets a key with a custom TTL
+func (f *Failover) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	err := f.active().SetWithTTL(ctx, key, value, ttl)
+	f.observe(err)
+	return err
+}
+
+// Set sets a key with the default TTL
+func (f *Failover) Set(ctx context.Context, key string, value interface{}) error {
+	err := f.active().Set(ctx, key, value)
+	f.observe(err)
+	return err
+}
+
+// Get retrieves a value and unmarshals it to the provided destination
+func (f *Failover) Get(ctx context.Context, key string, dest interface{}) error {
+	err := f.active().Get(ctx, key, dest)
+	f.observe(err)
+	return err
+}
+
+// GetWithTTL retrieves a value together with its remaining TTL
+func (f *Failover) GetWithTTL(ctx context.Context, key string, dest interface{}) (time.Duration, error) {
+	ttl, err := f.active().GetWithTTL(ctx, key, dest)
+	f.observe(err)
+	return ttl, err
+}
+
+// GetTTL returns the remaining TTL for a key
+func (f *Failover) GetTTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := f.active().GetTTL(ctx, key)
+	f.observe(err)
+	return ttl, err
+}
+
+// Exists checks if a key exists
+func (f *Failover) Exists(ctx context.Context, key string) (bool, error) {
+	ok, err := f.active().Exists(ctx, key)
+	f.observe(err)
+	return ok, err
+}
+
+// Delete removes one or more keys
+func (f *Failover) Delete(ctx context.Context, keys ...string) error {
+	err := f.active().Delete(ctx, keys...)
+	f.observe(err)
+	return err
+}
+
+// Flush removes all keys
+func (f *Failover) Flush(ctx context.Context) error {
+	err := f.active().Flush(ctx)
+	f.observe(err)
+	return err
+}
+
+// Scan returns one batch of the keys matching the pattern. A cursor is only valid on the
+// cache that returned it, so an iteration spanning a switch starts over on the other one.
+func (f *Failover) Scan(ctx context.Context, cursor uint64, pattern string, count int64) ([]string, uint64, error) {
+	keys, next, err := f.active().Scan(ctx, cursor, pattern, count)
+	f.observe(err)
+	return keys, next, err
+}
+
+// GetKeys returns keys matching the pattern
+func (f *Failover) GetKeys(ctx context.Context, pattern string) ([]string, error) {
+	keys, err := f.active().GetKeys(ctx, pattern)
+	f.observe(err)
+	return keys, err
+}
+
+// Expire sets expiration for a key
+func (f *Failover) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	err := f.active().Expire(ctx, key, ttl)
+	f.observe(err)
+	return err
+}
+
+// Increment atomically increments a key's value
+func (f *Failover) Increment(ctx context.Context, key string) (int64, error) {
+	n, err := f.active().Increment(ctx, key)
+	f.observe(err)
+	return n, err
+}
+
+// TryLock takes the lock in the primary when it is a Locker. While degraded, or without a
+// locking primary, the lock is always granted: other instances cannot be reached anyway.
+func (f *Failover) TryLock(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	locker, ok := f.primary.(interfaces.Locker)
+	if !ok || f.degraded.Load() {
+		return "", true, nil
+	}
+	token, ok, err := locker.TryLock(ctx, key, ttl)
+	f.observe(err)
+	return token, ok, err
+}
+
+// Unlock releases a lock taken in the primary; locks granted while degraded have no token
+func (f *Failover) Unlock(ctx context.Context, key string, token string) error {
+	locker, ok := f.primary.(interfaces.Locker)
+	if !ok || token == "" {
+		return nil
+	}
+	return locker.Unlock(ctx, key, token)
+}
+
+// Close stops checking the primary. The primary itself is left open for its other users.
+func (f *Failover) Close() error {
+	close(f.stop)
+	<-f.done
+	return nil
+}
@@ -0,0 +1,98 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/infrastructure/cache"
+	"github.com/OmidRasouli/weather-api/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	testhelpers.InitTestLogger()
+	os.Exit(m.Run())
+}
+
+// flakyCache is a memory cache whose calls fail while down is set
+type flakyCache struct {
+	interfaces.Cache
+	down atomic.Bool
+}
+
+func newFlakyCache() *flakyCache {
+	return &flakyCache{Cache: cache.NewMemory(10, time.Minute)}
+}
+
+func (f *flakyCache) HealthCheck(ctx context.Context) error {
+	if f.down.Load() {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (f *flakyCache) Set(ctx context.Context, key string, value interface{}) error {
+	if f.down.Load() {
+		return errors.New("connection refused")
+	}
+	return f.Cache.Set(ctx, key, value)
+}
+
+func (f *flakyCache) Get(ctx context.Context, key string, dest interface{}) error {
+	if f.down.Load() {
+		return errors.New("connection refused")
+	}
+	return f.Cache.Get(ctx, key, dest)
+}
+
+func TestFailoverCache_SwitchesToMemoryAndBack(t *testing.T) {
+	primary := newFlakyCache()
+	f := cache.NewFailover(primary, cache.NewMemory(10, time.Minute), time.Hour)
+	defer f.Close()
+	ctx := context.Background()
+
+	assert.False(t, f.Degraded())
+	assert.NoError(t, f.Set(ctx, "weather:tehran:IR", "from redis"))
+
+	// A failed call checks the primary at once and switches to memory
+	primary.down.Store(true)
+	var got string
+	assert.Error(t, f.Get(ctx, "weather:tehran:IR", &got))
+	assert.Eventually(t, f.Degraded, time.Second, 5*time.Millisecond)
+
+	assert.NoError(t, f.Set(ctx, "weather:tehran:IR", "from memory"))
+	assert.NoError(t, f.Get(ctx, "weather:tehran:IR", &got))
+	assert.Equal(t, "from memory", got)
+
+	// Locks are granted locally while degraded
+	token, ok, err := f.TryLock(ctx, "lock:weather:tehran:IR", time.Second)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.NoError(t, f.Unlock(ctx, "lock:weather:tehran:IR", token))
+}
+
+func TestFailoverCache_Reconnects(t *testing.T) {
+	primary := newFlakyCache()
+	primary.down.Store(true)
+	f := cache.NewFailover(primary, cache.NewMemory(10, time.Minute), 10*time.Millisecond)
+	defer f.Close()
+	ctx := context.Background()
+
+	// Unreachable from the start, so memory is used
+	assert.True(t, f.Degraded())
+	assert.NoError(t, f.Set(ctx, "weather:tehran:IR", "from memory"))
+
+	// Once the primary answers again it is swapped back in and memory is dropped
+	primary.down.Store(false)
+	assert.Eventually(t, func() bool { return !f.Degraded() }, time.Second, 5*time.Millisecond)
+	var got string
+	assert.Error(t, f.Get(ctx, "weather:tehran:IR", &got))
+	assert.NoError(t, f.Set(ctx, "weather:tehran:IR", "from redis"))
+	assert.NoError(t, primary.Get(ctx, "weather:tehran:IR", &got))
+	assert.Equal(t, "from redis", got)
+}
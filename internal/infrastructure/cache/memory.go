@@ -0,0 +1,305 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+)
+
+const (
+	// DefaultMemorySize is the number of entries a memory cache holds when no size is configured
+	DefaultMemorySize = 10000
+	// DefaultMemoryTTL is the TTL used by Memory.Set when none is configured
+	DefaultMemoryTTL = 10 * time.Minute
+)
+
+// Memory is an in-process cache holding at most a fixed number of entries. When it is full
+// the least recently used entry is evicted. Values are stored JSON encoded, like in Redis,
+// so callers never share them.
+type Memory struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	order   *list.List // front is the most recently used
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+	now     func() time.Time // testable clock
+}
+
+type memoryEntry struct {
+	key  string
+	data []byte
+	// expiresAt is the expiry reported to callers; zero never expires
+	expiresAt time.Time
+	// evictAt is when the entry is dropped, at or before expiresAt; zero never
+	evictAt time.Time
+}
+
+// NewMemory creates a memory cache; zero values fall back to the defaults above.
+func NewMemory(size int, ttl time.Duration) *Memory {
+	if size <= 0 {
+		size = DefaultMemorySize
+	}
+	if ttl <= 0 {
+		ttl = DefaultMemoryTTL
+	}
+	return &Memory{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+// HealthCheck always succeeds
+func (m *Memory) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+// SetWithTTL sets a key with a custom TTL; zero never expires
+func (m *Memory) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+	m.store(key, data, ttl, 0)
+	return nil
+}
+
+// Set sets a key with the default TTL
+func (m *Memory) Set(ctx context.Context, key string, value interface{}) error {
+	return m.SetWithTTL(ctx, key, value, m.ttl)
+}
+
+// Get retrieves a value and unmarshals it to the provided destination
+func (m *Memory) Get(ctx context.Context, key string, dest interface{}) error {
+	_, err := m.GetWithTTL(ctx, key, dest)
+	return err
+}
+
+// GetWithTTL retrieves a value together with its remaining TTL
+func (m *Memory) GetWithTTL(ctx context.Context, key string, dest interface{}) (time.Duration, error) {
+	data, ttl, ok := m.load(key)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return 0, err
+	}
+	return ttl, nil
+}
+
+// GetTTL returns the remaining TTL for a key, or a negative duration when it does not expire
+func (m *Memory) GetTTL(ctx context.Context, key string) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.lookup(key)
+	if e == nil {
+		return 0, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return m.remaining(e), nil
+}
+
+// Exists checks if a key exists
+func (m *Memory) Exists(ctx context.Context, key string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lookup(key) != nil, nil
+}
+
+// Delete removes one or more keys
+func (m *Memory) Delete(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		if el, ok := m.entries[key]; ok {
+			m.remove(el)
+		}
+	}
+	return nil
+}
+
+// Flush removes all keys
+func (m *Memory) Flush(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = make(map[string]*list.Element)
+	m.order.Init()
+	return nil
+}
+
+// GetKeys returns keys matching the glob pattern
+func (m *Memory) GetKeys(ctx context.Context, pattern string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]string, 0)
+	for key := range m.entries {
+		ok, err := path.Match(pattern, key)
+		if err != nil {
+			return nil, err
+		}
+		if ok && m.lookup(key) != nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// Scan returns one batch of the keys matching the glob pattern, in key order, and the cursor
+// of the next batch, which is 0 once iteration is done. The cursor is an offset, so keys
+// added or removed during iteration may be skipped or returned twice.
+func (m *Memory) Scan(ctx context.Context, cursor uint64, pattern string, count int64) ([]string, uint64, error) {
+	keys, err := m.GetKeys(ctx, pattern)
+	if err != nil {
+		return nil, 0, err
+	}
+	if count <= 0 {
+		count = DefaultScanCount
+	}
+	sort.Strings(keys)
+	if cursor >= uint64(len(keys)) {
+		return []string{}, 0, nil
+	}
+	end := cursor + uint64(count)
+	if end >= uint64(len(keys)) {
+		return keys[cursor:], 0, nil
+	}
+	return keys[cursor:end], end, nil
+}
+
+// Expire sets expiration for a key; a TTL of zero or less deletes it
+func (m *Memory) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.lookup(key)
+	if e == nil {
+		return nil
+	}
+	if ttl <= 0 {
+		m.remove(m.entries[key])
+		return nil
+	}
+	e.expiresAt = m.now().Add(ttl)
+	if e.evictAt.IsZero() || e.evictAt.After(e.expiresAt) {
+		e.evictAt = e.expiresAt
+	}
+	return nil
+}
+
+// Increment atomically increments a key's value, keeping its TTL
+func (m *Memory) Increment(ctx context.Context, key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var n int64
+	e := m.lookup(key)
+	if e != nil {
+		var err error
+		if n, err = strconv.ParseInt(string(e.data), 10, 64); err != nil {
+			return 0, fmt.Errorf("value of %s is not an integer", key)
+		}
+	}
+	n++
+	data := []byte(strconv.FormatInt(n, 10))
+	if e != nil {
+		e.data = data
+		return n, nil
+	}
+	m.insert(&memoryEntry{key: key, data: data})
+	return n, nil
+}
+
+// Close is a no-op
+func (m *Memory) Close() error {
+	return nil
+}
+
+// Stats returns the hits and misses of Get and GetWithTTL
+func (m *Memory) Stats() interfaces.CacheTierStats {
+	m.mu.Lock()
+	entries := len(m.entries)
+	m.mu.Unlock()
+	return interfaces.CacheTierStats{Tier: "memory", Hits: m.hits.Load(), Misses: m.misses.Load(), Entries: entries}
+}
+
+// load returns the encoded value of key and its remaining TTL, counting the hit or miss
+func (m *Memory) load(key string) ([]byte, time.Duration, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := m.lookup(key)
+	if e == nil {
+		m.misses.Add(1)
+		return nil, 0, false
+	}
+	m.hits.Add(1)
+	return e.data, m.remaining(e), true
+}
+
+// store sets the encoded value of key, reported as expiring after ttl but dropped after hold
+// if that is sooner. Zero durations never expire.
+func (m *Memory) store(key string, data []byte, ttl time.Duration, hold time.Duration) {
+	now := m.now()
+	e := &memoryEntry{key: key, data: data}
+	if ttl > 0 {
+		e.expiresAt = now.Add(ttl)
+		e.evictAt = e.expiresAt
+	}
+	if hold > 0 && (e.evictAt.IsZero() || now.Add(hold).Before(e.evictAt)) {
+		e.evictAt = now.Add(hold)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, ok := m.entries[key]; ok {
+		m.remove(el)
+	}
+	m.insert(e)
+}
+
+// lookup returns the live entry for key and marks it as recently used; m.mu must be held
+func (m *Memory) lookup(key string) *memoryEntry {
+	el, ok := m.entries[key]
+	if !ok {
+		return nil
+	}
+	e := el.Value.(*memoryEntry)
+	if !e.evictAt.IsZero() && !m.now().Before(e.evictAt) {
+		m.remove(el)
+		return nil
+	}
+	m.order.MoveToFront(el)
+	return e
+}
+
+// insert adds e as the most recently used entry, evicting the least recently used one
+// when full; m.mu must be held
+func (m *Memory) insert(e *memoryEntry) {
+	m.entries[e.key] = m.order.PushFront(e)
+	for m.order.Len() > m.size {
+		m.remove(m.order.Back())
+	}
+}
+
+// remove drops an entry; m.mu must be held
+func (m *Memory) remove(el *list.Element) {
+	m.order.Remove(el)
+	delete(m.entries, el.Value.(*memoryEntry).key)
+}
+
+// remaining returns the TTL left on e, or -1 when it does not expire
+func (m *Memory) remaining(e *memoryEntry) time.Duration {
+	if e.expiresAt.IsZero() {
+		return -1
+	}
+	return e.expiresAt.Sub(m.now())
+}
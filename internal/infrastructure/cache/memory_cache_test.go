@@ -0,0 +1,149 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/infrastructure/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+type cachedWeather struct {
+	City        string
+	Temperature float64
+}
+
+func TestMemoryCache_SetAndGet(t *testing.T) {
+	c := cache.NewMemory(10, time.Minute)
+	ctx := context.Background()
+
+	err := c.Set(ctx, "weather:tehran:IR", cachedWeather{City: "tehran", Temperature: 28.5})
+	assert.NoError(t, err)
+
+	var got cachedWeather
+	ttl, err := c.GetWithTTL(ctx, "weather:tehran:IR", &got)
+	assert.NoError(t, err)
+	assert.Equal(t, 28.5, got.Temperature)
+	assert.True(t, ttl > 50*time.Second && ttl <= time.Minute)
+
+	_, err = c.GetWithTTL(ctx, "weather:berlin:DE", &got)
+	assert.True(t, errors.Is(err, cache.ErrNotFound))
+
+	assert.Equal(t, interfaces.CacheTierStats{Tier: "memory", Hits: 1, Misses: 1, Entries: 1}, c.Stats())
+}
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := cache.NewMemory(2, time.Minute)
+	ctx := context.Background()
+
+	_ = c.Set(ctx, "a", 1)
+	_ = c.Set(ctx, "b", 2)
+	var n int
+	assert.NoError(t, c.Get(ctx, "a", &n))
+	_ = c.Set(ctx, "c", 3)
+
+	ok, _ := c.Exists(ctx, "a")
+	assert.True(t, ok)
+	ok, _ = c.Exists(ctx, "b")
+	assert.False(t, ok)
+	ok, _ = c.Exists(ctx, "c")
+	assert.True(t, ok)
+}
+
+func TestMemoryCache_Expiry(t *testing.T) {
+	c := cache.NewMemory(10, time.Minute)
+	ctx := context.Background()
+
+	_ = c.SetWithTTL(ctx, "short", "value", 20*time.Millisecond)
+	_ = c.SetWithTTL(ctx, "forever", "value", 0)
+	time.Sleep(30 * time.Millisecond)
+
+	var got string
+	assert.Error(t, c.Get(ctx, "short", &got))
+	ttl, err := c.GetTTL(ctx, "forever")
+	assert.NoError(t, err)
+	assert.True(t, ttl < 0)
+
+	_ = c.Expire(ctx, "forever", 20*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	assert.Error(t, c.Get(ctx, "forever", &got))
+}
+
+func TestMemoryCache_IncrementAndKeys(t *testing.T) {
+	c := cache.NewMemory(10, time.Minute)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, _ = c.Increment(ctx, "quota:openweather:minute")
+	}
+	var n int64
+	assert.NoError(t, c.Get(ctx, "quota:openweather:minute", &n))
+	assert.Equal(t, int64(3), n)
+
+	_ = c.Set(ctx, "weather:tehran:IR", "value")
+	keys, err := c.GetKeys(ctx, "weather:*")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"weather:tehran:IR"}, keys)
+
+	assert.NoError(t, c.Delete(ctx, "weather:tehran:IR"))
+	assert.NoError(t, c.Flush(ctx))
+	keys, _ = c.GetKeys(ctx, "*")
+	assert.Empty(t, keys)
+}
+
+func TestMemoryCache_Scan(t *testing.T) {
+	c := cache.NewMemory(10, time.Minute)
+	ctx := context.Background()
+
+	for _, key := range []string{"weather:c", "weather:a", "forecast:x", "weather:b"} {
+		_ = c.Set(ctx, key, "value")
+	}
+
+	keys, cursor, err := c.Scan(ctx, 0, "weather:*", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"weather:a", "weather:b"}, keys)
+	assert.Equal(t, uint64(2), cursor)
+
+	keys, cursor, err = c.Scan(ctx, cursor, "weather:*", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"weather:c"}, keys)
+	assert.Equal(t, uint64(0), cursor)
+}
+
+func TestTieredCache_InvalidatesOtherReplicas(t *testing.T) {
+	_, remote := newRedis(t)
+	a := cache.NewTiered(remote, 10, time.Minute)
+	defer a.Close()
+	b := cache.NewTiered(remote, 10, time.Minute)
+	defer b.Close()
+	// Let both subscriptions reach the server
+	time.Sleep(100 * time.Millisecond)
+
+	ctx := context.Background()
+	key := "test-tiered-key"
+	assert.NoError(t, a.Set(ctx, key, "first"))
+
+	// b reads Redis once, then memory
+	var got string
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, b.Get(ctx, key, &got))
+		assert.Equal(t, "first", got)
+	}
+	stats := b.Stats()
+	assert.Equal(t, uint64(1), stats[0].Hits)
+	assert.Equal(t, uint64(1), stats[1].Hits)
+
+	// a's change reaches b's memory through the invalidation
+	assert.NoError(t, a.Set(ctx, key, "second"))
+	assert.Eventually(t, func() bool {
+		return b.Get(ctx, key, &got) == nil && got == "second"
+	}, time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, a.Delete(ctx, key))
+	assert.Eventually(t, func() bool {
+		return b.Get(ctx, key, &got) != nil
+	}, time.Second, 10*time.Millisecond)
+}
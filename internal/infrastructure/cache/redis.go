@@ -3,55 +3,292 @@ package cache
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
-	"github.com/OmidRasouli/weather-api/infrastructure/database/cache"
+	"github.com/OmidRasouli/weather-api/config"
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/pkg/logger"
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
-// RedisCache provides caching operations using Redis
-type RedisCache struct {
-	redis *cache.Redis
-	ttl   time.Duration
+// ErrNotFound is returned by the caches in this package for a missing or expired key
+var ErrNotFound = errors.New("key not found")
+
+// ErrNoNamespace is returned by Flush when the keys are not stored under a namespace
+var ErrNoNamespace = errors.New("cache flush requires a key namespace")
+
+// DefaultScanCount is the number of keys Redis is asked to check per SCAN call when the
+// caller does not say
+const DefaultScanCount = 100
+
+// Redis represents a Redis database connection. Every key is stored under Namespace, so
+// instances sharing a server or database with other applications keep apart from them.
+type Redis struct {
+	Client    *redis.Client
+	TTL       time.Duration
+	Namespace string
 }
 
-// NewRedisCache creates a new Redis cache service
-func NewRedisCache(redis *cache.Redis) *RedisCache {
-	ttl := time.Duration(redis.TTL) * time.Second
+// NewRedisConnection creates a new Redis connection from configuration and verifies it
+func NewRedisConnection(cfg config.RedisConfig) (interfaces.Cache, error) {
+	r := NewRedisClient(cfg)
+
+	// Verify connection with longer timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pong, err := r.Client.Ping(ctx).Result()
+	if err != nil {
+		logger.Errorf("Failed to connect to Redis at %s:%d: %v", cfg.Host, cfg.Port, err)
+		r.Client.Close() // Clean up failed connection
+		return nil, fmt.Errorf("redis connection failed: %w", err)
+	}
+
+	logger.Infof("Successfully connected to Redis: %s", pong)
+	return r, nil
+}
+
+// NewRedisClient creates a Redis client from configuration without waiting for the server.
+// Connections are made on first use and remade after failures.
+func NewRedisClient(cfg config.RedisConfig) *Redis {
+	logger.Infof("Connecting to Redis at %s:%d", cfg.Host, cfg.Port)
+
+	port := cfg.Port
+	if port == 0 {
+		port = 6379 // Default Redis port
+	}
+	client := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%d", cfg.Host, port),
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	ttl := time.Duration(cfg.TTL) * time.Second
 	if ttl == 0 {
-		ttl = 10 * time.Minute // Default TTL: 10 minutes
+		ttl = 10 * time.Minute // Default TTL
 	}
 
-	return &RedisCache{
-		redis: redis,
-		ttl:   ttl,
+	return &Redis{
+		Client:    client,
+		TTL:       ttl,
+		Namespace: cfg.Namespace,
 	}
 }
 
-func (rc *RedisCache) Get(ctx context.Context, key string, dest interface{}) error {
-	val, err := rc.redis.Client.Get(ctx, key).Result()
+// HealthCheck pings the Redis server to check if it's available
+func (r *Redis) HealthCheck(ctx context.Context) error {
+	if r.Client == nil {
+		return fmt.Errorf("redis client is nil")
+	}
+
+	// Check if client is closed
+	if r.Client.Options().Addr == "" {
+		return fmt.Errorf("redis client is closed")
+	}
+
+	_, err := r.Client.Ping(ctx).Result()
+	if err != nil {
+		logger.Errorf("Redis health check failed: %v", err)
+		return fmt.Errorf("redis ping failed: %w", err)
+	}
+	return nil
+}
+
+// SetWithTTL sets a key with a custom TTL
+func (r *Redis) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+	return r.Client.Set(ctx, r.key(key), data, ttl).Err()
+}
+
+// Set sets a key with the default TTL
+func (r *Redis) Set(ctx context.Context, key string, value interface{}) error {
+	return r.SetWithTTL(ctx, key, value, r.TTL)
+}
+
+// Get retrieves a value and unmarshals it to the provided destination
+func (r *Redis) Get(ctx context.Context, key string, dest interface{}) error {
+	val, err := r.Client.Get(ctx, r.key(key)).Result()
 	if err != nil {
 		if err == redis.Nil {
-			return fmt.Errorf("key not found in cache")
+			return fmt.Errorf("%w: %s", ErrNotFound, key)
 		}
 		return err
 	}
 	return json.Unmarshal([]byte(val), dest)
 }
 
-func (rc *RedisCache) Set(ctx context.Context, key string, value interface{}) error {
-	data, err := json.Marshal(value)
+// GetWithTTL retrieves a value together with its remaining TTL in one round trip
+func (r *Redis) GetWithTTL(ctx context.Context, key string, dest interface{}) (time.Duration, error) {
+	data, ttl, err := r.getRaw(ctx, key)
 	if err != nil {
-		return fmt.Errorf("failed to marshal value: %w", err)
+		return 0, err
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return 0, err
+	}
+	return ttl, nil
+}
+
+// getRaw returns the encoded value of key and its remaining TTL in one round trip
+func (r *Redis) getRaw(ctx context.Context, key string) ([]byte, time.Duration, error) {
+	pipe := r.Client.Pipeline()
+	get := pipe.Get(ctx, r.key(key))
+	ttl := pipe.PTTL(ctx, r.key(key))
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, 0, err
+	}
+
+	data, err := get.Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, 0, fmt.Errorf("%w: %s", ErrNotFound, key)
+		}
+		return nil, 0, err
+	}
+	return data, ttl.Val(), nil
+}
+
+// GetTTL returns the remaining TTL for a key, or a negative duration when it does not expire
+func (r *Redis) GetTTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := r.Client.TTL(ctx, r.key(key)).Result()
+	if err != nil {
+		return 0, err
 	}
-	return rc.redis.Client.Set(ctx, key, data, rc.ttl).Err()
+	// TTL answers -2 for a missing key and -1 for one without expiry
+	if ttl == -2 {
+		return 0, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return ttl, nil
 }
 
-func (rc *RedisCache) Delete(ctx context.Context, key string) error {
-	return rc.redis.Client.Del(ctx, key).Err()
+// Exists checks if a key exists
+func (r *Redis) Exists(ctx context.Context, key string) (bool, error) {
+	result, err := r.Client.Exists(ctx, r.key(key)).Result()
+	if err != nil {
+		return false, err
+	}
+	return result > 0, nil
+}
+
+// Delete removes one or more keys
+func (r *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	namespaced := make([]string, len(keys))
+	for i, key := range keys {
+		namespaced[i] = r.key(key)
+	}
+	return r.Client.Del(ctx, namespaced...).Err()
+}
+
+// Flush removes every key in the namespace, leaving the rest of the database and the other
+// databases of the server alone. Without a namespace it refuses, rather than empty a
+// database that may be shared.
+func (r *Redis) Flush(ctx context.Context) error {
+	if r.Namespace == "" {
+		return ErrNoNamespace
+	}
+	var cursor uint64
+	for {
+		keys, next, err := r.Scan(ctx, cursor, "*", 0)
+		if err != nil {
+			return err
+		}
+		if err := r.Delete(ctx, keys...); err != nil {
+			return err
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// Scan returns one batch of the keys matching the pattern, with SCAN so Redis is not
+// blocked, and the cursor of the next batch, which is 0 once iteration is done
+func (r *Redis) Scan(ctx context.Context, cursor uint64, pattern string, count int64) ([]string, uint64, error) {
+	if count <= 0 {
+		count = DefaultScanCount
+	}
+	keys, next, err := r.Client.Scan(ctx, cursor, globEscaper.Replace(r.Namespace)+pattern, count).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	for i, key := range keys {
+		keys[i] = strings.TrimPrefix(key, r.Namespace)
+	}
+	return keys, next, nil
+}
+
+// GetKeys returns keys matching the pattern, scanning the whole namespace
+func (r *Redis) GetKeys(ctx context.Context, pattern string) ([]string, error) {
+	var (
+		all    []string
+		cursor uint64
+	)
+	for {
+		keys, next, err := r.Scan(ctx, cursor, pattern, 0)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, keys...)
+		if next == 0 {
+			return all, nil
+		}
+		cursor = next
+	}
+}
+
+// Expire sets expiration for a key
+func (r *Redis) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return r.Client.Expire(ctx, r.key(key), ttl).Err()
+}
+
+// Increment atomically increments a key's value
+func (r *Redis) Increment(ctx context.Context, key string) (int64, error) {
+	return r.Client.Incr(ctx, r.key(key)).Result()
+}
+
+// unlockScript deletes a lock only while it still holds the caller's token, so a holder
+// whose lock expired cannot release the next holder's
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// TryLock sets key to a random token unless it exists, expiring after ttl
+func (r *Redis) TryLock(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	token := uuid.NewString()
+	ok, err := r.Client.SetNX(ctx, r.key(key), token, ttl).Result()
+	if err != nil || !ok {
+		return "", false, err
+	}
+	return token, true, nil
+}
+
+// Unlock deletes key if it still holds token
+func (r *Redis) Unlock(ctx context.Context, key string, token string) error {
+	return unlockScript.Run(ctx, r.Client, []string{r.key(key)}, token).Err()
+}
+
+// globEscaper quotes the characters of a namespace that a SCAN pattern would match on
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+// key returns the name of key in Redis
+func (r *Redis) key(key string) string {
+	return r.Namespace + key
 }
 
-func (rc *RedisCache) Close() error {
-	return rc.redis.Client.Close()
+// Close closes the Redis connection
+func (r *Redis) Close() error {
+	return r.Client.Close()
 }
@@ -2,39 +2,118 @@ package cache_test
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/OmidRasouli/weather-api/config"
-	"github.com/OmidRasouli/weather-api/infrastructure/database/cache"
+	"github.com/OmidRasouli/weather-api/internal/infrastructure/cache"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestRedisCache_SetAndGet(t *testing.T) {
+// connectRealRedis connects to the server at REDIS_ADDR, or skips the test when it is not set.
+// Each test gets its own namespace, which is flushed afterwards; its brackets check that
+// SCAN patterns escape the namespace.
+func connectRealRedis(t *testing.T) *cache.Redis {
 	addr := os.Getenv("REDIS_ADDR")
 	if addr == "" {
 		t.Skip("REDIS_ADDR not set, skipping integration test")
 	}
-	client, err := cache.NewRedisConnection(config.RedisConfig{
-		Host: addr,
-		Port: 6379,
-		DB:   0,
-		TTL:  600,
+	client := cache.NewRedisClient(config.RedisConfig{
+		Host:      addr,
+		Port:      6379,
+		DB:        0,
+		TTL:       600,
+		Namespace: fmt.Sprintf("weather-api-test[%d]:", time.Now().UnixNano()),
 	})
+	assert.NoError(t, client.HealthCheck(context.Background()))
+	t.Cleanup(func() {
+		_ = client.Flush(context.Background())
+		_ = client.Close()
+	})
+	return client
+}
+
+func TestRedisIntegration_SetAndGet(t *testing.T) {
+	client := connectRealRedis(t)
+	ctx := context.Background()
+
+	assert.NoError(t, client.Set(ctx, "test-key", "test-value"))
+
+	var got string
+	assert.NoError(t, client.Get(ctx, "test-key", &got))
+	assert.Equal(t, "test-value", got)
+
+	assert.NoError(t, client.Delete(ctx, "test-key"))
+	assert.ErrorIs(t, client.Get(ctx, "test-key", &got), cache.ErrNotFound)
+}
+
+func TestRedisIntegration_GetWithTTL(t *testing.T) {
+	client := connectRealRedis(t)
+	ctx := context.Background()
+
+	assert.NoError(t, client.SetWithTTL(ctx, "test-ttl-key", "test-value", time.Minute))
+
+	var got string
+	ttl, err := client.GetWithTTL(ctx, "test-ttl-key", &got)
 	assert.NoError(t, err)
-	defer client.Close()
+	assert.Equal(t, "test-value", got)
+	assert.True(t, ttl > 50*time.Second && ttl <= time.Minute)
 
+	assert.NoError(t, client.Delete(ctx, "test-ttl-key"))
+	_, err = client.GetWithTTL(ctx, "test-ttl-key", &got)
+	assert.ErrorIs(t, err, cache.ErrNotFound)
+}
+
+func TestRedisIntegration_ScanPages(t *testing.T) {
+	client := connectRealRedis(t)
 	ctx := context.Background()
-	key := "test-key"
-	value := "test-value"
 
-	err = client.Set(ctx, key, value)
+	for i := 0; i < 50; i++ {
+		assert.NoError(t, client.Set(ctx, fmt.Sprintf("weather:city:city-%d:XX", i), i))
+	}
+	assert.NoError(t, client.Set(ctx, "forecast:other:XX", 0))
+
+	// A real server returns the keys over several small pages
+	seen := make(map[string]bool)
+	var cursor uint64
+	for pages := 1; ; pages++ {
+		keys, next, err := client.Scan(ctx, cursor, "weather:*", 10)
+		assert.NoError(t, err)
+		for _, key := range keys {
+			seen[key] = true
+		}
+		if next == 0 {
+			assert.Greater(t, pages, 1)
+			break
+		}
+		cursor = next
+	}
+	assert.Len(t, seen, 50)
+	assert.True(t, seen["weather:city:city-0:XX"])
+}
+
+func TestRedisIntegration_Lock(t *testing.T) {
+	client := connectRealRedis(t)
+	ctx := context.Background()
+
+	token, ok, err := client.TryLock(ctx, "lock:test", time.Minute)
 	assert.NoError(t, err)
+	assert.True(t, ok)
 
-	var got string
-	err = client.Get(ctx, key, &got)
+	_, ok, err = client.TryLock(ctx, "lock:test", time.Minute)
 	assert.NoError(t, err)
-	assert.Equal(t, value, got)
+	assert.False(t, ok)
 
-	_ = client.Delete(ctx, key)
+	// The unlock script leaves a lock held under another token alone
+	assert.NoError(t, client.Unlock(ctx, "lock:test", "someone-else"))
+	exists, err := client.Exists(ctx, "lock:test")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	assert.NoError(t, client.Unlock(ctx, "lock:test", token))
+	_, ok, err = client.TryLock(ctx, "lock:test", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, ok)
 }
@@ -0,0 +1,175 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/config"
+	"github.com/OmidRasouli/weather-api/internal/infrastructure/cache"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRedis starts an in-process Redis and returns a client connected to it
+func newRedis(t *testing.T) (*miniredis.Miniredis, *cache.Redis) {
+	s := miniredis.RunT(t)
+	return s, connectRedis(t, s, "")
+}
+
+// connectRedis returns a client of s that stores its keys under namespace
+func connectRedis(t *testing.T, s *miniredis.Miniredis, namespace string) *cache.Redis {
+	port, _ := strconv.Atoi(s.Port())
+	client, err := cache.NewRedisConnection(config.RedisConfig{
+		Host:      s.Host(),
+		Port:      port,
+		DB:        0,
+		TTL:       600,
+		Namespace: namespace,
+	})
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client.(*cache.Redis)
+}
+
+func TestRedisCache_SetAndGet(t *testing.T) {
+	_, client := newRedis(t)
+	ctx := context.Background()
+
+	assert.NoError(t, client.Set(ctx, "test-key", "test-value"))
+
+	var got string
+	assert.NoError(t, client.Get(ctx, "test-key", &got))
+	assert.Equal(t, "test-value", got)
+
+	ok, err := client.Exists(ctx, "test-key")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.NoError(t, client.Delete(ctx, "test-key"))
+	err = client.Get(ctx, "test-key", &got)
+	assert.True(t, errors.Is(err, cache.ErrNotFound))
+}
+
+func TestRedisCache_GetWithTTL(t *testing.T) {
+	s, client := newRedis(t)
+	ctx := context.Background()
+
+	assert.NoError(t, client.SetWithTTL(ctx, "test-ttl-key", "test-value", time.Minute))
+
+	var got string
+	ttl, err := client.GetWithTTL(ctx, "test-ttl-key", &got)
+	assert.NoError(t, err)
+	assert.Equal(t, "test-value", got)
+	assert.Equal(t, time.Minute, ttl)
+
+	// Set uses the configured default TTL
+	assert.NoError(t, client.Set(ctx, "test-default-key", "test-value"))
+	ttl, err = client.GetTTL(ctx, "test-default-key")
+	assert.NoError(t, err)
+	assert.Equal(t, 600*time.Second, ttl)
+
+	s.FastForward(time.Minute)
+	_, err = client.GetWithTTL(ctx, "test-ttl-key", &got)
+	assert.True(t, errors.Is(err, cache.ErrNotFound))
+}
+
+func TestRedisCache_IncrementAndExpire(t *testing.T) {
+	s, client := newRedis(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_, _ = client.Increment(ctx, "quota:openweather:minute")
+	}
+	n, err := client.Increment(ctx, "quota:openweather:minute")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), n)
+
+	assert.NoError(t, client.Expire(ctx, "quota:openweather:minute", time.Second))
+	s.FastForward(time.Second)
+	ok, _ := client.Exists(ctx, "quota:openweather:minute")
+	assert.False(t, ok)
+}
+
+func TestRedisCache_Lock(t *testing.T) {
+	_, client := newRedis(t)
+	ctx := context.Background()
+	key := "lock:weather:city:tehran:IR"
+
+	token, ok, err := client.TryLock(ctx, key, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	_, ok, err = client.TryLock(ctx, key, time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// Only the holder's token releases the lock
+	assert.NoError(t, client.Unlock(ctx, key, "someone-else"))
+	_, ok, _ = client.TryLock(ctx, key, time.Minute)
+	assert.False(t, ok)
+
+	assert.NoError(t, client.Unlock(ctx, key, token))
+	_, ok, _ = client.TryLock(ctx, key, time.Minute)
+	assert.True(t, ok)
+}
+
+func TestRedisCache_Scan(t *testing.T) {
+	_, client := newRedis(t)
+	ctx := context.Background()
+
+	for i := 0; i < 25; i++ {
+		assert.NoError(t, client.Set(ctx, fmt.Sprintf("weather:id:%02d", i), i))
+	}
+	assert.NoError(t, client.Set(ctx, "forecast:tehran:IR", "value"))
+
+	var (
+		keys   []string
+		cursor uint64
+	)
+	for {
+		batch, next, err := client.Scan(ctx, cursor, "weather:*", 10)
+		assert.NoError(t, err)
+		keys = append(keys, batch...)
+		if next == 0 {
+			break
+		}
+		cursor = next
+	}
+	assert.Equal(t, 25, len(keys))
+
+	all, err := client.GetKeys(ctx, "weather:*")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, keys, all)
+}
+
+func TestRedisCache_NamespacedFlush(t *testing.T) {
+	s := miniredis.RunT(t)
+	ours := connectRedis(t, s, "weather-api:")
+	theirs := connectRedis(t, s, "")
+	ctx := context.Background()
+
+	assert.NoError(t, ours.Set(ctx, "weather:city:tehran:IR", "value"))
+	assert.NoError(t, theirs.Set(ctx, "session:42", "value"))
+	assert.True(t, s.Exists("weather-api:weather:city:tehran:IR"))
+
+	keys, err := ours.GetKeys(ctx, "*")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"weather:city:tehran:IR"}, keys)
+
+	assert.NoError(t, ours.Flush(ctx))
+	assert.False(t, s.Exists("weather-api:weather:city:tehran:IR"))
+	assert.True(t, s.Exists("session:42"))
+}
+
+func TestRedisCache_FlushRequiresNamespace(t *testing.T) {
+	s, client := newRedis(t)
+	ctx := context.Background()
+
+	assert.NoError(t, client.Set(ctx, "session:42", "value"))
+	assert.True(t, errors.Is(client.Flush(ctx), cache.ErrNoNamespace))
+	assert.True(t, s.Exists("session:42"))
+}
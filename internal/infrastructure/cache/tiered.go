@@ -0,0 +1,250 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// InvalidationChannel is the Redis pub/sub channel on which replicas announce changed keys
+	InvalidationChannel = "cache:invalidate"
+	// DefaultLocalTTL bounds how long a replica keeps a value in memory without rereading Redis
+	DefaultLocalTTL = 30 * time.Second
+)
+
+// invalidation is published whenever a replica changes keys, so the others drop their copies
+type invalidation struct {
+	Origin string   `json:"origin"`
+	Keys   []string `json:"keys,omitempty"`
+	All    bool     `json:"all,omitempty"`
+}
+
+// Tiered is a two-tier cache: reads are answered from an in-process Memory cache when
+// possible and from Redis otherwise, writes go to both. Replicas announce every changed key
+// on InvalidationChannel and drop the keys changed by others. A value is kept in memory for
+// at most the local TTL, which bounds how stale a replica can be if an announcement is lost.
+type Tiered struct {
+	local    *Memory
+	remote   *Redis
+	localTTL time.Duration
+	origin   string
+	pubsub   *redis.PubSub
+
+	// generation changes on every eviction, so that a value read from Redis before an
+	// eviction is not put back in memory after it
+	generation   atomic.Uint64
+	remoteHits   atomic.Uint64
+	remoteMisses atomic.Uint64
+}
+
+// NewTiered layers a memory cache of the given size over remote and subscribes to the
+// invalidations of other replicas; zero values fall back to the defaults.
+func NewTiered(remote *Redis, size int, localTTL time.Duration) *Tiered {
+	if localTTL <= 0 {
+		localTTL = DefaultLocalTTL
+	}
+	t := &Tiered{
+		local:    NewMemory(size, remote.TTL),
+		remote:   remote,
+		localTTL: localTTL,
+		origin:   uuid.NewString(),
+		pubsub:   remote.Client.Subscribe(context.Background(), remote.key(InvalidationChannel)),
+	}
+	go t.listen()
+	return t
+}
+
+// listen drops the keys changed by other replicas until the subscription is closed
+func (t *Tiered) listen() {
+	for msg := range t.pubsub.Channel() {
+		var inv invalidation
+		if err := json.Unmarshal([]byte(msg.Payload), &inv); err != nil {
+			logger.Warnf("Ignoring malformed cache invalidation: %v", err)
+			continue
+		}
+		if inv.Origin == t.origin {
+			continue
+		}
+		if inv.All {
+			t.evictAll()
+		} else {
+			t.evict(inv.Keys...)
+		}
+	}
+}
+
+// HealthCheck pings Redis
+func (t *Tiered) HealthCheck(ctx context.Context) error {
+	return t.remote.HealthCheck(ctx)
+}
+
+// SetWithTTL sets a key with a custom TTL in Redis and in memory
+func (t *Tiered) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+	if err := t.remote.Client.Set(ctx, t.remote.key(key), data, ttl).Err(); err != nil {
+		return err
+	}
+	t.generation.Add(1)
+	t.local.store(key, data, ttl, t.localTTL)
+	t.publish(ctx, invalidation{Keys: []string{key}})
+	return nil
+}
+
+// Set sets a key with the default TTL
+func (t *Tiered) Set(ctx context.Context, key string, value interface{}) error {
+	return t.SetWithTTL(ctx, key, value, t.remote.TTL)
+}
+
+// Get retrieves a value and unmarshals it to the provided destination
+func (t *Tiered) Get(ctx context.Context, key string, dest interface{}) error {
+	_, err := t.GetWithTTL(ctx, key, dest)
+	return err
+}
+
+// GetWithTTL retrieves a value together with its remaining TTL in Redis
+func (t *Tiered) GetWithTTL(ctx context.Context, key string, dest interface{}) (time.Duration, error) {
+	data, ttl, ok := t.local.load(key)
+	if !ok {
+		generation := t.generation.Load()
+		var err error
+		if data, ttl, err = t.remote.getRaw(ctx, key); err != nil {
+			if errors.Is(err, ErrNotFound) {
+				t.remoteMisses.Add(1)
+			}
+			return 0, err
+		}
+		t.remoteHits.Add(1)
+		if t.generation.Load() == generation {
+			t.local.store(key, data, ttl, t.localTTL)
+		}
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return 0, err
+	}
+	return ttl, nil
+}
+
+// GetTTL returns the remaining TTL for a key in Redis
+func (t *Tiered) GetTTL(ctx context.Context, key string) (time.Duration, error) {
+	return t.remote.GetTTL(ctx, key)
+}
+
+// Exists checks if a key exists in memory or in Redis
+func (t *Tiered) Exists(ctx context.Context, key string) (bool, error) {
+	if ok, _ := t.local.Exists(ctx, key); ok {
+		return true, nil
+	}
+	return t.remote.Exists(ctx, key)
+}
+
+// Delete removes one or more keys from Redis and from every replica's memory
+func (t *Tiered) Delete(ctx context.Context, keys ...string) error {
+	if err := t.remote.Delete(ctx, keys...); err != nil {
+		return err
+	}
+	t.evict(keys...)
+	t.publish(ctx, invalidation{Keys: keys})
+	return nil
+}
+
+// Flush removes the keys of the namespace from Redis and all keys from every replica's memory
+func (t *Tiered) Flush(ctx context.Context) error {
+	if err := t.remote.Flush(ctx); err != nil {
+		return err
+	}
+	t.evictAll()
+	t.publish(ctx, invalidation{All: true})
+	return nil
+}
+
+// Scan returns one batch of the keys in Redis matching the pattern
+func (t *Tiered) Scan(ctx context.Context, cursor uint64, pattern string, count int64) ([]string, uint64, error) {
+	return t.remote.Scan(ctx, cursor, pattern, count)
+}
+
+// GetKeys returns the keys in Redis matching the pattern
+func (t *Tiered) GetKeys(ctx context.Context, pattern string) ([]string, error) {
+	return t.remote.GetKeys(ctx, pattern)
+}
+
+// Expire sets expiration for a key in Redis and drops it from every replica's memory
+func (t *Tiered) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	if err := t.remote.Expire(ctx, key, ttl); err != nil {
+		return err
+	}
+	t.evict(key)
+	t.publish(ctx, invalidation{Keys: []string{key}})
+	return nil
+}
+
+// Increment atomically increments a key's value in Redis and drops it from every replica's memory
+func (t *Tiered) Increment(ctx context.Context, key string) (int64, error) {
+	n, err := t.remote.Increment(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	t.evict(key)
+	t.publish(ctx, invalidation{Keys: []string{key}})
+	return n, nil
+}
+
+// TryLock takes the lock in Redis
+func (t *Tiered) TryLock(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	return t.remote.TryLock(ctx, key, ttl)
+}
+
+// Unlock releases a lock taken in Redis
+func (t *Tiered) Unlock(ctx context.Context, key string, token string) error {
+	return t.remote.Unlock(ctx, key, token)
+}
+
+// Close stops listening for invalidations. The Redis connection is left open for its other users.
+func (t *Tiered) Close() error {
+	return t.pubsub.Close()
+}
+
+// Stats returns the hits and misses of the memory tier and of the Redis lookups made on its misses
+func (t *Tiered) Stats() []interfaces.CacheTierStats {
+	local := t.local.Stats()
+	local.Tier = "local"
+	return []interfaces.CacheTierStats{
+		local,
+		{Tier: "redis", Hits: t.remoteHits.Load(), Misses: t.remoteMisses.Load()},
+	}
+}
+
+func (t *Tiered) evict(keys ...string) {
+	t.generation.Add(1)
+	_ = t.local.Delete(context.Background(), keys...)
+}
+
+func (t *Tiered) evictAll() {
+	t.generation.Add(1)
+	_ = t.local.Flush(context.Background())
+}
+
+// publish announces changed keys to the other replicas. A lost announcement leaves them
+// serving the old value until their copy reaches the local TTL, so it is only logged.
+func (t *Tiered) publish(ctx context.Context, inv invalidation) {
+	inv.Origin = t.origin
+	data, err := json.Marshal(inv)
+	if err != nil {
+		logger.Warnf("Failed to encode cache invalidation: %v", err)
+		return
+	}
+	if err := t.remote.Client.Publish(ctx, t.remote.key(InvalidationChannel), data).Err(); err != nil {
+		logger.Warnf("Failed to publish cache invalidation: %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/domain/weather"
+)
+
+// WeatherCache implements interfaces.WeatherCache on top of an interfaces.Cache
+type WeatherCache struct {
+	cache interfaces.Cache
+}
+
+// NewWeatherCache creates a weather cache on top of cache
+func NewWeatherCache(cache interfaces.Cache) *WeatherCache {
+	return &WeatherCache{cache: cache}
+}
+
+// Get returns the observation under key and the remaining TTL of the entry
+func (wc *WeatherCache) Get(ctx context.Context, key string) (*weather.Weather, time.Duration, error) {
+	var w *weather.Weather
+	remaining, err := wc.cache.GetWithTTL(ctx, key, &w)
+	if err != nil {
+		return nil, 0, err
+	}
+	if w == nil {
+		return nil, 0, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return w, remaining, nil
+}
+
+// GetByID returns a stored observation
+func (wc *WeatherCache) GetByID(ctx context.Context, id string) (*weather.Weather, error) {
+	w, _, err := wc.Get(ctx, interfaces.WeatherIDKey(id))
+	return w, err
+}
+
+// GetLatest returns the most recently stored observation for a city
+func (wc *WeatherCache) GetLatest(ctx context.Context, city string) (*weather.Weather, error) {
+	w, _, err := wc.Get(ctx, interfaces.WeatherLatestKey(city))
+	return w, err
+}
+
+// Put caches w under key for ttl
+func (wc *WeatherCache) Put(ctx context.Context, key string, w *weather.Weather, ttl time.Duration) error {
+	return wc.cache.SetWithTTL(ctx, key, w, ttl)
+}
+
+// PutByID caches a stored observation by its ID
+func (wc *WeatherCache) PutByID(ctx context.Context, w *weather.Weather) error {
+	return wc.cache.Set(ctx, interfaces.WeatherIDKey(w.ID.String()), w)
+}
+
+// PutLatest caches w as the most recently stored observation for its city
+func (wc *WeatherCache) PutLatest(ctx context.Context, w *weather.Weather) error {
+	return wc.cache.Set(ctx, interfaces.WeatherLatestKey(w.City), w)
+}
+
+// Store caches a newly stored observation under key for ttl, by its ID and as the latest for
+// its city. Every entry is attempted; the errors of those that failed are joined.
+func (wc *WeatherCache) Store(ctx context.Context, key string, w *weather.Weather, ttl time.Duration) error {
+	errs := []error{wc.Put(ctx, key, w, ttl), wc.PutByID(ctx, w)}
+	if w.City != "" {
+		errs = append(errs, wc.PutLatest(ctx, w))
+	}
+	return errors.Join(errs...)
+}
+
+// Delete removes entries by key
+func (wc *WeatherCache) Delete(ctx context.Context, keys ...string) error {
+	return wc.cache.Delete(ctx, keys...)
+}
+
+// Invalidate removes the entries that may hold w: by ID, by its city and country, and the
+// latest for its city
+func (wc *WeatherCache) Invalidate(ctx context.Context, w *weather.Weather) error {
+	return wc.cache.Delete(ctx,
+		interfaces.WeatherIDKey(w.ID.String()),
+		interfaces.WeatherCityKey(w.City, w.Country),
+		interfaces.WeatherLatestKey(w.City),
+	)
+}
+
+// Entries returns one batch of the weather entries whose key matches interfaces.WeatherKeyPrefix followed
+// by pattern, with their remaining TTL, and the cursor of the next batch (0 when done)
+func (wc *WeatherCache) Entries(ctx context.Context, pattern string, cursor uint64, count int64) ([]interfaces.CacheEntry, uint64, error) {
+	keys, next, err := wc.cache.Scan(ctx, cursor, interfaces.WeatherKeyPrefix+pattern, count)
+	if err != nil {
+		return nil, 0, err
+	}
+	entries := make([]interfaces.CacheEntry, 0, len(keys))
+	for _, key := range keys {
+		ttl, err := wc.cache.GetTTL(ctx, key)
+		if errors.Is(err, ErrNotFound) {
+			continue // expired or deleted since the scan
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		entry := interfaces.CacheEntry{Key: key, TTLSeconds: -1}
+		if ttl >= 0 {
+			entry.TTLSeconds = int64(ttl.Round(time.Second) / time.Second)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, next, nil
+}
+
+// Purge deletes the weather entries whose key matches interfaces.WeatherKeyPrefix followed by pattern
+// and returns how many were found. The keys are collected before any is deleted, since a
+// cursor may skip keys when the set it iterates shrinks, and a scan may return a key twice.
+func (wc *WeatherCache) Purge(ctx context.Context, pattern string) (int, error) {
+	var (
+		keys   []string
+		seen   = make(map[string]struct{})
+		cursor uint64
+	)
+	for {
+		batch, next, err := wc.cache.Scan(ctx, cursor, interfaces.WeatherKeyPrefix+pattern, 0)
+		if err != nil {
+			return 0, err
+		}
+		for _, key := range batch {
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+		if next == 0 {
+			break
+		}
+		cursor = next
+	}
+
+	for start := 0; start < len(keys); start += DefaultScanCount {
+		end := min(start+DefaultScanCount, len(keys))
+		if err := wc.cache.Delete(ctx, keys[start:end]...); err != nil {
+			return start, err
+		}
+	}
+	return len(keys), nil
+}
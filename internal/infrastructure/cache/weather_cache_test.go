@@ -0,0 +1,98 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/domain/weather"
+	"github.com/OmidRasouli/weather-api/internal/infrastructure/cache"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeatherCache_StoreAndGet(t *testing.T) {
+	s, client := newRedis(t)
+	wc := cache.NewWeatherCache(client)
+	ctx := context.Background()
+
+	w := &weather.Weather{ID: uuid.New(), City: "Tehran", Country: "IR", Temperature: 28.5}
+	key := interfaces.WeatherCityKey("Tehran", "IR")
+	assert.NoError(t, wc.Store(ctx, key, w, time.Minute))
+
+	got, ttl, err := wc.Get(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, 28.5, got.Temperature)
+	assert.Equal(t, time.Minute, ttl)
+
+	got, err = wc.GetByID(ctx, w.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, w.ID, got.ID)
+
+	got, err = wc.GetLatest(ctx, "Tehran")
+	assert.NoError(t, err)
+	assert.Equal(t, w.ID, got.ID)
+
+	// The city entry expires with its own TTL, the others with the default
+	s.FastForward(time.Minute)
+	_, _, err = wc.Get(ctx, key)
+	assert.True(t, errors.Is(err, cache.ErrNotFound))
+	_, err = wc.GetByID(ctx, w.ID.String())
+	assert.NoError(t, err)
+}
+
+func TestWeatherCache_Invalidate(t *testing.T) {
+	_, client := newRedis(t)
+	wc := cache.NewWeatherCache(client)
+	ctx := context.Background()
+
+	w := &weather.Weather{ID: uuid.New(), City: "Tehran", Country: "IR"}
+	other := &weather.Weather{ID: uuid.New(), City: "Berlin", Country: "DE"}
+	assert.NoError(t, wc.Store(ctx, interfaces.WeatherCityKey("Tehran", "IR"), w, time.Minute))
+	assert.NoError(t, wc.Store(ctx, interfaces.WeatherCityKey("Berlin", "DE"), other, time.Minute))
+
+	assert.NoError(t, wc.Invalidate(ctx, w))
+
+	_, _, err := wc.Get(ctx, interfaces.WeatherCityKey("Tehran", "IR"))
+	assert.True(t, errors.Is(err, cache.ErrNotFound))
+	_, err = wc.GetByID(ctx, w.ID.String())
+	assert.True(t, errors.Is(err, cache.ErrNotFound))
+	_, err = wc.GetLatest(ctx, "Tehran")
+	assert.True(t, errors.Is(err, cache.ErrNotFound))
+
+	_, err = wc.GetLatest(ctx, "Berlin")
+	assert.NoError(t, err)
+}
+
+func TestWeatherCache_EntriesAndPurge(t *testing.T) {
+	_, client := newRedis(t)
+	wc := cache.NewWeatherCache(client)
+	ctx := context.Background()
+
+	tehran := &weather.Weather{ID: uuid.New(), City: "Tehran", Country: "IR"}
+	berlin := &weather.Weather{ID: uuid.New(), City: "Berlin", Country: "DE"}
+	assert.NoError(t, wc.Put(ctx, interfaces.WeatherCityKey("Tehran", "IR"), tehran, time.Minute))
+	assert.NoError(t, wc.Put(ctx, interfaces.WeatherCityKey("Berlin", "DE"), berlin, time.Minute))
+	assert.NoError(t, wc.PutByID(ctx, tehran))
+	assert.NoError(t, client.Set(ctx, "forecast:Tehran:IR", "value"))
+
+	entries, cursor, err := wc.Entries(ctx, "city:*", 0, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), cursor)
+	assert.ElementsMatch(t, []interfaces.CacheEntry{
+		{Key: "weather:city:Tehran:IR", TTLSeconds: 60},
+		{Key: "weather:city:Berlin:DE", TTLSeconds: 60},
+	}, entries)
+
+	deleted, err := wc.Purge(ctx, "city:*")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+
+	entries, _, err = wc.Entries(ctx, "*", 0, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []interfaces.CacheEntry{{Key: interfaces.WeatherIDKey(tehran.ID.String()), TTLSeconds: 600}}, entries)
+	ok, _ := client.Exists(ctx, "forecast:Tehran:IR")
+	assert.True(t, ok)
+}
@@ -0,0 +1,71 @@
+package alert
+
+import "github.com/OmidRasouli/weather-api/internal/domain/alert"
+
+// map from domain to db model
+func toRuleModel(r *alert.Rule) *ruleModel {
+	return &ruleModel{
+		ID:         r.ID,
+		Name:       r.Name,
+		City:       r.City,
+		Country:    r.Country,
+		Field:      r.Field,
+		Operator:   string(r.Operator),
+		Threshold:  r.Threshold,
+		WebhookURL: r.WebhookURL,
+		Secret:     r.Secret,
+		Enabled:    r.Enabled,
+		CreatedAt:  r.CreatedAt,
+		UpdatedAt:  r.UpdatedAt,
+	}
+}
+
+// map from db model to domain
+func toRule(m *ruleModel) *alert.Rule {
+	return &alert.Rule{
+		ID:         m.ID,
+		Name:       m.Name,
+		City:       m.City,
+		Country:    m.Country,
+		Field:      m.Field,
+		Operator:   alert.Operator(m.Operator),
+		Threshold:  m.Threshold,
+		WebhookURL: m.WebhookURL,
+		Secret:     m.Secret,
+		Enabled:    m.Enabled,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+// map from domain to db model
+func toDeliveryModel(d *alert.Delivery) *deliveryModel {
+	return &deliveryModel{
+		ID:         d.ID,
+		RuleID:     d.RuleID,
+		WeatherID:  d.WeatherID,
+		URL:        d.URL,
+		Attempts:   d.Attempts,
+		StatusCode: d.StatusCode,
+		Succeeded:  d.Succeeded,
+		Error:      d.Error,
+		CreatedAt:  d.CreatedAt,
+		UpdatedAt:  d.UpdatedAt,
+	}
+}
+
+// map from db model to domain
+func toDelivery(m *deliveryModel) *alert.Delivery {
+	return &alert.Delivery{
+		ID:         m.ID,
+		RuleID:     m.RuleID,
+		WeatherID:  m.WeatherID,
+		URL:        m.URL,
+		Attempts:   m.Attempts,
+		StatusCode: m.StatusCode,
+		Succeeded:  m.Succeeded,
+		Error:      m.Error,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
@@ -0,0 +1,43 @@
+package alert
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ruleModel struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Name       string
+	City       string
+	Country    string
+	Field      string
+	Operator   string
+	Threshold  float64
+	WebhookURL string
+	Secret     string
+	Enabled    bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (ruleModel) TableName() string {
+	return "alert_rules"
+}
+
+type deliveryModel struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	RuleID     uuid.UUID `gorm:"type:uuid"`
+	WeatherID  uuid.UUID `gorm:"type:uuid"`
+	URL        string
+	Attempts   int
+	StatusCode int
+	Succeeded  bool
+	Error      string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (deliveryModel) TableName() string {
+	return "alert_deliveries"
+}
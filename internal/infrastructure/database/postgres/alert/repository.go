@@ -0,0 +1,98 @@
+package alert
+
+import (
+	"context"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/domain/alert"
+	"gorm.io/gorm"
+)
+
+type RulePostgresRepository struct {
+	db interfaces.Database
+}
+
+func NewRulePostgresRepository(db interfaces.Database) interfaces.AlertRuleRepository {
+	return &RulePostgresRepository{db: db}
+}
+
+func (r *RulePostgresRepository) Create(ctx context.Context, rule *alert.Rule) error {
+	return r.db.WithContext(ctx).Create(toRuleModel(rule)).Error
+}
+
+func (r *RulePostgresRepository) FindByID(ctx context.Context, id string) (*alert.Rule, error) {
+	var model ruleModel
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return toRule(&model), nil
+}
+
+func (r *RulePostgresRepository) FindAll(ctx context.Context) ([]*alert.Rule, error) {
+	return r.find(r.db.WithContext(ctx).Order("created_at"))
+}
+
+func (r *RulePostgresRepository) FindEnabledForLocation(ctx context.Context, city string, country string) ([]*alert.Rule, error) {
+	return r.find(r.db.WithContext(ctx).
+		Where("enabled").
+		Where("city = '' OR lower(city) = lower(?)", city).
+		Where("country = '' OR upper(country) = upper(?)", country))
+}
+
+func (r *RulePostgresRepository) find(tx *gorm.DB) ([]*alert.Rule, error) {
+	var models []ruleModel
+	if err := tx.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*alert.Rule, 0, len(models))
+	for i := range models {
+		result = append(result, toRule(&models[i]))
+	}
+	return result, nil
+}
+
+func (r *RulePostgresRepository) Update(ctx context.Context, rule *alert.Rule) error {
+	return r.db.WithContext(ctx).Save(toRuleModel(rule)).Error
+}
+
+func (r *RulePostgresRepository) Delete(ctx context.Context, id string) error {
+	res := r.db.WithContext(ctx).Delete(&ruleModel{}, "id = ?", id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+type DeliveryPostgresRepository struct {
+	db interfaces.Database
+}
+
+func NewDeliveryPostgresRepository(db interfaces.Database) interfaces.AlertDeliveryRepository {
+	return &DeliveryPostgresRepository{db: db}
+}
+
+func (r *DeliveryPostgresRepository) Save(ctx context.Context, d *alert.Delivery) error {
+	return r.db.WithContext(ctx).Save(toDeliveryModel(d)).Error
+}
+
+func (r *DeliveryPostgresRepository) FindByRule(ctx context.Context, ruleID string, limit int) ([]*alert.Delivery, error) {
+	var models []deliveryModel
+	err := r.db.WithContext(ctx).
+		Where("rule_id = ?", ruleID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*alert.Delivery, 0, len(models))
+	for i := range models {
+		result = append(result, toDelivery(&models[i]))
+	}
+	return result, nil
+}
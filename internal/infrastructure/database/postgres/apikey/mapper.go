@@ -0,0 +1,39 @@
+package apikey
+
+import (
+	"strings"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/apikey"
+)
+
+// map from domain to db model
+func toDBModel(k *apikey.Key) *apiKeyModel {
+	return &apiKeyModel{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Hash:       k.Hash,
+		Scopes:     strings.Join(k.Scopes, " "),
+		CreatedBy:  k.CreatedBy,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+		UpdatedAt:  k.UpdatedAt,
+	}
+}
+
+// map from db model to domain
+func toDomainModel(m *apiKeyModel) *apikey.Key {
+	return &apikey.Key{
+		ID:         m.ID,
+		Name:       m.Name,
+		Prefix:     m.Prefix,
+		Hash:       m.Hash,
+		Scopes:     strings.Fields(m.Scopes),
+		CreatedBy:  m.CreatedBy,
+		LastUsedAt: m.LastUsedAt,
+		RevokedAt:  m.RevokedAt,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
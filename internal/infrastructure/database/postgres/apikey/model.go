@@ -0,0 +1,24 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type apiKeyModel struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Name       string
+	Prefix     string
+	Hash       string
+	Scopes     string // space-separated
+	CreatedBy  string
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (apiKeyModel) TableName() string {
+	return "api_keys"
+}
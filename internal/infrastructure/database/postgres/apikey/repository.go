@@ -0,0 +1,73 @@
+package apikey
+
+import (
+	"context"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/domain/apikey"
+	"gorm.io/gorm"
+)
+
+type APIKeyPostgresRepository struct {
+	db interfaces.Database
+}
+
+func NewAPIKeyPostgresRepository(db interfaces.Database) interfaces.APIKeyRepository {
+	return &APIKeyPostgresRepository{db: db}
+}
+
+func (r *APIKeyPostgresRepository) Create(ctx context.Context, k *apikey.Key) error {
+	return r.db.WithContext(ctx).Create(toDBModel(k)).Error
+}
+
+func (r *APIKeyPostgresRepository) FindByID(ctx context.Context, id string) (*apikey.Key, error) {
+	var model apiKeyModel
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return toDomainModel(&model), nil
+}
+
+func (r *APIKeyPostgresRepository) FindByPrefix(ctx context.Context, prefix string) (*apikey.Key, error) {
+	var model apiKeyModel
+	if err := r.db.WithContext(ctx).First(&model, "prefix = ?", prefix).Error; err != nil {
+		return nil, err
+	}
+	return toDomainModel(&model), nil
+}
+
+func (r *APIKeyPostgresRepository) FindAll(ctx context.Context) ([]*apikey.Key, error) {
+	var models []apiKeyModel
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*apikey.Key, 0, len(models))
+	for i := range models {
+		result = append(result, toDomainModel(&models[i]))
+	}
+	return result, nil
+}
+
+func (r *APIKeyPostgresRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	res := r.db.WithContext(ctx).Model(&apiKeyModel{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"revoked_at": gorm.Expr("COALESCE(revoked_at, ?)", at),
+			"updated_at": at,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *APIKeyPostgresRepository) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&apiKeyModel{}).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", at).Error
+}
@@ -0,0 +1,51 @@
+package forecast
+
+import (
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/forecast"
+	"github.com/google/uuid"
+)
+
+// map from domain to db models, one row per forecast entry
+func toDBModels(f *forecast.Forecast, now time.Time) []forecastModel {
+	models := make([]forecastModel, 0, len(f.Entries))
+	for _, e := range f.Entries {
+		models = append(models, forecastModel{
+			ID:          uuid.New(),
+			City:        f.City,
+			Country:     f.Country,
+			ForecastAt:  e.ForecastAt,
+			Temperature: e.Temperature,
+			Description: e.Description,
+			Humidity:    e.Humidity,
+			WindSpeed:   e.WindSpeed,
+			FetchedAt:   f.FetchedAt,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+	}
+	return models
+}
+
+// map from db models to domain
+func toDomainModel(models []forecastModel) *forecast.Forecast {
+	f := &forecast.Forecast{
+		City:    models[0].City,
+		Country: models[0].Country,
+		Entries: make([]forecast.Entry, 0, len(models)),
+	}
+	for _, m := range models {
+		if m.FetchedAt.After(f.FetchedAt) {
+			f.FetchedAt = m.FetchedAt
+		}
+		f.Entries = append(f.Entries, forecast.Entry{
+			ForecastAt:  m.ForecastAt,
+			Temperature: m.Temperature,
+			Description: m.Description,
+			Humidity:    m.Humidity,
+			WindSpeed:   m.WindSpeed,
+		})
+	}
+	return f
+}
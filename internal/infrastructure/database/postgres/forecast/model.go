@@ -0,0 +1,25 @@
+package forecast
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type forecastModel struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	City        string
+	Country     string
+	ForecastAt  time.Time
+	Temperature float64
+	Description string
+	Humidity    int
+	WindSpeed   float64
+	FetchedAt   time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (forecastModel) TableName() string {
+	return "forecast"
+}
@@ -0,0 +1,51 @@
+package forecast
+
+import (
+	"context"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/domain/forecast"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ForecastPostgresRepository struct {
+	db interfaces.Database
+}
+
+func NewForecastPostgresRepository(db interfaces.Database) interfaces.ForecastRepository {
+	return &ForecastPostgresRepository{db: db}
+}
+
+func (r *ForecastPostgresRepository) Save(ctx context.Context, f *forecast.Forecast) error {
+	models := toDBModels(f, time.Now())
+	if len(models) == 0 {
+		return nil
+	}
+
+	// A newer fetch replaces the prediction previously stored for the same time slot
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "city"}, {Name: "country"}, {Name: "forecast_at"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"temperature", "description", "humidity", "wind_speed", "fetched_at", "updated_at",
+			}),
+		}).
+		Create(&models).Error
+}
+
+func (r *ForecastPostgresRepository) FindUpcoming(ctx context.Context, city string, country string, from time.Time) (*forecast.Forecast, error) {
+	// Forecasts are stored under the requested country, so an empty one only matches those
+	// fetched without a country, as in the cache key, and namesakes are never mixed
+	tx := r.db.WithContext(ctx).Where("city = ? AND country = ? AND forecast_at >= ?", city, country, from)
+
+	var models []forecastModel
+	if err := tx.Order("forecast_at").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	if len(models) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return toDomainModel(models), nil
+}
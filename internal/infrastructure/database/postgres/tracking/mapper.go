@@ -0,0 +1,39 @@
+package tracking
+
+import (
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/tracking"
+)
+
+// map from domain to db model
+func toDBModel(l *tracking.Location) *trackedLocationModel {
+	return &trackedLocationModel{
+		ID:                     l.ID,
+		City:                   l.City,
+		Country:                l.Country,
+		RefreshIntervalSeconds: int64(l.RefreshInterval / time.Second),
+		Enabled:                l.Enabled,
+		NextRunAt:              l.NextRunAt,
+		LastRefreshedAt:        l.LastRefreshedAt,
+		LastError:              l.LastError,
+		CreatedAt:              l.CreatedAt,
+		UpdatedAt:              l.UpdatedAt,
+	}
+}
+
+// map from db model to domain
+func toDomainModel(m *trackedLocationModel) *tracking.Location {
+	return &tracking.Location{
+		ID:              m.ID,
+		City:            m.City,
+		Country:         m.Country,
+		RefreshInterval: time.Duration(m.RefreshIntervalSeconds) * time.Second,
+		Enabled:         m.Enabled,
+		NextRunAt:       m.NextRunAt,
+		LastRefreshedAt: m.LastRefreshedAt,
+		LastError:       m.LastError,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+	}
+}
@@ -0,0 +1,24 @@
+package tracking
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type trackedLocationModel struct {
+	ID                     uuid.UUID `gorm:"type:uuid;primaryKey"`
+	City                   string
+	Country                string
+	RefreshIntervalSeconds int64
+	Enabled                bool
+	NextRunAt              time.Time
+	LastRefreshedAt        *time.Time
+	LastError              string
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
+}
+
+func (trackedLocationModel) TableName() string {
+	return "tracked_locations"
+}
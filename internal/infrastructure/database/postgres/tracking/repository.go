@@ -0,0 +1,110 @@
+package tracking
+
+import (
+	"context"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/domain/tracking"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TrackedLocationPostgresRepository struct {
+	db interfaces.Database
+}
+
+func NewTrackedLocationPostgresRepository(db interfaces.Database) interfaces.TrackedLocationRepository {
+	return &TrackedLocationPostgresRepository{db: db}
+}
+
+func (r *TrackedLocationPostgresRepository) Create(ctx context.Context, l *tracking.Location) error {
+	return r.db.WithContext(ctx).Create(toDBModel(l)).Error
+}
+
+func (r *TrackedLocationPostgresRepository) FindByID(ctx context.Context, id string) (*tracking.Location, error) {
+	var model trackedLocationModel
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return toDomainModel(&model), nil
+}
+
+func (r *TrackedLocationPostgresRepository) FindAll(ctx context.Context) ([]*tracking.Location, error) {
+	var models []trackedLocationModel
+	if err := r.db.WithContext(ctx).Order("city").Order("country").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*tracking.Location, 0, len(models))
+	for i := range models {
+		result = append(result, toDomainModel(&models[i]))
+	}
+	return result, nil
+}
+
+func (r *TrackedLocationPostgresRepository) Update(ctx context.Context, l *tracking.Location) error {
+	return r.db.WithContext(ctx).Save(toDBModel(l)).Error
+}
+
+func (r *TrackedLocationPostgresRepository) Delete(ctx context.Context, id string) error {
+	res := r.db.WithContext(ctx).Delete(&trackedLocationModel{}, "id = ?", id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *TrackedLocationPostgresRepository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*tracking.Location, error) {
+	var models []trackedLocationModel
+
+	// FOR UPDATE SKIP LOCKED lets each replica claim a disjoint set of rows; moving
+	// next_run_at forward inside the same transaction keeps them claimed after commit.
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("enabled AND next_run_at <= ?", now).
+			Order("next_run_at").
+			Limit(limit).
+			Find(&models).Error
+		if err != nil || len(models) == 0 {
+			return err
+		}
+
+		for i := range models {
+			m := &models[i]
+			next := now.Add(time.Duration(m.RefreshIntervalSeconds) * time.Second)
+			if err := tx.Model(m).UpdateColumn("next_run_at", next).Error; err != nil {
+				return err
+			}
+			m.NextRunAt = next
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*tracking.Location, 0, len(models))
+	for i := range models {
+		result = append(result, toDomainModel(&models[i]))
+	}
+	return result, nil
+}
+
+func (r *TrackedLocationPostgresRepository) MarkRefreshed(ctx context.Context, id string, at time.Time, refreshErr error) error {
+	lastError := ""
+	if refreshErr != nil {
+		lastError = refreshErr.Error()
+	}
+	return r.db.WithContext(ctx).
+		Model(&trackedLocationModel{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"last_refreshed_at": at,
+			"last_error":        lastError,
+			"updated_at":        at,
+		}).Error
+}
@@ -0,0 +1,35 @@
+package user
+
+import "github.com/OmidRasouli/weather-api/internal/domain/user"
+
+// map from domain to db model
+func toDBModel(u *user.User) *userModel {
+	return &userModel{
+		ID:              u.ID,
+		Username:        u.Username,
+		PasswordHash:    u.PasswordHash,
+		Role:            string(u.Role),
+		Disabled:        u.Disabled,
+		IdentityIssuer:  u.IdentityIssuer,
+		IdentitySubject: u.IdentitySubject,
+		LastLoginAt:     u.LastLoginAt,
+		CreatedAt:       u.CreatedAt,
+		UpdatedAt:       u.UpdatedAt,
+	}
+}
+
+// map from db model to domain
+func toDomainModel(m *userModel) *user.User {
+	return &user.User{
+		ID:              m.ID,
+		Username:        m.Username,
+		PasswordHash:    m.PasswordHash,
+		Role:            user.Role(m.Role),
+		Disabled:        m.Disabled,
+		IdentityIssuer:  m.IdentityIssuer,
+		IdentitySubject: m.IdentitySubject,
+		LastLoginAt:     m.LastLoginAt,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+	}
+}
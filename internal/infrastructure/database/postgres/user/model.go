@@ -0,0 +1,24 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type userModel struct {
+	ID              uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Username        string
+	PasswordHash    string
+	Role            string
+	Disabled        bool
+	IdentityIssuer  string
+	IdentitySubject string
+	LastLoginAt     *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (userModel) TableName() string {
+	return "users"
+}
@@ -0,0 +1,77 @@
+package user
+
+import (
+	"context"
+	"errors"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/domain/user"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uniqueViolation is the PostgreSQL error code for a unique constraint violation
+const uniqueViolation = "23505"
+
+type UserPostgresRepository struct {
+	db interfaces.Database
+}
+
+func NewUserPostgresRepository(db interfaces.Database) interfaces.UserRepository {
+	return &UserPostgresRepository{db: db}
+}
+
+func (r *UserPostgresRepository) Create(ctx context.Context, u *user.User) error {
+	err := r.db.WithContext(ctx).Create(toDBModel(u)).Error
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return user.ErrUsernameTaken
+	}
+	return err
+}
+
+func (r *UserPostgresRepository) FindByID(ctx context.Context, id string) (*user.User, error) {
+	var model userModel
+	if err := r.db.WithContext(ctx).First(&model, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return toDomainModel(&model), nil
+}
+
+func (r *UserPostgresRepository) FindByUsername(ctx context.Context, username string) (*user.User, error) {
+	var model userModel
+	if err := r.db.WithContext(ctx).First(&model, "lower(username) = lower(?)", username).Error; err != nil {
+		return nil, err
+	}
+	return toDomainModel(&model), nil
+}
+
+func (r *UserPostgresRepository) FindByIdentity(ctx context.Context, issuer string, subject string) (*user.User, error) {
+	var model userModel
+	if err := r.db.WithContext(ctx).First(&model, "identity_issuer = ? AND identity_subject = ?", issuer, subject).Error; err != nil {
+		return nil, err
+	}
+	return toDomainModel(&model), nil
+}
+
+func (r *UserPostgresRepository) FindAll(ctx context.Context) ([]*user.User, error) {
+	var models []userModel
+	if err := r.db.WithContext(ctx).Order("username").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*user.User, 0, len(models))
+	for i := range models {
+		result = append(result, toDomainModel(&models[i]))
+	}
+	return result, nil
+}
+
+func (r *UserPostgresRepository) Update(ctx context.Context, u *user.User) error {
+	return r.db.WithContext(ctx).Save(toDBModel(u)).Error
+}
+
+func (r *UserPostgresRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&userModel{}).Count(&count).Error
+	return count, err
+}
@@ -8,11 +8,28 @@ func toDBModel(w *weather.Weather) *weatherModel {
 		ID:          w.ID,
 		City:        w.City,
 		Country:     w.Country,
+		Latitude:    w.Latitude,
+		Longitude:   w.Longitude,
 		Temperature: w.Temperature,
+		FeelsLike:   w.FeelsLike,
+		TempMin:     w.TempMin,
+		TempMax:     w.TempMax,
 		Description: w.Description,
+		ConditionID: w.ConditionID,
+		Icon:        w.Icon,
 		Humidity:    w.Humidity,
+		Pressure:    w.Pressure,
+		Visibility:  w.Visibility,
+		Clouds:      w.Clouds,
 		WindSpeed:   w.WindSpeed,
+		WindDeg:     w.WindDeg,
+		WindGust:    w.WindGust,
+		Rain:        w.Rain,
+		Snow:        w.Snow,
+		Sunrise:     w.Sunrise,
+		Sunset:      w.Sunset,
 		FetchedAt:   w.FetchedAt,
+		Provider:    w.Provider,
 		CreatedAt:   w.CreatedAt,
 		UpdatedAt:   w.UpdatedAt,
 	}
@@ -24,12 +41,46 @@ func toDomainModel(m *weatherModel) *weather.Weather {
 		ID:          m.ID,
 		City:        m.City,
 		Country:     m.Country,
+		Latitude:    m.Latitude,
+		Longitude:   m.Longitude,
 		Temperature: m.Temperature,
+		FeelsLike:   m.FeelsLike,
+		TempMin:     m.TempMin,
+		TempMax:     m.TempMax,
 		Description: m.Description,
+		ConditionID: m.ConditionID,
+		Icon:        m.Icon,
 		Humidity:    m.Humidity,
+		Pressure:    m.Pressure,
+		Visibility:  m.Visibility,
+		Clouds:      m.Clouds,
 		WindSpeed:   m.WindSpeed,
+		WindDeg:     m.WindDeg,
+		WindGust:    m.WindGust,
+		Rain:        m.Rain,
+		Snow:        m.Snow,
+		Sunrise:     m.Sunrise,
+		Sunset:      m.Sunset,
 		FetchedAt:   m.FetchedAt,
+		Provider:    m.Provider,
 		CreatedAt:   m.CreatedAt,
 		UpdatedAt:   m.UpdatedAt,
 	}
 }
+
+// map from aggregation row to domain
+func toHistoryBucket(r *historyRow) *weather.HistoryBucket {
+	return &weather.HistoryBucket{
+		Start:          r.BucketStart.UTC(),
+		Samples:        r.Samples,
+		MinTemperature: r.MinTemperature,
+		MaxTemperature: r.MaxTemperature,
+		AvgTemperature: r.AvgTemperature,
+		MinHumidity:    r.MinHumidity,
+		MaxHumidity:    r.MaxHumidity,
+		AvgHumidity:    r.AvgHumidity,
+		MinWindSpeed:   r.MinWindSpeed,
+		MaxWindSpeed:   r.MaxWindSpeed,
+		AvgWindSpeed:   r.AvgWindSpeed,
+	}
+}
@@ -10,11 +10,28 @@ type weatherModel struct {
 	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
 	City        string
 	Country     string
+	Latitude    *float64
+	Longitude   *float64
 	Temperature float64
+	FeelsLike   float64
+	TempMin     float64
+	TempMax     float64
 	Description string
+	ConditionID int
+	Icon        string
 	Humidity    int
+	Pressure    int
+	Visibility  int
+	Clouds      int
 	WindSpeed   float64
+	WindDeg     int
+	WindGust    float64
+	Rain        float64
+	Snow        float64
+	Sunrise     *time.Time
+	Sunset      *time.Time
 	FetchedAt   time.Time
+	Provider    string
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
@@ -22,3 +39,24 @@ type weatherModel struct {
 func (weatherModel) TableName() string {
 	return "weather"
 }
+
+// nearbyRow is a weather row annotated with its haversine distance from the query point.
+type nearbyRow struct {
+	weatherModel
+	DistanceKm float64
+}
+
+// historyRow is the result row of the bucketed history aggregation query.
+type historyRow struct {
+	BucketStart    time.Time
+	Samples        int
+	MinTemperature float64
+	MaxTemperature float64
+	AvgTemperature float64
+	MinHumidity    int
+	MaxHumidity    int
+	AvgHumidity    float64
+	MinWindSpeed   float64
+	MaxWindSpeed   float64
+	AvgWindSpeed   float64
+}
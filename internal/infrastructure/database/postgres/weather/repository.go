@@ -2,9 +2,12 @@ package weather
 
 import (
 	"context"
+	"time"
 
 	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
 	"github.com/OmidRasouli/weather-api/internal/domain/weather"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type WeatherPostgresRepository struct {
@@ -40,6 +43,52 @@ func (r *WeatherPostgresRepository) FindAll(ctx context.Context) ([]*weather.Wea
 	return result, nil
 }
 
+func (r *WeatherPostgresRepository) FindByQuery(ctx context.Context, q weather.Query) ([]*weather.Weather, int64, error) {
+	q.Normalize()
+
+	// filtered builds a fresh statement each time so Count and Find don't share state
+	filtered := func() *gorm.DB {
+		tx := r.db.WithContext(ctx).Model(&weatherModel{})
+		if q.City != "" {
+			tx = tx.Where("city = ?", q.City)
+		}
+		if q.Country != "" {
+			tx = tx.Where("country = ?", q.Country)
+		}
+		if !q.FetchedFrom.IsZero() {
+			tx = tx.Where("fetched_at >= ?", q.FetchedFrom)
+		}
+		if !q.FetchedTo.IsZero() {
+			tx = tx.Where("fetched_at <= ?", q.FetchedTo)
+		}
+		return tx
+	}
+
+	var total int64
+	if err := filtered().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// SortBy is validated by Normalize, so it is safe to use as a column name.
+	// id is appended as a tie-breaker to keep pages stable.
+	var models []weatherModel
+	err := filtered().
+		Order(clause.OrderByColumn{Column: clause.Column{Name: q.SortBy}, Desc: q.Descending}).
+		Order("id").
+		Limit(q.Limit).
+		Offset(q.Offset).
+		Find(&models).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]*weather.Weather, 0, len(models))
+	for _, m := range models {
+		result = append(result, toDomainModel(&m))
+	}
+	return result, total, nil
+}
+
 func (r *WeatherPostgresRepository) FindLatestByCity(ctx context.Context, city string) (*weather.Weather, error) {
 	var m weatherModel
 	err := r.db.WithContext(ctx).
@@ -52,6 +101,96 @@ func (r *WeatherPostgresRepository) FindLatestByCity(ctx context.Context, city s
 	return toDomainModel(&m), nil
 }
 
+// historyQuery groups observations into fixed-width buckets by flooring the epoch of fetched_at.
+const historyQuery = `
+SELECT to_timestamp(floor(extract(epoch FROM fetched_at) / @bucket) * @bucket) AS bucket_start,
+       count(*)                AS samples,
+       min(temperature)        AS min_temperature,
+       max(temperature)        AS max_temperature,
+       avg(temperature)        AS avg_temperature,
+       min(humidity)           AS min_humidity,
+       max(humidity)           AS max_humidity,
+       avg(humidity)           AS avg_humidity,
+       min(wind_speed)         AS min_wind_speed,
+       max(wind_speed)         AS max_wind_speed,
+       avg(wind_speed)         AS avg_wind_speed
+FROM weather
+WHERE city = @city
+  AND (@country = '' OR country = @country)
+  AND fetched_at >= @from
+  AND fetched_at < @to
+GROUP BY bucket_start
+ORDER BY bucket_start`
+
+func (r *WeatherPostgresRepository) FindHistory(ctx context.Context, q weather.HistoryQuery) ([]*weather.HistoryBucket, error) {
+	var rows []historyRow
+	err := r.db.WithContext(ctx).Raw(historyQuery, map[string]interface{}{
+		"bucket":  int64(q.Bucket / time.Second),
+		"city":    q.City,
+		"country": q.Country,
+		"from":    q.From.UTC(),
+		"to":      q.To.UTC(),
+	}).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*weather.HistoryBucket, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toHistoryBucket(&row))
+	}
+	return result, nil
+}
+
+// nearbyQuery computes the haversine distance of every located observation inside a latitude
+// bounding box around the query point, then keeps those within the radius.
+const nearbyQuery = `
+SELECT * FROM (
+    SELECT weather.*,
+           2 * @earth_radius * asin(sqrt(
+               power(sin(radians(latitude - @lat) / 2), 2) +
+               cos(radians(@lat)) * cos(radians(latitude)) *
+               power(sin(radians(longitude - @lon) / 2), 2)
+           )) AS distance_km
+    FROM weather
+    WHERE latitude IS NOT NULL
+      AND longitude IS NOT NULL
+      AND latitude BETWEEN @min_lat AND @max_lat
+) nearby
+WHERE distance_km <= @radius
+ORDER BY distance_km, fetched_at DESC
+LIMIT @limit`
+
+func (r *WeatherPostgresRepository) FindNearby(ctx context.Context, q weather.NearbyQuery) ([]*weather.NearbyResult, error) {
+	q.Normalize()
+
+	// One degree of latitude is ~111 km everywhere, which bounds the search cheaply
+	latDelta := q.RadiusKm / 111.0
+
+	var rows []nearbyRow
+	err := r.db.WithContext(ctx).Raw(nearbyQuery, map[string]interface{}{
+		"earth_radius": weather.EarthRadiusKm,
+		"lat":          q.Latitude,
+		"lon":          q.Longitude,
+		"min_lat":      q.Latitude - latDelta,
+		"max_lat":      q.Latitude + latDelta,
+		"radius":       q.RadiusKm,
+		"limit":        q.Limit,
+	}).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*weather.NearbyResult, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, &weather.NearbyResult{
+			Weather:    toDomainModel(&row.weatherModel),
+			DistanceKm: row.DistanceKm,
+		})
+	}
+	return result, nil
+}
+
 func (r *WeatherPostgresRepository) Update(ctx context.Context, w *weather.Weather) error {
 	return r.db.WithContext(ctx).Save(toDBModel(w)).Error
 }
@@ -0,0 +1,194 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/user"
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// Config describes the OpenID Connect issuer logins are delegated to.
+type Config struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	// Audiences lists the aud values accepted on bearer tokens besides the client ID,
+	// e.g. the API identifier the issuer puts in access tokens
+	Audiences     []string
+	UsernameClaim string
+	GroupsClaim   string
+	// GroupRoles maps IdP group names to role names; DefaultRole is given to users in
+	// none of them and may be empty to reject them
+	GroupRoles  map[string]string
+	DefaultRole string
+}
+
+// Provider authenticates users against an OpenID Connect issuer using its discovery
+// document, and verifies tokens it issued with the keys it publishes in its JWKS.
+type Provider struct {
+	cfg        Config
+	oauth      oauth2.Config
+	verifier   *gooidc.IDTokenVerifier
+	groupRoles map[string]user.Role
+	fallback   user.Role
+}
+
+// NewProvider fetches the issuer's discovery document. The JWKS is fetched lazily and
+// refreshed when a token names an unknown key.
+func NewProvider(ctx context.Context, cfg Config) (*Provider, error) {
+	if cfg.ClientID == "" {
+		return nil, errors.New("oidc: client ID is required")
+	}
+
+	groupRoles := make(map[string]user.Role, len(cfg.GroupRoles))
+	for group, name := range cfg.GroupRoles {
+		role, err := user.ParseRole(name)
+		if err != nil {
+			return nil, fmt.Errorf("oidc: group %q: %w", group, err)
+		}
+		groupRoles[group] = role
+	}
+	var fallback user.Role
+	if cfg.DefaultRole != "" {
+		role, err := user.ParseRole(cfg.DefaultRole)
+		if err != nil {
+			return nil, fmt.Errorf("oidc: default role: %w", err)
+		}
+		fallback = role
+	}
+	if cfg.UsernameClaim == "" {
+		cfg.UsernameClaim = "preferred_username"
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = "groups"
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{gooidc.ScopeOpenID, "profile", "email"}
+	}
+
+	provider, err := gooidc.NewProvider(ctx, cfg.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: discovery failed: %w", err)
+	}
+
+	return &Provider{
+		cfg: cfg,
+		oauth: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       cfg.Scopes,
+		},
+		// The audience is checked by audienceAllowed, since access tokens are often
+		// issued for an API identifier rather than the client ID
+		verifier:   provider.Verifier(&gooidc.Config{SkipClientIDCheck: true}),
+		groupRoles: groupRoles,
+		fallback:   fallback,
+	}, nil
+}
+
+// AuthCodeURL returns the authorization endpoint URL, requesting a PKCE S256 challenge for verifier.
+func (p *Provider) AuthCodeURL(state string, nonce string, verifier string) string {
+	return p.oauth.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier))
+}
+
+// Exchange redeems the authorization code and verifies the returned ID token, including its nonce.
+func (p *Provider) Exchange(ctx context.Context, code string, nonce string, verifier string) (*user.Identity, error) {
+	token, err := p.oauth.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("oidc: code exchange failed: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("oidc: token response has no id_token")
+	}
+
+	idToken, err := p.verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, err
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("oidc: ID token nonce does not match")
+	}
+	return p.identity(idToken)
+}
+
+// VerifyToken validates a bearer token issued by the issuer: signature, issuer, expiry and audience.
+func (p *Provider) VerifyToken(ctx context.Context, rawToken string) (*user.Identity, error) {
+	idToken, err := p.verify(ctx, rawToken)
+	if err != nil {
+		return nil, err
+	}
+	return p.identity(idToken)
+}
+
+// verify checks a token against the issuer's JWKS and the accepted audiences
+func (p *Provider) verify(ctx context.Context, rawToken string) (*gooidc.IDToken, error) {
+	idToken, err := p.verifier.Verify(ctx, rawToken)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: %w", err)
+	}
+	if !p.audienceAllowed(idToken.Audience) {
+		return nil, fmt.Errorf("oidc: token audience %v is not accepted", idToken.Audience)
+	}
+	return idToken, nil
+}
+
+func (p *Provider) audienceAllowed(audience []string) bool {
+	for _, aud := range audience {
+		if aud == p.cfg.ClientID || slices.Contains(p.cfg.Audiences, aud) {
+			return true
+		}
+	}
+	return false
+}
+
+// identity reads the username, groups and token ID claims and maps the groups to a role
+func (p *Provider) identity(idToken *gooidc.IDToken) (*user.Identity, error) {
+	var claims map[string]interface{}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("oidc: invalid claims: %w", err)
+	}
+
+	username, _ := claims[p.cfg.UsernameClaim].(string)
+	if username == "" {
+		username = idToken.Subject
+	}
+	groups := stringList(claims[p.cfg.GroupsClaim])
+	tokenID, _ := claims["jti"].(string)
+
+	return &user.Identity{
+		Issuer:    idToken.Issuer,
+		Subject:   idToken.Subject,
+		Username:  username,
+		Groups:    groups,
+		Role:      user.RoleForGroups(groups, p.groupRoles, p.fallback),
+		TokenID:   tokenID,
+		ExpiresAt: idToken.Expiry,
+	}, nil
+}
+
+// stringList accepts a claim holding either a list of strings or a single string
+func stringList(v interface{}) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/services"
+	"github.com/OmidRasouli/weather-api/internal/domain/user"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubIdP is a minimal OpenID Connect issuer: discovery, JWKS and a token endpoint
+// that redeems a single code, checking the PKCE verifier against challenge
+type stubIdP struct {
+	server    *httptest.Server
+	key       *rsa.PrivateKey
+	kid       string
+	jwks      services.JWKS
+	challenge string
+	idToken   string
+}
+
+func newStubIdP(t *testing.T) *stubIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	signingKey, err := services.NewAsymmetricKey(key)
+	assert.NoError(t, err)
+
+	idp := &stubIdP{key: key, kid: signingKey.ID, jwks: services.NewKeySet(signingKey).JWKS()}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                                idp.server.URL,
+			"authorization_endpoint":                idp.server.URL + "/authorize",
+			"token_endpoint":                        idp.server.URL + "/token",
+			"jwks_uri":                              idp.server.URL + "/keys",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(idp.jwks)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		sum := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+		if r.FormValue("code") != "good-code" || base64.RawURLEncoding.EncodeToString(sum[:]) != idp.challenge {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "opaque",
+			"token_type":   "Bearer",
+			"expires_in":   300,
+			"id_token":     idp.idToken,
+		})
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+// sign issues a token for alice from the stub; overrides replace or add claims
+func (idp *stubIdP) sign(t *testing.T, overrides jwt.MapClaims) string {
+	claims := jwt.MapClaims{
+		"iss":                idp.server.URL,
+		"sub":                "00u1a2b3c",
+		"aud":                "weather-client",
+		"exp":                time.Now().Add(time.Minute).Unix(),
+		"iat":                time.Now().Unix(),
+		"preferred_username": "alice",
+		"groups":             []string{"staff", "weather-editors"},
+	}
+	for k, v := range overrides {
+		claims[k] = v
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = idp.kid
+	signed, err := token.SignedString(idp.key)
+	assert.NoError(t, err)
+	return signed
+}
+
+func newTestProvider(t *testing.T, idp *stubIdP, defaultRole string) *Provider {
+	p, err := NewProvider(context.Background(), Config{
+		IssuerURL:   idp.server.URL,
+		ClientID:    "weather-client",
+		RedirectURL: "http://localhost:8080/auth/oidc/callback",
+		Audiences:   []string{"weather-api"},
+		GroupRoles:  map[string]string{"weather-editors": "editor", "weather-admins": "admin"},
+		DefaultRole: defaultRole,
+	})
+	assert.NoError(t, err)
+	return p
+}
+
+func TestProvider_AuthorizationCodeLogin(t *testing.T) {
+	idp := newStubIdP(t)
+	p := newTestProvider(t, idp, "")
+
+	authURL, err := url.Parse(p.AuthCodeURL("state-1", "nonce-1", "verifier-verifier-verifier-verifier-verifier"))
+	assert.NoError(t, err)
+	query := authURL.Query()
+	assert.Equal(t, idp.server.URL+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+	assert.Equal(t, "state-1", query.Get("state"))
+	assert.Equal(t, "nonce-1", query.Get("nonce"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	idp.challenge = query.Get("code_challenge")
+
+	idp.idToken = idp.sign(t, jwt.MapClaims{
+		"nonce":  "nonce-1",
+		"groups": []string{"weather-editors", "weather-admins"},
+	})
+	id, err := p.Exchange(context.Background(), "good-code", "nonce-1", "verifier-verifier-verifier-verifier-verifier")
+	assert.NoError(t, err)
+	assert.Equal(t, idp.server.URL, id.Issuer)
+	assert.Equal(t, "00u1a2b3c", id.Subject)
+	assert.Equal(t, "alice", id.Username)
+	// The most privileged mapped group wins
+	assert.Equal(t, user.RoleAdmin, id.Role)
+
+	// A wrong PKCE verifier is refused by the issuer
+	_, err = p.Exchange(context.Background(), "good-code", "nonce-1", "another-verifier-another-verifier-another")
+	assert.Error(t, err)
+
+	// An ID token issued for another login is rejected by its nonce
+	_, err = p.Exchange(context.Background(), "good-code", "nonce-2", "verifier-verifier-verifier-verifier-verifier")
+	assert.Error(t, err)
+}
+
+func TestProvider_VerifyToken(t *testing.T) {
+	idp := newStubIdP(t)
+	p := newTestProvider(t, idp, "")
+	ctx := context.Background()
+
+	// Access tokens for the API identifier are accepted besides ID tokens for the client
+	id, err := p.VerifyToken(ctx, idp.sign(t, jwt.MapClaims{"aud": "weather-api", "jti": "at-1"}))
+	assert.NoError(t, err)
+	assert.Equal(t, user.RoleEditor, id.Role)
+	assert.Equal(t, "at-1", id.TokenID)
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+	}{
+		{"other audience", jwt.MapClaims{"aud": "billing-api"}},
+		{"other issuer", jwt.MapClaims{"iss": "https://evil.example.com"}},
+		{"expired", jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := p.VerifyToken(ctx, idp.sign(t, tt.claims))
+			assert.Error(t, err)
+		})
+	}
+
+	// A token signed by a key the issuer does not publish is rejected
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	forged := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss": idp.server.URL, "sub": "x", "aud": "weather-client", "exp": time.Now().Add(time.Minute).Unix(),
+	})
+	forged.Header["kid"] = idp.kid
+	raw, err := forged.SignedString(other)
+	assert.NoError(t, err)
+	_, err = p.VerifyToken(ctx, raw)
+	assert.Error(t, err)
+}
+
+func TestProvider_UnmappedGroups(t *testing.T) {
+	idp := newStubIdP(t)
+	token := idp.sign(t, jwt.MapClaims{"groups": "staff"})
+
+	// Without a default role the identity gets no role
+	id, err := newTestProvider(t, idp, "").VerifyToken(context.Background(), token)
+	assert.NoError(t, err)
+	assert.Equal(t, user.Role(""), id.Role)
+	assert.Equal(t, []string{"staff"}, id.Groups)
+
+	id, err = newTestProvider(t, idp, "viewer").VerifyToken(context.Background(), token)
+	assert.NoError(t, err)
+	assert.Equal(t, user.RoleViewer, id.Role)
+}
+
+func TestNewProvider_InvalidGroupRole(t *testing.T) {
+	idp := newStubIdP(t)
+	_, err := NewProvider(context.Background(), Config{
+		IssuerURL:  idp.server.URL,
+		ClientID:   "weather-client",
+		GroupRoles: map[string]string{"weather-admins": "superuser"},
+	})
+	assert.ErrorIs(t, err, user.ErrInvalidUser)
+}
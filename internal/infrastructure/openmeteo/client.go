@@ -0,0 +1,147 @@
+package openmeteo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/go-resty/resty/v2"
+)
+
+// ProviderName identifies Open-Meteo in provider configuration and stored records.
+const ProviderName = "openmeteo"
+
+const (
+	defaultBaseURL      = "https://api.open-meteo.com/v1"
+	defaultGeocodingURL = "https://geocoding-api.open-meteo.com/v1"
+)
+
+// Client fetches current conditions from Open-Meteo. It needs no API key;
+// cities are resolved to coordinates through the Open-Meteo geocoding API.
+type Client struct {
+	baseURL      string
+	geocodingURL string
+	client       *resty.Client
+}
+
+func NewClient() *Client {
+	return &Client{
+		baseURL:      defaultBaseURL,
+		geocodingURL: defaultGeocodingURL,
+		client: resty.New().
+			SetTimeout(5 * time.Second),
+	}
+}
+
+// WithBaseURLs overrides the forecast and geocoding endpoints; empty values keep the defaults.
+func (c *Client) WithBaseURLs(baseURL string, geocodingURL string) *Client {
+	if baseURL != "" {
+		c.baseURL = baseURL
+	}
+	if geocodingURL != "" {
+		c.geocodingURL = geocodingURL
+	}
+	return c
+}
+
+type place struct {
+	Name        string  `json:"name"`
+	Latitude    float64 `json:"latitude"`
+	Longitude   float64 `json:"longitude"`
+	CountryCode string  `json:"country_code"`
+}
+
+type geocodingResponse struct {
+	Results []place `json:"results"`
+}
+
+type currentResponse struct {
+	Current struct {
+		Time             int64   `json:"time"` // Unix timestamp
+		Temperature      float64 `json:"temperature_2m"`
+		RelativeHumidity int     `json:"relative_humidity_2m"`
+		WindSpeed        float64 `json:"wind_speed_10m"`
+		WeatherCode      int     `json:"weather_code"`
+	} `json:"current"`
+}
+
+// geocode resolves a city (and optional ISO country code) to a place with coordinates
+func (c *Client) geocode(ctx context.Context, city string, country string) (*place, error) {
+	params := map[string]string{
+		"name":  city,
+		"count": "1",
+	}
+	if country != "" {
+		params["countryCode"] = country
+	}
+
+	var res geocodingResponse
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetQueryParams(params).
+		SetResult(&res).
+		Get(c.geocodingURL + "/search")
+	if err != nil {
+		return nil, fmt.Errorf("failed to call geocoding API: %w", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("geocoding API returned status %d", resp.StatusCode())
+	}
+	if len(res.Results) == 0 {
+		return nil, fmt.Errorf("%w: %s,%s", interfaces.ErrCityNotFound, city, country)
+	}
+	return &res.Results[0], nil
+}
+
+func (c *Client) FetchWeatherData(ctx context.Context, city string, country string) (*interfaces.WeatherAPIResponse, error) {
+	place, err := c.geocode(ctx, city, country)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.FetchWeatherByCoordinates(ctx, place.Latitude, place.Longitude)
+	if err != nil {
+		return nil, err
+	}
+	result.City, result.Country = place.Name, place.CountryCode
+	return result, nil
+}
+
+// FetchWeatherByCoordinates fetches current conditions for a latitude/longitude.
+// Open-Meteo has no reverse geocoding, so City and Country are left empty.
+func (c *Client) FetchWeatherByCoordinates(ctx context.Context, lat float64, lon float64) (*interfaces.WeatherAPIResponse, error) {
+	var res currentResponse
+	resp, err := c.client.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{
+			"latitude":        strconv.FormatFloat(lat, 'f', -1, 64),
+			"longitude":       strconv.FormatFloat(lon, 'f', -1, 64),
+			"current":         "temperature_2m,relative_humidity_2m,wind_speed_10m,weather_code",
+			"wind_speed_unit": "ms",
+			"timeformat":      "unixtime",
+		}).
+		SetResult(&res).
+		Get(c.baseURL + "/forecast")
+	if err != nil {
+		return nil, fmt.Errorf("failed to call weather API: %w", err)
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("weather API returned status %d", resp.StatusCode())
+	}
+	if res.Current.Time == 0 {
+		return nil, fmt.Errorf("invalid response: missing current conditions")
+	}
+
+	return &interfaces.WeatherAPIResponse{
+		Temperature: res.Current.Temperature,
+		Description: describe(res.Current.WeatherCode),
+		Humidity:    res.Current.RelativeHumidity,
+		WindSpeed:   res.Current.WindSpeed,
+		FetchedAt:   time.Unix(res.Current.Time, 0),
+		Latitude:    &lat,
+		Longitude:   &lon,
+		Provider:    ProviderName,
+	}, nil
+}
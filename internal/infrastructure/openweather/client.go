@@ -3,50 +3,141 @@ package openweather
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
 	"github.com/go-resty/resty/v2"
 )
 
+// ProviderName identifies OpenWeather in provider configuration and stored records.
+const ProviderName = "openweather"
+
+const defaultBaseURL = "https://api.openweathermap.org/data/2.5"
+
 type Client struct {
-	apiKey string
-	client *resty.Client
+	apiKey  string
+	baseURL string
+	client  *resty.Client
+	retry   RetryPolicy
+	breaker *CircuitBreaker
+	sleep   func(ctx context.Context, d time.Duration) error // replaced in tests
 }
 
 func NewClient(apiKey string) *Client {
 	return &Client{
-		apiKey: apiKey,
+		apiKey:  apiKey,
+		baseURL: defaultBaseURL,
 		client: resty.New().
 			SetTimeout(5 * time.Second),
+		retry:   RetryPolicy{}.withDefaults(),
+		breaker: NewCircuitBreaker(0, 0),
+		sleep:   sleepContext,
+	}
+}
+
+// WithResilience replaces the default retry policy and circuit breaker.
+func (c *Client) WithResilience(retry RetryPolicy, breaker *CircuitBreaker) *Client {
+	c.retry = retry.withDefaults()
+	if breaker != nil {
+		c.breaker = breaker
 	}
+	return c
 }
 
-// Sample API response struct
+// WithBaseURL overrides the API endpoint; an empty value keeps the default.
+func (c *Client) WithBaseURL(baseURL string) *Client {
+	if baseURL != "" {
+		c.baseURL = baseURL
+	}
+	return c
+}
+
+// apiResponse is the body of the current weather endpoint
 type apiResponse struct {
+	Name  string `json:"name"`
+	Coord *struct {
+		Lat float64 `json:"lat"`
+		Lon float64 `json:"lon"`
+	} `json:"coord"`
+	Sys struct {
+		Country string `json:"country"`
+		Sunrise int64  `json:"sunrise"` // Unix timestamp
+		Sunset  int64  `json:"sunset"`  // Unix timestamp
+	} `json:"sys"`
 	Main struct {
-		Temp     float64 `json:"temp"`
-		Humidity int     `json:"humidity"`
+		Temp      float64 `json:"temp"`
+		FeelsLike float64 `json:"feels_like"`
+		TempMin   float64 `json:"temp_min"`
+		TempMax   float64 `json:"temp_max"`
+		Pressure  int     `json:"pressure"`
+		Humidity  int     `json:"humidity"`
 	} `json:"main"`
 	Weather []struct {
+		ID          int    `json:"id"`
 		Description string `json:"description"`
+		Icon        string `json:"icon"`
 	} `json:"weather"`
+	Visibility int `json:"visibility"`
+	Clouds     struct {
+		All int `json:"all"`
+	} `json:"clouds"`
 	Wind struct {
 		Speed float64 `json:"speed"`
+		Deg   int     `json:"deg"`
+		Gust  float64 `json:"gust"`
 	} `json:"wind"`
+	Rain struct {
+		OneHour float64 `json:"1h"`
+	} `json:"rain"`
+	Snow struct {
+		OneHour float64 `json:"1h"`
+	} `json:"snow"`
 	Dt int64 `json:"dt"` // Unix timestamp
 }
 
+// errorResponse is the body of an error response, e.g. {"cod":"404","message":"city not found"}
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+// forecastResponse is the body of the 5-day/3-hour forecast endpoint
+type forecastResponse struct {
+	List []apiResponse `json:"list"`
+}
+
+// query builds the common query parameters for a city lookup
+func (c *Client) query(city string, country string) map[string]string {
+	q := city
+	if country != "" {
+		q = city + "," + country
+	}
+	return map[string]string{
+		"q":     q,
+		"appid": c.apiKey,
+		"units": "metric",
+	}
+}
+
 func (c *Client) FetchWeatherData(ctx context.Context, city string, country string) (*interfaces.WeatherAPIResponse, error) {
-	url := fmt.Sprintf("https://api.openweathermap.org/data/2.5/weather?q=%s,%s&appid=%s&units=metric", city, country, c.apiKey)
+	return c.fetchCurrent(ctx, c.query(city, country))
+}
 
-	var res apiResponse
-	_, err := c.client.R().
-		SetContext(ctx).
-		SetResult(&res).
-		Get(url)
+// FetchWeatherByCoordinates calls the current weather endpoint for a latitude/longitude
+func (c *Client) FetchWeatherByCoordinates(ctx context.Context, lat float64, lon float64) (*interfaces.WeatherAPIResponse, error) {
+	return c.fetchCurrent(ctx, map[string]string{
+		"lat":   strconv.FormatFloat(lat, 'f', -1, 64),
+		"lon":   strconv.FormatFloat(lon, 'f', -1, 64),
+		"appid": c.apiKey,
+		"units": "metric",
+	})
+}
 
-	if err != nil {
+// fetchCurrent calls the current weather endpoint with the given query parameters
+func (c *Client) fetchCurrent(ctx context.Context, params map[string]string) (*interfaces.WeatherAPIResponse, error) {
+	var res apiResponse
+	if err := c.get(ctx, "/weather", params, &res); err != nil {
 		return nil, fmt.Errorf("failed to call weather API: %w", err)
 	}
 
@@ -54,11 +145,148 @@ func (c *Client) FetchWeatherData(ctx context.Context, city string, country stri
 		return nil, fmt.Errorf("invalid response: missing weather description")
 	}
 
-	return &interfaces.WeatherAPIResponse{
+	result := &interfaces.WeatherAPIResponse{
+		City:        res.Name,
+		Country:     res.Sys.Country,
 		Temperature: res.Main.Temp,
+		FeelsLike:   res.Main.FeelsLike,
+		TempMin:     res.Main.TempMin,
+		TempMax:     res.Main.TempMax,
 		Description: res.Weather[0].Description,
+		ConditionID: res.Weather[0].ID,
+		Icon:        res.Weather[0].Icon,
 		Humidity:    res.Main.Humidity,
+		Pressure:    res.Main.Pressure,
+		Visibility:  res.Visibility,
+		Clouds:      res.Clouds.All,
 		WindSpeed:   res.Wind.Speed,
+		WindDeg:     res.Wind.Deg,
+		WindGust:    res.Wind.Gust,
+		Rain:        res.Rain.OneHour,
+		Snow:        res.Snow.OneHour,
+		Sunrise:     unixTime(res.Sys.Sunrise),
+		Sunset:      unixTime(res.Sys.Sunset),
 		FetchedAt:   time.Unix(res.Dt, 0),
+		Provider:    ProviderName,
+	}
+	if res.Coord != nil {
+		result.Latitude, result.Longitude = &res.Coord.Lat, &res.Coord.Lon
+	}
+	return result, nil
+}
+
+// get calls an endpoint through the circuit breaker and decodes a successful response into
+// result. Timeouts, transport errors and 5xx responses are retried with exponential backoff
+// and jitter; 4xx responses are returned at once, and do not count against the breaker. Nor
+// do calls cut short by ctx or by the call budget, whose error is returned as is.
+func (c *Client) get(ctx context.Context, path string, params map[string]string, result interface{}) error {
+	if !c.breaker.Allow() {
+		return interfaces.ErrCircuitOpen
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		var res *resty.Response
+		var body errorResponse
+		res, err = c.client.R().
+			SetContext(ctx).
+			SetQueryParams(params).
+			SetResult(result).
+			SetError(&body).
+			Get(c.baseURL + path)
+
+		retryable := false
+		switch {
+		case err != nil && ctx.Err() != nil:
+			// The caller gave up mid-request, which says nothing about the API
+			c.breaker.Abandon()
+			return err
+		case err != nil:
+			err = fmt.Errorf("%w: %w", interfaces.ErrUpstreamUnavailable, err)
+			retryable = true
+		case res.StatusCode() >= http.StatusInternalServerError:
+			err = upstreamError(res.StatusCode(), body.Message)
+			retryable = true
+		case res.IsError():
+			c.breaker.Success()
+			return upstreamError(res.StatusCode(), body.Message)
+		default:
+			c.breaker.Success()
+			return nil
+		}
+
+		if !retryable || attempt >= c.retry.MaxAttempts {
+			break
+		}
+		if sleepErr := c.sleep(ctx, c.retry.delay(attempt)); sleepErr != nil {
+			c.breaker.Abandon()
+			return sleepErr
+		}
+		// Retries are upstream calls too, so they are charged to the call budget if there is one.
+		// Running out of our own budget is not a failure of the API, so the breaker is left alone.
+		if quotaErr := interfaces.AcquireRetry(ctx); quotaErr != nil {
+			c.breaker.Abandon()
+			return quotaErr
+		}
+	}
+
+	c.breaker.Failure()
+	return err
+}
+
+// upstreamError classifies an error response by its status code
+func upstreamError(status int, message string) *interfaces.UpstreamError {
+	e := &interfaces.UpstreamError{Provider: ProviderName, StatusCode: status, Message: message}
+	switch {
+	case status == http.StatusNotFound:
+		e.Kind = interfaces.ErrCityNotFound
+	case status == http.StatusUnauthorized:
+		e.Kind = interfaces.ErrUpstreamUnauthorized
+	case status == http.StatusTooManyRequests:
+		e.Kind = interfaces.ErrUpstreamRateLimited
+	case status >= http.StatusInternalServerError:
+		e.Kind = interfaces.ErrUpstreamUnavailable
+	}
+	return e
+}
+
+// unixTime converts an optional Unix timestamp; zero means the field was absent
+func unixTime(ts int64) *time.Time {
+	if ts == 0 {
+		return nil
+	}
+	t := time.Unix(ts, 0).UTC()
+	return &t
+}
+
+// FetchForecast calls the 5-day/3-hour forecast endpoint for a city
+func (c *Client) FetchForecast(ctx context.Context, city string, country string) (*interfaces.ForecastAPIResponse, error) {
+	var res forecastResponse
+	if err := c.get(ctx, "/forecast", c.query(city, country), &res); err != nil {
+		return nil, fmt.Errorf("failed to call forecast API: %w", err)
+	}
+
+	if len(res.List) == 0 {
+		return nil, fmt.Errorf("invalid response: missing forecast entries")
+	}
+
+	entries := make([]interfaces.ForecastAPIEntry, 0, len(res.List))
+	for _, item := range res.List {
+		var description string
+		if len(item.Weather) > 0 {
+			description = item.Weather[0].Description
+		}
+		entries = append(entries, interfaces.ForecastAPIEntry{
+			ForecastAt:  time.Unix(item.Dt, 0).UTC(),
+			Temperature: item.Main.Temp,
+			Description: description,
+			Humidity:    item.Main.Humidity,
+			WindSpeed:   item.Wind.Speed,
+		})
+	}
+
+	return &interfaces.ForecastAPIResponse{
+		Entries:   entries,
+		FetchedAt: time.Now().UTC(),
 	}, nil
 }
@@ -0,0 +1,166 @@
+package openweather
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchForecast(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/forecast", r.URL.Path)
+		assert.Equal(t, "tehran,IR", r.URL.Query().Get("q"))
+		assert.Equal(t, "test-key", r.URL.Query().Get("appid"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"list":[
+			{"dt":1700000000,"main":{"temp":21.5,"humidity":40},"weather":[{"description":"clear sky"}],"wind":{"speed":3.1}},
+			{"dt":1700010800,"main":{"temp":19.0,"humidity":45},"weather":[{"description":"few clouds"}],"wind":{"speed":2.4}}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key")
+	client.baseURL = server.URL
+
+	res, err := client.FetchForecast(context.Background(), "tehran", "IR")
+
+	assert.NoError(t, err)
+	assert.Len(t, res.Entries, 2)
+	assert.Equal(t, int64(1700010800), res.Entries[1].ForecastAt.Unix())
+	assert.Equal(t, 19.0, res.Entries[1].Temperature)
+	assert.Equal(t, "few clouds", res.Entries[1].Description)
+}
+
+func TestFetchForecast_EmptyList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"list":[]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key")
+	client.baseURL = server.URL
+
+	res, err := client.FetchForecast(context.Background(), "nowhere", "")
+
+	assert.Error(t, err)
+	assert.Nil(t, res)
+}
+
+func TestFetchWeatherByCoordinates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/weather", r.URL.Path)
+		assert.Equal(t, "35.6892", r.URL.Query().Get("lat"))
+		assert.Equal(t, "51.389", r.URL.Query().Get("lon"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"Tehran","coord":{"lat":35.69,"lon":51.42},"sys":{"country":"IR"},
+			"main":{"temp":30.1,"humidity":20},"weather":[{"description":"clear sky"}],"wind":{"speed":2.5},"dt":1700000000}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key").WithBaseURL(server.URL)
+
+	res, err := client.FetchWeatherByCoordinates(context.Background(), 35.6892, 51.389)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Tehran", res.City)
+	assert.Equal(t, "IR", res.Country)
+	assert.Equal(t, 35.69, *res.Latitude)
+	assert.Equal(t, 51.42, *res.Longitude)
+	assert.Equal(t, ProviderName, res.Provider)
+}
+
+func TestFetchWeatherData_FullPayload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/weather", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"coord":{"lon":13.41,"lat":52.52},
+			"weather":[{"id":501,"main":"Rain","description":"moderate rain","icon":"10d"}],
+			"main":{"temp":12.3,"feels_like":11.1,"temp_min":10.9,"temp_max":13.8,"pressure":1008,"humidity":81},
+			"visibility":9000,
+			"wind":{"speed":5.7,"deg":240,"gust":11.3},
+			"rain":{"1h":1.27},
+			"clouds":{"all":90},
+			"dt":1700000000,
+			"sys":{"country":"DE","sunrise":1699985000,"sunset":1700017000},
+			"name":"Berlin"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test-key").WithBaseURL(server.URL)
+
+	res, err := client.FetchWeatherData(context.Background(), "berlin", "DE")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 11.1, res.FeelsLike)
+	assert.Equal(t, 10.9, res.TempMin)
+	assert.Equal(t, 13.8, res.TempMax)
+	assert.Equal(t, 1008, res.Pressure)
+	assert.Equal(t, 9000, res.Visibility)
+	assert.Equal(t, 90, res.Clouds)
+	assert.Equal(t, 240, res.WindDeg)
+	assert.Equal(t, 11.3, res.WindGust)
+	assert.Equal(t, 1.27, res.Rain)
+	assert.Equal(t, 0.0, res.Snow)
+	assert.Equal(t, 501, res.ConditionID)
+	assert.Equal(t, "10d", res.Icon)
+	assert.Equal(t, int64(1699985000), res.Sunrise.Unix())
+	assert.Equal(t, int64(1700017000), res.Sunset.Unix())
+}
+
+func TestFetchWeatherData_ClassifiesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		kind    error
+		message string
+	}{
+		{name: "unknown city", status: http.StatusNotFound, body: `{"cod":"404","message":"city not found"}`, kind: interfaces.ErrCityNotFound, message: "city not found"},
+		{name: "bad API key", status: http.StatusUnauthorized, body: `{"cod":401,"message":"Invalid API key. Please see https://openweathermap.org/faq#error401 for more info."}`, kind: interfaces.ErrUpstreamUnauthorized, message: "Invalid API key. Please see https://openweathermap.org/faq#error401 for more info."},
+		{name: "throttled", status: http.StatusTooManyRequests, body: `{"cod":429,"message":"Your account is temporary blocked"}`, kind: interfaces.ErrUpstreamRateLimited, message: "Your account is temporary blocked"},
+		{name: "server error", status: http.StatusBadGateway, body: `<html>bad gateway</html>`, kind: interfaces.ErrUpstreamUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasPrefix(tt.body, "{") {
+					w.Header().Set("Content-Type", "application/json")
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient("test-key").WithBaseURL(server.URL).WithResilience(RetryPolicy{MaxAttempts: 1}, nil)
+
+			res, err := client.FetchWeatherData(context.Background(), "atlantis", "")
+
+			assert.Nil(t, res)
+			assert.True(t, errors.Is(err, tt.kind))
+			var upstreamErr *interfaces.UpstreamError
+			assert.True(t, errors.As(err, &upstreamErr))
+			assert.Equal(t, tt.status, upstreamErr.StatusCode)
+			assert.Equal(t, tt.message, upstreamErr.Message)
+		})
+	}
+}
+
+func TestFetchWeatherData_UnreachableIsUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	client := NewClient("test-key").WithBaseURL(server.URL).WithResilience(RetryPolicy{MaxAttempts: 1}, nil)
+
+	_, err := client.FetchWeatherData(context.Background(), "berlin", "DE")
+
+	assert.True(t, errors.Is(err, interfaces.ErrUpstreamUnavailable))
+}
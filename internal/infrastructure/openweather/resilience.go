@@ -0,0 +1,154 @@
+package openweather
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/pkg/logger"
+)
+
+const (
+	DefaultMaxAttempts      = 3
+	DefaultRetryBackoff     = 200 * time.Millisecond
+	DefaultMaxRetryBackoff  = 2 * time.Second
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+// RetryPolicy bounds the attempts made for one call. Zero values fall back to the defaults above.
+type RetryPolicy struct {
+	// MaxAttempts includes the first attempt; 1 disables retries.
+	MaxAttempts int
+	// Backoff is the delay ceiling before the first retry; it doubles with every further retry.
+	Backoff time.Duration
+	// MaxBackoff caps the delay ceiling.
+	MaxBackoff time.Duration
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = DefaultMaxAttempts
+	}
+	if p.Backoff <= 0 {
+		p.Backoff = DefaultRetryBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultMaxRetryBackoff
+	}
+	return p
+}
+
+// delay returns the wait before the given retry (1 for the first), drawn uniformly up to
+// an exponentially growing ceiling so that clients recovering together spread out
+func (p RetryPolicy) delay(retry int) time.Duration {
+	ceiling := p.Backoff
+	for i := 1; i < retry && ceiling < p.MaxBackoff; i++ {
+		ceiling *= 2
+	}
+	if ceiling > p.MaxBackoff {
+		ceiling = p.MaxBackoff
+	}
+	return time.Duration(rand.Int63n(int64(ceiling)) + 1)
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// CircuitBreaker stops calls to the API after Threshold consecutive failed calls. Once
+// Cooldown has passed a single trial call is let through: its success closes the breaker,
+// its failure opens it for another cooldown.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	state     breakerState
+	failures  int
+	openedAt  time.Time
+	now       func() time.Time // testable clock
+}
+
+// NewCircuitBreaker creates a closed breaker; zero values fall back to the defaults above.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		threshold = DefaultBreakerThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+	return &CircuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// Allow reports whether a call may be made now.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		// Only the trial call is let through
+		return false
+	default:
+		return true
+	}
+}
+
+// Success records a call that reached the API, closing the breaker.
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != breakerClosed {
+		logger.Infof("OpenWeather circuit breaker closed")
+	}
+	b.state = breakerClosed
+	b.failures = 0
+}
+
+// Failure records a call that failed after all its attempts.
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == breakerHalfOpen || (b.state == breakerClosed && b.failures >= b.threshold) {
+		logger.Warnf("OpenWeather circuit breaker opened after %d consecutive failure(s), retrying in %s", b.failures, b.cooldown)
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// Abandon records a call that ended without a verdict on the API, because the caller gave
+// up or our own call budget ran out. A trial call gives its turn to the next one.
+func (b *CircuitBreaker) Abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+	}
+}
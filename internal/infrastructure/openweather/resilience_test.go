@@ -0,0 +1,220 @@
+package openweather
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	testhelpers.InitTestLogger()
+	os.Exit(m.Run())
+}
+
+const currentWeatherBody = `{"name":"Berlin","sys":{"country":"DE"},"main":{"temp":12.3,"humidity":81},"weather":[{"description":"moderate rain"}],"dt":1700000000}`
+
+// newFlakyServer answers with each of statuses in turn, then with 200 and a valid body
+func newFlakyServer(calls *int32, statuses ...int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(calls, 1))
+		w.Header().Set("Content-Type", "application/json")
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			_, _ = w.Write([]byte(`{"cod":"500","message":"internal error"}`))
+			return
+		}
+		_, _ = w.Write([]byte(currentWeatherBody))
+	}))
+}
+
+// newTestClient returns a client that records its backoff delays instead of sleeping
+func newTestClient(baseURL string, retry RetryPolicy, breaker *CircuitBreaker, delays *[]time.Duration) *Client {
+	c := NewClient("test-key").WithBaseURL(baseURL).WithResilience(retry, breaker)
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		*delays = append(*delays, d)
+		return nil
+	}
+	return c
+}
+
+func TestClient_RetriesServerErrors(t *testing.T) {
+	var calls int32
+	server := newFlakyServer(&calls, http.StatusBadGateway, http.StatusServiceUnavailable)
+	defer server.Close()
+
+	var delays []time.Duration
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: 100 * time.Millisecond, MaxBackoff: 150 * time.Millisecond}
+	client := newTestClient(server.URL, policy, nil, &delays)
+
+	res, err := client.FetchWeatherData(context.Background(), "berlin", "DE")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "moderate rain", res.Description)
+	assert.Equal(t, int32(3), calls)
+	assert.Len(t, delays, 2)
+	assert.LessOrEqual(t, delays[0], 100*time.Millisecond)
+	assert.LessOrEqual(t, delays[1], 150*time.Millisecond)
+}
+
+func TestClient_GivesUpAfterMaxAttempts(t *testing.T) {
+	var calls int32
+	server := newFlakyServer(&calls, 500, 500, 500, 500)
+	defer server.Close()
+
+	var delays []time.Duration
+	client := newTestClient(server.URL, RetryPolicy{MaxAttempts: 2}, nil, &delays)
+
+	_, err := client.FetchWeatherData(context.Background(), "berlin", "DE")
+
+	assert.ErrorContains(t, err, "status 500")
+	assert.Equal(t, int32(2), calls)
+}
+
+func TestClient_DoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	server := newFlakyServer(&calls, http.StatusNotFound)
+	defer server.Close()
+
+	var delays []time.Duration
+	breaker := NewCircuitBreaker(1, time.Minute)
+	client := newTestClient(server.URL, RetryPolicy{}, breaker, &delays)
+
+	_, err := client.FetchWeatherData(context.Background(), "atlantis", "")
+
+	assert.ErrorContains(t, err, "status 404")
+	assert.Equal(t, int32(1), calls)
+	assert.Empty(t, delays)
+	// A rejected request shows the API is up, so the breaker stays closed
+	assert.True(t, breaker.Allow())
+}
+
+func TestClient_RetriesTimeouts(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(currentWeatherBody))
+	}))
+	defer server.Close()
+
+	var delays []time.Duration
+	client := newTestClient(server.URL, RetryPolicy{}, nil, &delays)
+	client.client.SetTimeout(50 * time.Millisecond)
+
+	res, err := client.FetchWeatherData(context.Background(), "berlin", "DE")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Berlin", res.City)
+	assert.Equal(t, int32(2), calls)
+}
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	var calls int32
+	server := newFlakyServer(&calls, 500, 500, 500, 500)
+	defer server.Close()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(2, 30*time.Second)
+	breaker.now = func() time.Time { return now }
+	var delays []time.Duration
+	client := newTestClient(server.URL, RetryPolicy{MaxAttempts: 1}, breaker, &delays)
+	ctx := context.Background()
+
+	// Two consecutive failures open the breaker
+	for i := 0; i < 2; i++ {
+		_, err := client.FetchWeatherData(ctx, "berlin", "DE")
+		assert.False(t, errors.Is(err, interfaces.ErrCircuitOpen))
+	}
+	_, err := client.FetchWeatherData(ctx, "berlin", "DE")
+	assert.True(t, errors.Is(err, interfaces.ErrCircuitOpen))
+	_, err = client.FetchForecast(ctx, "berlin", "DE")
+	assert.True(t, errors.Is(err, interfaces.ErrCircuitOpen))
+	assert.Equal(t, int32(2), calls)
+
+	// After the cooldown a failed trial call opens it again
+	now = now.Add(30 * time.Second)
+	_, err = client.FetchWeatherData(ctx, "berlin", "DE")
+	assert.False(t, errors.Is(err, interfaces.ErrCircuitOpen))
+	_, err = client.FetchWeatherData(ctx, "berlin", "DE")
+	assert.True(t, errors.Is(err, interfaces.ErrCircuitOpen))
+	assert.Equal(t, int32(3), calls)
+
+	// A successful trial call closes it
+	now = now.Add(30 * time.Second)
+	_, err = client.FetchWeatherData(ctx, "berlin", "DE")
+	assert.Error(t, err)
+	now = now.Add(30 * time.Second)
+	_, err = client.FetchWeatherData(ctx, "berlin", "DE")
+	assert.NoError(t, err)
+	_, err = client.FetchWeatherData(ctx, "berlin", "DE")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(6), calls)
+}
+
+func TestClient_RetriesAreChargedToTheBudget(t *testing.T) {
+	var calls int32
+	server := newFlakyServer(&calls, 500, 500, 500)
+	defer server.Close()
+
+	var delays []time.Duration
+	breaker := NewCircuitBreaker(1, time.Minute)
+	client := newTestClient(server.URL, RetryPolicy{MaxAttempts: 3}, breaker, &delays)
+
+	// The budget allows one retry
+	var acquired int
+	ctx := interfaces.WithRetryAcquire(context.Background(), func(context.Context) error {
+		acquired++
+		if acquired > 1 {
+			return &interfaces.QuotaExceededError{Provider: ProviderName, Period: "minute"}
+		}
+		return nil
+	})
+	_, err := client.FetchWeatherData(ctx, "berlin", "DE")
+
+	var quotaErr *interfaces.QuotaExceededError
+	assert.ErrorAs(t, err, &quotaErr)
+	assert.Equal(t, int32(2), calls)
+	assert.Equal(t, 2, acquired)
+
+	// Our own budget running out is not held against the API
+	assert.True(t, breaker.Allow())
+}
+
+func TestClient_CancelledRetriesDoNotOpenTheBreaker(t *testing.T) {
+	var calls int32
+	server := newFlakyServer(&calls, 500, 500, 500)
+	defer server.Close()
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(1, 30*time.Second)
+	breaker.now = func() time.Time { return now }
+	client := NewClient("test-key").WithBaseURL(server.URL).WithResilience(RetryPolicy{MaxAttempts: 3}, breaker)
+	ctx, cancel := context.WithCancel(context.Background())
+	client.sleep = func(context.Context, time.Duration) error {
+		cancel()
+		return ctx.Err()
+	}
+
+	_, err := client.FetchWeatherData(ctx, "berlin", "DE")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(1), calls)
+	assert.True(t, breaker.Allow())
+
+	// An abandoned trial call lets the next caller try instead of keeping the breaker half-open
+	breaker.Failure()
+	now = now.Add(30 * time.Second)
+	_, err = client.FetchWeatherData(ctx, "berlin", "DE")
+	assert.False(t, errors.Is(err, interfaces.ErrCircuitOpen))
+	assert.True(t, breaker.Allow())
+}
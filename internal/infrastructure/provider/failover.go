@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/pkg/logger"
+)
+
+// Provider is a named weather API client.
+type Provider struct {
+	Name   string
+	Client interfaces.WeatherAPIClient
+}
+
+// FailoverClient tries its providers in priority order and returns the first successful response.
+// It implements interfaces.WeatherAPIClient and interfaces.ForecastAPIClient; forecasts are only
+// requested from providers that support them.
+type FailoverClient struct {
+	providers []Provider
+}
+
+// NewFailoverClient creates a client that fails over between providers in the given order.
+func NewFailoverClient(providers ...Provider) *FailoverClient {
+	return &FailoverClient{providers: providers}
+}
+
+func (f *FailoverClient) FetchWeatherData(ctx context.Context, city string, country string) (*interfaces.WeatherAPIResponse, error) {
+	var errs []error
+	for _, p := range f.providers {
+		res, err := p.Client.FetchWeatherData(ctx, city, country)
+		if err == nil {
+			if res.Provider == "" {
+				res.Provider = p.Name
+			}
+			return res, nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name, err))
+		if ctx.Err() != nil {
+			break
+		}
+		logger.Warnf("Weather provider %s failed for %s, %s: %v", p.Name, city, country, err)
+	}
+	return nil, fmt.Errorf("all weather providers failed: %w", errors.Join(errs...))
+}
+
+func (f *FailoverClient) FetchWeatherByCoordinates(ctx context.Context, lat float64, lon float64) (*interfaces.WeatherAPIResponse, error) {
+	var errs []error
+	for _, p := range f.providers {
+		res, err := p.Client.FetchWeatherByCoordinates(ctx, lat, lon)
+		if err == nil {
+			if res.Provider == "" {
+				res.Provider = p.Name
+			}
+			return res, nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name, err))
+		if ctx.Err() != nil {
+			break
+		}
+		logger.Warnf("Weather provider %s failed for %.4f, %.4f: %v", p.Name, lat, lon, err)
+	}
+	return nil, fmt.Errorf("all weather providers failed: %w", errors.Join(errs...))
+}
+
+func (f *FailoverClient) FetchForecast(ctx context.Context, city string, country string) (*interfaces.ForecastAPIResponse, error) {
+	var errs []error
+	for _, p := range f.providers {
+		forecaster, ok := p.Client.(interfaces.ForecastAPIClient)
+		if !ok {
+			continue
+		}
+
+		res, err := forecaster.FetchForecast(ctx, city, country)
+		if err == nil {
+			return res, nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name, err))
+		if ctx.Err() != nil {
+			break
+		}
+		logger.Warnf("Forecast provider %s failed for %s, %s: %v", p.Name, city, country, err)
+	}
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("no configured weather provider supports forecasts")
+	}
+	return nil, fmt.Errorf("all forecast providers failed: %w", errors.Join(errs...))
+}
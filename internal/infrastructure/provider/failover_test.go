@@ -0,0 +1,128 @@
+package provider_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/OmidRasouli/weather-api/config"
+	"github.com/OmidRasouli/weather-api/internal/infrastructure/provider"
+	"github.com/OmidRasouli/weather-api/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	testhelpers.InitTestLogger()
+	os.Exit(m.Run())
+}
+
+// newOpenWeatherStub returns an OpenWeather stand-in that always answers with the given status and body
+func newOpenWeatherStub(status int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+// newOpenMeteoStub returns an Open-Meteo stand-in serving both geocoding and current conditions
+func newOpenMeteoStub() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results":[{"name":"Berlin","latitude":52.52,"longitude":13.41,"country_code":"DE"}]}`))
+	})
+	mux.HandleFunc("/forecast", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("latitude") != "52.52" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"current":{"time":1700000000,"temperature_2m":12.3,"relative_humidity_2m":71,"wind_speed_10m":4.2,"weather_code":3}}`))
+	})
+	return httptest.NewServer(mux)
+}
+
+func buildClient(t *testing.T, cfg *config.Config) *provider.FailoverClient {
+	providers, err := provider.DefaultRegistry().Build(cfg, cfg.Providers.Priority)
+	assert.NoError(t, err)
+	return provider.NewFailoverClient(providers...)
+}
+
+func TestFailoverClient_UsesFirstHealthyProvider(t *testing.T) {
+	ow := newOpenWeatherStub(http.StatusOK, `{"main":{"temp":10.5,"humidity":60},"weather":[{"description":"light rain"}],"wind":{"speed":3},"dt":1700000000}`)
+	defer ow.Close()
+	om := newOpenMeteoStub()
+	defer om.Close()
+
+	cfg := &config.Config{
+		OpenWeather: config.OpenWeatherConfig{APIKey: "key", BaseURL: ow.URL},
+		OpenMeteo:   config.OpenMeteoConfig{BaseURL: om.URL, GeocodingURL: om.URL},
+		Providers:   config.ProvidersConfig{Priority: []string{"openweather", "openmeteo"}},
+	}
+
+	res, err := buildClient(t, cfg).FetchWeatherData(context.Background(), "Berlin", "DE")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "openweather", res.Provider)
+	assert.Equal(t, "light rain", res.Description)
+}
+
+func TestFailoverClient_FailsOverWhenRateLimited(t *testing.T) {
+	ow := newOpenWeatherStub(http.StatusTooManyRequests, `{"cod":429,"message":"rate limited"}`)
+	defer ow.Close()
+	om := newOpenMeteoStub()
+	defer om.Close()
+
+	cfg := &config.Config{
+		OpenWeather: config.OpenWeatherConfig{APIKey: "key", BaseURL: ow.URL},
+		OpenMeteo:   config.OpenMeteoConfig{BaseURL: om.URL, GeocodingURL: om.URL},
+		Providers:   config.ProvidersConfig{Priority: []string{"openweather", "openmeteo"}},
+	}
+
+	res, err := buildClient(t, cfg).FetchWeatherData(context.Background(), "Berlin", "DE")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "openmeteo", res.Provider)
+	assert.Equal(t, 12.3, res.Temperature)
+	assert.Equal(t, 71, res.Humidity)
+	assert.Equal(t, "overcast", res.Description)
+}
+
+func TestFailoverClient_AllProvidersFail(t *testing.T) {
+	ow := newOpenWeatherStub(http.StatusInternalServerError, `{}`)
+	defer ow.Close()
+	om := newOpenWeatherStub(http.StatusInternalServerError, `{}`)
+	defer om.Close()
+
+	cfg := &config.Config{
+		OpenWeather: config.OpenWeatherConfig{APIKey: "key", BaseURL: ow.URL},
+		OpenMeteo:   config.OpenMeteoConfig{BaseURL: om.URL, GeocodingURL: om.URL},
+		Providers:   config.ProvidersConfig{Priority: []string{"openmeteo", "openweather"}},
+	}
+
+	res, err := buildClient(t, cfg).FetchWeatherData(context.Background(), "Berlin", "DE")
+
+	assert.Error(t, err)
+	assert.Nil(t, res)
+	assert.Contains(t, err.Error(), "openmeteo")
+	assert.Contains(t, err.Error(), "openweather")
+}
+
+func TestRegistry_Build(t *testing.T) {
+	registry := provider.DefaultRegistry()
+
+	_, err := registry.Build(&config.Config{}, []string{"darksky"})
+	assert.ErrorContains(t, err, "unknown weather provider")
+
+	// OpenWeather without an API key is skipped rather than failing startup
+	providers, err := registry.Build(&config.Config{}, []string{"openweather", "openmeteo"})
+	assert.NoError(t, err)
+	assert.Len(t, providers, 1)
+	assert.Equal(t, "openmeteo", providers[0].Name)
+
+	_, err = registry.Build(&config.Config{}, []string{"openweather"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/application/ratelimit"
+	"github.com/OmidRasouli/weather-api/pkg/logger"
+)
+
+// quotaKeyPrefix namespaces the call counters in the cache
+const quotaKeyPrefix = "quota:"
+
+// QuotaBudget is the number of calls a provider may receive per minute and per UTC day.
+// A zero budget is unlimited.
+type QuotaBudget struct {
+	PerMinute int
+	PerDay    int
+}
+
+// QuotaGovernor keeps a provider within its call budgets. Calls are counted in fixed
+// windows aligned to the minute and the UTC day, in the cache so that all replicas
+// share one budget. While the cache is unavailable each replica counts in memory.
+type QuotaGovernor struct {
+	provider string
+	budget   QuotaBudget
+	cache    interfaces.Cache
+	memory   *ratelimit.MemoryStore
+	degraded atomic.Bool
+	now      func() time.Time // testable clock
+}
+
+// NewQuotaGovernor creates a governor for the named provider; cache may be nil to count in memory only.
+func NewQuotaGovernor(provider string, budget QuotaBudget, cache interfaces.Cache) *QuotaGovernor {
+	return &QuotaGovernor{
+		provider: provider,
+		budget:   budget,
+		cache:    cache,
+		memory:   ratelimit.NewMemoryStore(),
+		now:      time.Now,
+	}
+}
+
+// Wrap returns a client that takes each call from the budgets before passing it to client,
+// and each retry the client sends for it.
+// The result implements interfaces.ForecastAPIClient exactly when client does.
+func (g *QuotaGovernor) Wrap(client interfaces.WeatherAPIClient) interfaces.WeatherAPIClient {
+	governed := &quotaClient{governor: g, client: client}
+	if forecaster, ok := client.(interfaces.ForecastAPIClient); ok {
+		return &quotaForecastClient{quotaClient: governed, forecaster: forecaster}
+	}
+	return governed
+}
+
+// Acquire takes one call from the budgets, or returns an *interfaces.QuotaExceededError
+// when the minute or day budget is used up.
+func (g *QuotaGovernor) Acquire(ctx context.Context) error {
+	now := g.now().UTC()
+	minute, day := windows(now)
+
+	if g.budget.PerMinute > 0 {
+		if n := g.increment(ctx, g.key("minute", minute), 2*time.Minute); n > int64(g.budget.PerMinute) {
+			return &interfaces.QuotaExceededError{Provider: g.provider, Period: "minute", RetryAfter: minute.Add(time.Minute).Sub(now)}
+		}
+	}
+	if g.budget.PerDay > 0 {
+		if n := g.increment(ctx, g.key("day", day), 25*time.Hour); n > int64(g.budget.PerDay) {
+			return &interfaces.QuotaExceededError{Provider: g.provider, Period: "day", RetryAfter: day.AddDate(0, 0, 1).Sub(now)}
+		}
+	}
+	return nil
+}
+
+// Usage reports the calls counted in the current minute and day. Refused calls are counted as well.
+func (g *QuotaGovernor) Usage(ctx context.Context) (interfaces.QuotaUsage, error) {
+	now := g.now().UTC()
+	minute, day := windows(now)
+
+	return interfaces.QuotaUsage{
+		Provider:       g.provider,
+		MinuteUsed:     g.get(ctx, g.key("minute", minute)),
+		MinuteLimit:    g.budget.PerMinute,
+		MinuteResetsAt: minute.Add(time.Minute),
+		DayUsed:        g.get(ctx, g.key("day", day)),
+		DayLimit:       g.budget.PerDay,
+		DayResetsAt:    day.AddDate(0, 0, 1),
+		Shared:         g.cache != nil && !g.degraded.Load(),
+	}, nil
+}
+
+// retries returns ctx with the governor's budgets charged for each retry the client makes
+func (g *QuotaGovernor) retries(ctx context.Context) context.Context {
+	return interfaces.WithRetryAcquire(ctx, g.Acquire)
+}
+
+// windows returns the start of the minute and the UTC day containing now
+func windows(now time.Time) (time.Time, time.Time) {
+	return now.Truncate(time.Minute), time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (g *QuotaGovernor) key(period string, start time.Time) string {
+	return fmt.Sprintf("%s%s:%s:%d", quotaKeyPrefix, g.provider, period, start.Unix())
+}
+
+// increment counts a call in the cache, falling back to memory while the cache fails
+func (g *QuotaGovernor) increment(ctx context.Context, key string, ttl time.Duration) int64 {
+	if g.cache != nil {
+		n, err := g.cache.Increment(ctx, key)
+		if err == nil && n == 1 {
+			err = g.cache.Expire(ctx, key, ttl)
+		}
+		if err == nil {
+			if g.degraded.CompareAndSwap(true, false) {
+				logger.Infof("%s quota is counted in the shared cache again", g.provider)
+			}
+			return n
+		}
+		if g.degraded.CompareAndSwap(false, true) {
+			logger.Warnf("%s quota cache unavailable, counting in memory per instance: %v", g.provider, err)
+		}
+	}
+	return g.memory.Increment(key, ttl)
+}
+
+// get reads a counter from wherever increment is currently counting
+func (g *QuotaGovernor) get(ctx context.Context, key string) int64 {
+	if g.cache == nil || g.degraded.Load() {
+		return g.memory.Get(key)
+	}
+	var n int64
+	if err := g.cache.Get(ctx, key, &n); err != nil {
+		return 0
+	}
+	return n
+}
+
+// quotaClient is a weather client whose calls are taken from a governor's budgets
+type quotaClient struct {
+	governor *QuotaGovernor
+	client   interfaces.WeatherAPIClient
+}
+
+func (q *quotaClient) FetchWeatherData(ctx context.Context, city string, country string) (*interfaces.WeatherAPIResponse, error) {
+	if err := q.governor.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	return q.client.FetchWeatherData(q.governor.retries(ctx), city, country)
+}
+
+func (q *quotaClient) FetchWeatherByCoordinates(ctx context.Context, lat float64, lon float64) (*interfaces.WeatherAPIResponse, error) {
+	if err := q.governor.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	return q.client.FetchWeatherByCoordinates(q.governor.retries(ctx), lat, lon)
+}
+
+// quotaForecastClient is a quotaClient for providers that also serve forecasts
+type quotaForecastClient struct {
+	*quotaClient
+	forecaster interfaces.ForecastAPIClient
+}
+
+func (q *quotaForecastClient) FetchForecast(ctx context.Context, city string, country string) (*interfaces.ForecastAPIResponse, error) {
+	if err := q.governor.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	return q.forecaster.FetchForecast(q.governor.retries(ctx), city, country)
+}
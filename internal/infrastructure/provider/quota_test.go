@@ -0,0 +1,205 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeCache implements the counter operations the governor uses; with down set every call fails
+type fakeCache struct {
+	interfaces.Cache
+	mu      sync.Mutex
+	counter map[string]int64
+	down    bool
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{counter: make(map[string]int64)}
+}
+
+func (f *fakeCache) Increment(ctx context.Context, key string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.down {
+		return 0, errors.New("connection refused")
+	}
+	f.counter[key]++
+	return f.counter[key], nil
+}
+
+func (f *fakeCache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return nil
+}
+
+func (f *fakeCache) Get(ctx context.Context, key string, dest interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n, ok := f.counter[key]
+	if !ok {
+		return errors.New("key not found")
+	}
+	*dest.(*int64) = n
+	return nil
+}
+
+// countingClient answers every call and counts them
+type countingClient struct {
+	calls int
+}
+
+func (c *countingClient) FetchWeatherData(ctx context.Context, city string, country string) (*interfaces.WeatherAPIResponse, error) {
+	c.calls++
+	return &interfaces.WeatherAPIResponse{City: city, Country: country}, nil
+}
+
+func (c *countingClient) FetchWeatherByCoordinates(ctx context.Context, lat float64, lon float64) (*interfaces.WeatherAPIResponse, error) {
+	c.calls++
+	return &interfaces.WeatherAPIResponse{}, nil
+}
+
+type countingForecastClient struct {
+	countingClient
+}
+
+func (c *countingForecastClient) FetchForecast(ctx context.Context, city string, country string) (*interfaces.ForecastAPIResponse, error) {
+	c.calls++
+	return &interfaces.ForecastAPIResponse{}, nil
+}
+
+// retryingClient retries every weather call until its budget refuses, up to attempts calls
+type retryingClient struct {
+	countingClient
+	attempts int
+}
+
+func (c *retryingClient) FetchWeatherData(ctx context.Context, city string, country string) (*interfaces.WeatherAPIResponse, error) {
+	c.calls++
+	for i := 1; i < c.attempts; i++ {
+		if err := interfaces.AcquireRetry(ctx); err != nil {
+			return nil, err
+		}
+		c.calls++
+	}
+	return &interfaces.WeatherAPIResponse{City: city, Country: country}, nil
+}
+
+// newTestGovernor returns a governor whose clock is *now
+func newTestGovernor(budget QuotaBudget, cache interfaces.Cache, now *time.Time) *QuotaGovernor {
+	g := NewQuotaGovernor("openweather", budget, cache)
+	g.now = func() time.Time { return *now }
+	return g
+}
+
+func TestQuotaGovernor_SharedBudgets(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 30, 0, time.UTC)
+	cache := newFakeCache()
+	ctx := context.Background()
+
+	// Two instances take from the same budgets
+	a := newTestGovernor(QuotaBudget{PerMinute: 2, PerDay: 3}, cache, &now)
+	b := newTestGovernor(QuotaBudget{PerMinute: 2, PerDay: 3}, cache, &now)
+	assert.NoError(t, a.Acquire(ctx))
+	assert.NoError(t, b.Acquire(ctx))
+
+	var quotaErr *interfaces.QuotaExceededError
+	err := a.Acquire(ctx)
+	assert.True(t, errors.As(err, &quotaErr))
+	assert.True(t, errors.Is(err, interfaces.ErrQuotaExhausted))
+	assert.Equal(t, "minute", quotaErr.Period)
+	assert.Equal(t, 30*time.Second, quotaErr.RetryAfter)
+
+	// The next minute has room, but the day budget is used up
+	now = now.Add(time.Minute)
+	assert.NoError(t, b.Acquire(ctx))
+	err = a.Acquire(ctx)
+	assert.True(t, errors.As(err, &quotaErr))
+	assert.Equal(t, "day", quotaErr.Period)
+	assert.Equal(t, 11*time.Hour+58*time.Minute+30*time.Second, quotaErr.RetryAfter)
+
+	usage, err := a.Usage(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "openweather", usage.Provider)
+	assert.Equal(t, int64(2), usage.MinuteUsed)
+	assert.Equal(t, 2, usage.MinuteLimit)
+	assert.Equal(t, time.Date(2025, 1, 1, 12, 2, 0, 0, time.UTC), usage.MinuteResetsAt)
+	assert.Equal(t, int64(4), usage.DayUsed)
+	assert.Equal(t, 3, usage.DayLimit)
+	assert.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), usage.DayResetsAt)
+	assert.True(t, usage.Shared)
+}
+
+func TestQuotaGovernor_FallsBackToMemory(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := newFakeCache()
+	cache.down = true
+	g := newTestGovernor(QuotaBudget{PerMinute: 1}, cache, &now)
+	ctx := context.Background()
+
+	assert.NoError(t, g.Acquire(ctx))
+	assert.Error(t, g.Acquire(ctx))
+	assert.Empty(t, cache.counter)
+
+	usage, _ := g.Usage(ctx)
+	assert.Equal(t, int64(2), usage.MinuteUsed)
+	assert.False(t, usage.Shared)
+
+	// Once the cache is back it is used again
+	cache.down = false
+	now = now.Add(time.Minute)
+	assert.NoError(t, g.Acquire(ctx))
+	assert.Len(t, cache.counter, 1)
+}
+
+func TestQuotaGovernor_Wrap(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	// Forecast support is kept
+	_, ok := newTestGovernor(QuotaBudget{}, nil, &now).Wrap(&countingClient{}).(interfaces.ForecastAPIClient)
+	assert.False(t, ok)
+	_, ok = newTestGovernor(QuotaBudget{}, nil, &now).Wrap(&countingForecastClient{}).(interfaces.ForecastAPIClient)
+	assert.True(t, ok)
+
+	// An exhausted provider is failed over without being called
+	governed := &countingForecastClient{}
+	fallback := &countingClient{}
+	client := NewFailoverClient(
+		Provider{Name: "openweather", Client: newTestGovernor(QuotaBudget{PerMinute: 1}, nil, &now).Wrap(governed)},
+		Provider{Name: "openmeteo", Client: fallback},
+	)
+	for i := 0; i < 3; i++ {
+		res, err := client.FetchWeatherData(ctx, "Berlin", "DE")
+		assert.NoError(t, err)
+		assert.NotNil(t, res)
+	}
+	assert.Equal(t, 1, governed.calls)
+	assert.Equal(t, 2, fallback.calls)
+
+	// Without another provider the quota error reaches the caller
+	only := NewFailoverClient(Provider{Name: "openweather", Client: newTestGovernor(QuotaBudget{PerMinute: 1}, nil, &now).Wrap(&countingForecastClient{})})
+	_, err := only.FetchForecast(ctx, "Berlin", "DE")
+	assert.NoError(t, err)
+	_, err = only.FetchForecast(ctx, "Berlin", "DE")
+	assert.True(t, errors.Is(err, interfaces.ErrQuotaExhausted))
+}
+
+func TestQuotaGovernor_ChargesRetries(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+	g := newTestGovernor(QuotaBudget{PerMinute: 4}, newFakeCache(), &now)
+
+	client := &retryingClient{attempts: 3}
+	_, err := g.Wrap(client).FetchWeatherData(ctx, "Berlin", "DE")
+	assert.NoError(t, err)
+
+	// The second call gets one attempt before the budget of 4 is used up
+	_, err = g.Wrap(client).FetchWeatherData(ctx, "Berlin", "DE")
+	assert.True(t, errors.Is(err, interfaces.ErrQuotaExhausted))
+	assert.Equal(t, 4, client.calls)
+}
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/OmidRasouli/weather-api/config"
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/infrastructure/openmeteo"
+	"github.com/OmidRasouli/weather-api/internal/infrastructure/openweather"
+	"github.com/OmidRasouli/weather-api/pkg/logger"
+)
+
+// Factory builds a weather API client for a provider from the application configuration.
+type Factory func(cfg *config.Config) (interfaces.WeatherAPIClient, error)
+
+// Registry maps provider names to the factories that build them.
+type Registry struct {
+	factories map[string]Factory
+}
+
+// NewRegistry creates an empty provider registry.
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]Factory)}
+}
+
+// DefaultRegistry returns a registry with every built-in provider registered.
+func DefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.Register(openweather.ProviderName, func(cfg *config.Config) (interfaces.WeatherAPIClient, error) {
+		if cfg.OpenWeather.APIKey == "" {
+			return nil, fmt.Errorf("OPENWEATHER_API_KEY is not set")
+		}
+		ow := cfg.OpenWeather
+		return openweather.NewClient(ow.APIKey).
+			WithBaseURL(ow.BaseURL).
+			WithResilience(openweather.RetryPolicy{
+				MaxAttempts: ow.MaxAttempts,
+				Backoff:     ow.RetryBackoff,
+				MaxBackoff:  ow.MaxRetryBackoff,
+			}, openweather.NewCircuitBreaker(ow.BreakerThreshold, ow.BreakerCooldown)), nil
+	})
+	r.Register(openmeteo.ProviderName, func(cfg *config.Config) (interfaces.WeatherAPIClient, error) {
+		return openmeteo.NewClient().WithBaseURLs(cfg.OpenMeteo.BaseURL, cfg.OpenMeteo.GeocodingURL), nil
+	})
+	return r
+}
+
+// Register adds or replaces the factory for a provider name.
+func (r *Registry) Register(name string, factory Factory) {
+	r.factories[strings.ToLower(name)] = factory
+}
+
+// Names returns the registered provider names in alphabetical order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Build creates the named providers in the given priority order.
+// Unknown names are a configuration error; a provider whose factory fails
+// (for example because its API key is missing) is skipped with a warning.
+func (r *Registry) Build(cfg *config.Config, names []string) ([]Provider, error) {
+	providers := make([]Provider, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		factory, ok := r.factories[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown weather provider %q (available: %s)", name, strings.Join(r.Names(), ", "))
+		}
+		client, err := factory(cfg)
+		if err != nil {
+			logger.Warnf("Skipping weather provider %s: %v", name, err)
+			continue
+		}
+		providers = append(providers, Provider{Name: name, Client: client})
+	}
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no usable weather providers configured")
+	}
+	return providers, nil
+}
@@ -0,0 +1,83 @@
+package signing
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/services"
+)
+
+// LoadKeySet builds the JWT key set. New tokens are signed with the private key in
+// signingKeyFile, or with the HMAC secret when no file is given. Keys in
+// verificationKeyFiles (public or private PEM) are only used to verify, e.g. a retired
+// key whose tokens have not expired yet, or the next key published ahead of a rotation.
+// When both a signing key file and a secret are set, HS256 tokens stay valid as well.
+func LoadKeySet(secret string, signingKeyFile string, verificationKeyFiles []string) (*services.KeySet, error) {
+	var active *services.SigningKey
+	var verification []*services.SigningKey
+
+	if signingKeyFile != "" {
+		key, err := LoadKey(signingKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if !key.CanSign() {
+			return nil, fmt.Errorf("%s: signing key must be a private key", signingKeyFile)
+		}
+		active = key
+		if secret != "" {
+			verification = append(verification, services.NewHMACKey([]byte(secret)))
+		}
+	} else if secret != "" {
+		active = services.NewHMACKey([]byte(secret))
+	}
+
+	for _, path := range verificationKeyFiles {
+		key, err := LoadKey(path)
+		if err != nil {
+			return nil, err
+		}
+		verification = append(verification, key)
+	}
+
+	return services.NewKeySet(active, verification...), nil
+}
+
+// LoadKey reads an RSA or Ed25519 key from a PEM file. PKCS#8 and PKCS#1 private keys
+// and PKIX and PKCS#1 public keys are accepted.
+func LoadKey(path string) (*services.SigningKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM block found", path)
+	}
+
+	var key interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%s: unsupported PEM block %q", path, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	signingKey, err := services.NewAsymmetricKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return signingKey, nil
+}
@@ -0,0 +1,110 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/services"
+	"github.com/OmidRasouli/weather-api/internal/domain/user"
+	"github.com/stretchr/testify/assert"
+)
+
+// writePEM stores a key in dir and returns the file path
+func writePEM(t *testing.T, dir string, name string, blockType string, der []byte) string {
+	path := filepath.Join(dir, name)
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600)
+	assert.NoError(t, err)
+	return path
+}
+
+func TestLoadKeySet_Rotation(t *testing.T) {
+	dir := t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	oldFile := writePEM(t, dir, "old.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(edKey)
+	assert.NoError(t, err)
+	newFile := writePEM(t, dir, "new.pem", "PRIVATE KEY", der)
+
+	// Before the rotation tokens are signed with the RSA key
+	before, err := LoadKeySet("", oldFile, nil)
+	assert.NoError(t, err)
+	oldToken, _, err := services.NewAuthService(before).GenerateToken("alice", user.RoleEditor, time.Minute)
+	assert.NoError(t, err)
+
+	// After it the Ed25519 key signs and the RSA key only verifies
+	after, err := LoadKeySet("", newFile, []string{oldFile})
+	assert.NoError(t, err)
+	auth := services.NewAuthService(after)
+
+	claims, err := auth.ValidateToken(oldToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", claims.Subject)
+
+	newToken, _, err := auth.GenerateToken("alice", user.RoleEditor, time.Minute)
+	assert.NoError(t, err)
+	_, err = auth.ValidateToken(newToken)
+	assert.NoError(t, err)
+
+	jwks := auth.JWKS()
+	assert.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "EdDSA", jwks.Keys[0].Alg)
+	assert.Equal(t, "OKP", jwks.Keys[0].Kty)
+	assert.Equal(t, "RS256", jwks.Keys[1].Alg)
+
+	// Once the old key is dropped its tokens are rejected
+	dropped, err := LoadKeySet("", newFile, nil)
+	assert.NoError(t, err)
+	_, err = services.NewAuthService(dropped).ValidateToken(oldToken)
+	assert.Error(t, err)
+}
+
+func TestLoadKeySet_HMACFallback(t *testing.T) {
+	dir := t.TempDir()
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(edKey)
+	assert.NoError(t, err)
+	keyFile := writePEM(t, dir, "key.pem", "PRIVATE KEY", der)
+
+	legacy, err := LoadKeySet("shared-secret", "", nil)
+	assert.NoError(t, err)
+	legacyToken, _, err := services.NewAuthService(legacy).GenerateToken("alice", user.RoleViewer, time.Minute)
+	assert.NoError(t, err)
+	assert.Empty(t, services.NewAuthService(legacy).JWKS().Keys)
+
+	// Switching to a key file keeps HS256 tokens valid while JWT_SECRET is still set
+	migrated, err := LoadKeySet("shared-secret", keyFile, nil)
+	assert.NoError(t, err)
+	_, err = services.NewAuthService(migrated).ValidateToken(legacyToken)
+	assert.NoError(t, err)
+	assert.Len(t, services.NewAuthService(migrated).JWKS().Keys, 1)
+}
+
+func TestLoadKeySet_PublicKeyCannotSign(t *testing.T) {
+	dir := t.TempDir()
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	assert.NoError(t, err)
+	pubFile := writePEM(t, dir, "pub.pem", "PUBLIC KEY", der)
+
+	_, err = LoadKeySet("", pubFile, nil)
+	assert.Error(t, err)
+
+	ks, err := LoadKeySet("", "", []string{pubFile})
+	assert.NoError(t, err)
+	_, err = ks.Active()
+	assert.ErrorIs(t, err, services.ErrNoSigningKey)
+}
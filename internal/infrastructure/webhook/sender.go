@@ -0,0 +1,126 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/alert"
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	// SignatureHeader carries "sha256=<hex HMAC>" of "<timestamp>.<body>" keyed with the rule secret.
+	SignatureHeader = "X-Weather-Signature"
+	// TimestampHeader carries the Unix time the payload was signed at, so receivers can reject replays.
+	TimestampHeader = "X-Weather-Timestamp"
+	// DeliveryHeader carries the delivery ID, which stays the same across retries.
+	DeliveryHeader = "X-Weather-Delivery"
+)
+
+// ErrForbiddenAddress is returned when a webhook resolves to an address deliveries may not reach.
+var ErrForbiddenAddress = errors.New("webhook address is not public")
+
+// Sender delivers alert payloads over HTTP. Only public addresses are dialed, whatever the
+// URL resolves to at delivery time or redirects to.
+type Sender struct {
+	client     *resty.Client
+	allow      func(netip.Addr) bool
+	timeSource func() time.Time // testable clock
+}
+
+func NewSender(timeout time.Duration) *Sender {
+	s := &Sender{
+		allow:      alert.PublicAddress,
+		timeSource: time.Now,
+	}
+	dialer := &net.Dialer{Timeout: timeout, Control: s.control}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialed instead of the webhook, so the address check would not apply
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	s.client = resty.New().SetTimeout(timeout).SetTransport(transport)
+	return s
+}
+
+// WithAddressFilter replaces the check of the addresses webhooks may be sent to, which by
+// default only allows public ones
+func (s *Sender) WithAddressFilter(allow func(netip.Addr) bool) *Sender {
+	s.allow = allow
+	return s
+}
+
+// CheckURL resolves the host of rawURL and returns ErrForbiddenAddress if any of its
+// addresses may not be sent to.
+func (s *Sender) CheckURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil {
+		return fmt.Errorf("failed to resolve webhook host: %w", err)
+	}
+	for _, addr := range addrs {
+		if !s.allow(addr) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrForbiddenAddress, u.Hostname(), addr)
+		}
+	}
+	return nil
+}
+
+// control refuses connections to addresses that may not be sent to, once the host is resolved
+func (s *Sender) control(network string, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !s.allow(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, addrPort.Addr())
+	}
+	return nil
+}
+
+// Sign returns the signature header value for a payload signed at timestamp.
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is valid for the payload and timestamp.
+func Verify(secret string, timestamp int64, payload []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, payload)), []byte(signature))
+}
+
+// Send makes a single delivery attempt. Non-2xx responses are returned as errors along with their status code.
+func (s *Sender) Send(ctx context.Context, url string, secret string, deliveryID string, payload []byte) (int, error) {
+	timestamp := s.timeSource().Unix()
+	resp, err := s.client.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetHeader(SignatureHeader, Sign(secret, timestamp, payload)).
+		SetHeader(TimestampHeader, strconv.FormatInt(timestamp, 10)).
+		SetHeader(DeliveryHeader, deliveryID).
+		SetBody(payload).
+		Post(url)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call webhook: %w", err)
+	}
+	if resp.IsError() {
+		return resp.StatusCode(), fmt.Errorf("webhook returned status %d", resp.StatusCode())
+	}
+	return resp.StatusCode(), nil
+}
@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// allowAll lets the tests deliver to their loopback servers
+func allowAll(netip.Addr) bool {
+	return true
+}
+
+func TestSend_SignsPayload(t *testing.T) {
+	payload := []byte(`{"rule":"heat"}`)
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sender := NewSender(time.Second).WithAddressFilter(allowAll)
+	sender.timeSource = func() time.Time { return time.Unix(1700000000, 0) }
+
+	status, err := sender.Send(context.Background(), server.URL, "s3cret", "delivery-1", payload)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, status)
+	assert.Equal(t, payload, body)
+	assert.Equal(t, "delivery-1", received.Header.Get(DeliveryHeader))
+	timestamp, _ := strconv.ParseInt(received.Header.Get(TimestampHeader), 10, 64)
+	assert.Equal(t, int64(1700000000), timestamp)
+	assert.True(t, Verify("s3cret", timestamp, body, received.Header.Get(SignatureHeader)))
+	assert.False(t, Verify("other", timestamp, body, received.Header.Get(SignatureHeader)))
+}
+
+func TestSend_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	status, err := NewSender(time.Second).WithAddressFilter(allowAll).Send(context.Background(), server.URL, "s3cret", "delivery-1", []byte(`{}`))
+
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+}
+
+func TestSend_RefusesLocalAddresses(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	status, err := NewSender(time.Second).Send(context.Background(), server.URL, "s3cret", "delivery-1", []byte(`{}`))
+
+	assert.True(t, errors.Is(err, ErrForbiddenAddress))
+	assert.Equal(t, 0, status)
+	assert.Equal(t, 0, calls)
+}
+
+func TestCheckURL(t *testing.T) {
+	sender := NewSender(time.Second)
+	ctx := context.Background()
+
+	assert.NoError(t, sender.CheckURL(ctx, "https://203.0.113.10/hook"))
+	for _, url := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://10.1.2.3/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+		"http://[::ffff:192.168.1.1]/hook",
+	} {
+		assert.True(t, errors.Is(sender.CheckURL(ctx, url), ErrForbiddenAddress), url)
+	}
+}
@@ -0,0 +1,295 @@
+package controller
+
+import (
+	"context"
+	stdErrors "errors"
+	"net/http"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/alert"
+	"github.com/OmidRasouli/weather-api/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// AlertService defines the operations for managing alert rules and reading their delivery log.
+type AlertService interface {
+	CreateRule(ctx context.Context, r *alert.Rule) (*alert.Rule, error)
+	ListRules(ctx context.Context) ([]*alert.Rule, error)
+	GetRule(ctx context.Context, id string) (*alert.Rule, error)
+	UpdateRule(ctx context.Context, id string, update *alert.Rule) (*alert.Rule, error)
+	DeleteRule(ctx context.Context, id string) error
+	ListDeliveries(ctx context.Context, ruleID string, limit int) ([]*alert.Delivery, error)
+}
+
+type AlertController struct {
+	service AlertService
+}
+
+func NewAlertController(service AlertService) *AlertController {
+	return &AlertController{service: service}
+}
+
+// AlertRuleRequest describes a condition such as "temperature in Berlin,DE > 35" and the webhook to notify.
+// City and country are optional; an empty value matches every location.
+type AlertRuleRequest struct {
+	Name       string   `json:"name"`
+	City       string   `json:"city"`
+	Country    string   `json:"country" binding:"omitempty,min=2,max=3,alpha"`
+	Field      string   `json:"field" binding:"required" example:"temperature"`
+	Operator   string   `json:"operator" binding:"required,oneof=> >= < <= == !=" example:">"`
+	Threshold  *float64 `json:"threshold" binding:"required" example:"35"`
+	WebhookURL string   `json:"webhookUrl" binding:"required,url" example:"https://example.com/hooks/weather"`
+	Secret     string   `json:"secret" binding:"omitempty,min=16"`
+	Enabled    *bool    `json:"enabled"`
+}
+
+func (r *AlertRuleRequest) toDomain() *alert.Rule {
+	return &alert.Rule{
+		Name:       r.Name,
+		City:       r.City,
+		Country:    r.Country,
+		Field:      r.Field,
+		Operator:   alert.Operator(r.Operator),
+		Threshold:  *r.Threshold,
+		WebhookURL: r.WebhookURL,
+		Secret:     r.Secret,
+		Enabled:    r.Enabled == nil || *r.Enabled,
+	}
+}
+
+// AlertRuleResponse is the API representation of an alert rule. The secret is only returned on creation.
+type AlertRuleResponse struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	City       string    `json:"city"`
+	Country    string    `json:"country"`
+	Field      string    `json:"field"`
+	Operator   string    `json:"operator"`
+	Threshold  float64   `json:"threshold"`
+	WebhookURL string    `json:"webhookUrl"`
+	Secret     string    `json:"secret,omitempty"`
+	Enabled    bool      `json:"enabled"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+func toAlertRuleResponse(r *alert.Rule) AlertRuleResponse {
+	return AlertRuleResponse{
+		ID:         r.ID.String(),
+		Name:       r.Name,
+		City:       r.City,
+		Country:    r.Country,
+		Field:      r.Field,
+		Operator:   string(r.Operator),
+		Threshold:  r.Threshold,
+		WebhookURL: r.WebhookURL,
+		Enabled:    r.Enabled,
+		CreatedAt:  r.CreatedAt,
+		UpdatedAt:  r.UpdatedAt,
+	}
+}
+
+// AlertDeliveryResponse is one entry of a rule's webhook delivery log.
+type AlertDeliveryResponse struct {
+	ID         string    `json:"id"`
+	WeatherID  string    `json:"weatherId"`
+	URL        string    `json:"url"`
+	Attempts   int       `json:"attempts"`
+	StatusCode int       `json:"statusCode"`
+	Succeeded  bool      `json:"succeeded"`
+	Error      string    `json:"error,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// AlertDeliveriesQuery holds the parameters accepted by GET /alerts/{id}/deliveries.
+type AlertDeliveriesQuery struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+// Create godoc
+// @Summary      Create alert rule
+// @Description  Creates a rule that posts an HMAC-signed webhook whenever a newly stored observation satisfies field operator threshold. Fields: temperature, feels_like, temp_min, temp_max, humidity, pressure, visibility, clouds, wind_speed, wind_deg, wind_gust, rain, snow (metric units). The signing secret is generated when omitted and only returned in this response.
+// @Tags         alerts
+// @Accept       json
+// @Produce      json
+// @Param        request  body      AlertRuleRequest  true  "Alert rule"
+// @Success      201      {object}  AlertRuleResponse
+// @Failure      400      {object}  errors.AppError "Invalid request data"
+// @Failure      500      {object}  errors.AppError "Failed to create alert rule"
+// @Router       /alerts [post]
+func (ac *AlertController) Create(c *gin.Context) {
+	var req AlertRuleRequest
+	if !ac.bindRule(c, &req) {
+		return
+	}
+
+	result, err := ac.service.CreateRule(c, req.toDomain())
+	if err != nil {
+		ac.handleError(c, "Failed to create alert rule", err)
+		return
+	}
+
+	response := toAlertRuleResponse(result)
+	response.Secret = result.Secret
+	c.JSON(http.StatusCreated, response)
+}
+
+// List godoc
+// @Summary      List alert rules
+// @Description  Lists every alert rule
+// @Tags         alerts
+// @Produce      json
+// @Success      200  {array}   AlertRuleResponse
+// @Failure      500  {object}  errors.AppError "Failed to list alert rules"
+// @Router       /alerts [get]
+func (ac *AlertController) List(c *gin.Context) {
+	rules, err := ac.service.ListRules(c)
+	if err != nil {
+		_ = c.Error(errors.NewInternalServerError("Failed to list alert rules", err))
+		return
+	}
+
+	result := make([]AlertRuleResponse, 0, len(rules))
+	for _, r := range rules {
+		result = append(result, toAlertRuleResponse(r))
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetByID godoc
+// @Summary      Get alert rule
+// @Description  Retrieves an alert rule by its ID
+// @Tags         alerts
+// @Produce      json
+// @Param        id   path      string  true  "Alert rule ID"
+// @Success      200  {object}  AlertRuleResponse
+// @Failure      404  {object}  errors.AppError "Alert rule not found"
+// @Router       /alerts/{id} [get]
+func (ac *AlertController) GetByID(c *gin.Context) {
+	result, err := ac.service.GetRule(c, c.Param("id"))
+	if err != nil {
+		_ = c.Error(errors.NewNotFound("Alert rule not found", err))
+		return
+	}
+	c.JSON(http.StatusOK, toAlertRuleResponse(result))
+}
+
+// Update godoc
+// @Summary      Update alert rule
+// @Description  Replaces the condition, webhook and enabled flag of a rule. The secret is rotated only when a new one is given.
+// @Tags         alerts
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string            true  "Alert rule ID"
+// @Param        request  body      AlertRuleRequest  true  "Alert rule"
+// @Success      200      {object}  AlertRuleResponse
+// @Failure      400      {object}  errors.AppError "Invalid request data"
+// @Failure      404      {object}  errors.AppError "Alert rule not found"
+// @Failure      500      {object}  errors.AppError "Failed to update alert rule"
+// @Router       /alerts/{id} [put]
+func (ac *AlertController) Update(c *gin.Context) {
+	var req AlertRuleRequest
+	if !ac.bindRule(c, &req) {
+		return
+	}
+
+	result, err := ac.service.UpdateRule(c, c.Param("id"), req.toDomain())
+	if err != nil {
+		ac.handleError(c, "Failed to update alert rule", err)
+		return
+	}
+	c.JSON(http.StatusOK, toAlertRuleResponse(result))
+}
+
+// Delete godoc
+// @Summary      Delete alert rule
+// @Description  Deletes an alert rule together with its delivery log
+// @Tags         alerts
+// @Produce      json
+// @Param        id   path      string  true  "Alert rule ID"
+// @Success      200  {object}  map[string]string "Alert rule deleted"
+// @Failure      404  {object}  errors.AppError "Alert rule not found"
+// @Failure      500  {object}  errors.AppError "Failed to delete alert rule"
+// @Router       /alerts/{id} [delete]
+func (ac *AlertController) Delete(c *gin.Context) {
+	if err := ac.service.DeleteRule(c, c.Param("id")); err != nil {
+		ac.handleError(c, "Failed to delete alert rule", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Alert rule deleted"})
+}
+
+// GetDeliveries godoc
+// @Summary      List alert deliveries
+// @Description  Returns the most recent webhook deliveries of a rule, newest first
+// @Tags         alerts
+// @Produce      json
+// @Param        id     path      string  true   "Alert rule ID"
+// @Param        limit  query     int     false  "Maximum number of deliveries (1-500)" default(50)
+// @Success      200    {array}   AlertDeliveryResponse
+// @Failure      400    {object}  errors.AppError "Invalid query parameters"
+// @Failure      404    {object}  errors.AppError "Alert rule not found"
+// @Failure      500    {object}  errors.AppError "Failed to list alert deliveries"
+// @Router       /alerts/{id}/deliveries [get]
+func (ac *AlertController) GetDeliveries(c *gin.Context) {
+	var req AlertDeliveriesQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(errors.NewBadRequest("Invalid query parameters", err))
+		return
+	}
+
+	deliveries, err := ac.service.ListDeliveries(c, c.Param("id"), req.Limit)
+	if err != nil {
+		ac.handleError(c, "Failed to list alert deliveries", err)
+		return
+	}
+
+	result := make([]AlertDeliveryResponse, 0, len(deliveries))
+	for _, d := range deliveries {
+		result = append(result, AlertDeliveryResponse{
+			ID:         d.ID.String(),
+			WeatherID:  d.WeatherID.String(),
+			URL:        d.URL,
+			Attempts:   d.Attempts,
+			StatusCode: d.StatusCode,
+			Succeeded:  d.Succeeded,
+			Error:      d.Error,
+			CreatedAt:  d.CreatedAt,
+			UpdatedAt:  d.UpdatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// bindRule decodes and validates an alert rule body, recording a 400 on failure
+func (ac *AlertController) bindRule(c *gin.Context, req *AlertRuleRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if ok {
+			details := make(map[string]string)
+			for _, e := range validationErrors {
+				details[e.Field()] = e.Error()
+			}
+			_ = c.Error(errors.ValidationError("Invalid request data", details))
+			return false
+		}
+		_ = c.Error(errors.NewBadRequest("Invalid request body", err))
+		return false
+	}
+	return true
+}
+
+// handleError maps validation and lookup failures to 400/404 and everything else to 500
+func (ac *AlertController) handleError(c *gin.Context, message string, err error) {
+	switch {
+	case stdErrors.Is(err, alert.ErrInvalidRule):
+		_ = c.Error(errors.NewBadRequest(err.Error(), err))
+	case stdErrors.Is(err, gorm.ErrRecordNotFound):
+		_ = c.Error(errors.NewNotFound("Alert rule not found", err))
+	default:
+		_ = c.Error(errors.NewInternalServerError(message, err))
+	}
+}
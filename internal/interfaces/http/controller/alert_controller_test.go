@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/alert"
+	"github.com/OmidRasouli/weather-api/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAlertService is a mock implementation of the AlertService interface
+type MockAlertService struct {
+	mock.Mock
+}
+
+func (m *MockAlertService) CreateRule(ctx context.Context, r *alert.Rule) (*alert.Rule, error) {
+	args := m.Called(ctx, r)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*alert.Rule), args.Error(1)
+}
+
+func (m *MockAlertService) ListRules(ctx context.Context) ([]*alert.Rule, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*alert.Rule), args.Error(1)
+}
+
+func (m *MockAlertService) GetRule(ctx context.Context, id string) (*alert.Rule, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*alert.Rule), args.Error(1)
+}
+
+func (m *MockAlertService) UpdateRule(ctx context.Context, id string, update *alert.Rule) (*alert.Rule, error) {
+	args := m.Called(ctx, id, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*alert.Rule), args.Error(1)
+}
+
+func (m *MockAlertService) DeleteRule(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAlertService) ListDeliveries(ctx context.Context, ruleID string, limit int) ([]*alert.Delivery, error) {
+	args := m.Called(ctx, ruleID, limit)
+	return args.Get(0).([]*alert.Delivery), args.Error(1)
+}
+
+func TestCreateAlert_ReturnsSecretOnce(t *testing.T) {
+	mockService := new(MockAlertService)
+	sut := NewAlertController(mockService)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	reqBody := `{"city":"berlin","country":"DE","field":"temperature","operator":">","threshold":35,"webhookUrl":"https://example.com/hook"}`
+	c.Request = httptest.NewRequest("POST", "/alerts", strings.NewReader(reqBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	created := &alert.Rule{ID: uuid.New(), City: "berlin", Country: "DE", Field: "temperature", Operator: alert.GreaterThan, Threshold: 35, Secret: "generated-secret", Enabled: true}
+	mockService.On("CreateRule", mock.Anything, mock.MatchedBy(func(r *alert.Rule) bool {
+		return r.City == "berlin" && r.Operator == alert.GreaterThan && r.Threshold == 35 && r.Enabled && r.Secret == ""
+	})).Return(created, nil)
+
+	sut.Create(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var body AlertRuleResponse
+	assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "generated-secret", body.Secret)
+}
+
+func TestCreateAlert_InvalidOperator(t *testing.T) {
+	mockService := new(MockAlertService)
+	sut := NewAlertController(mockService)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	reqBody := `{"field":"temperature","operator":"~","threshold":35,"webhookUrl":"https://example.com/hook"}`
+	c.Request = httptest.NewRequest("POST", "/alerts", strings.NewReader(reqBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	sut.Create(c)
+
+	assert.Equal(t, 1, len(c.Errors))
+	appErr, ok := c.Errors.Last().Err.(*errors.AppError)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, http.StatusBadRequest, appErr.Code)
+	_, hasOperator := appErr.Details["Operator"]
+	assert.Equal(t, true, hasOperator)
+	mockService.AssertNotCalled(t, "CreateRule", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"context"
+	stdErrors "errors"
+	"net/http"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/apikey"
+	"github.com/OmidRasouli/weather-api/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// APIKeyService defines the API key administration operations.
+type APIKeyService interface {
+	CreateKey(ctx context.Context, name string, scopes []string, createdBy string) (*apikey.Key, string, error)
+	ListKeys(ctx context.Context) ([]*apikey.Key, error)
+	RevokeKey(ctx context.Context, id string) (*apikey.Key, error)
+}
+
+type APIKeyController struct {
+	service APIKeyService
+}
+
+func NewAPIKeyController(service APIKeyService) *APIKeyController {
+	return &APIKeyController{service: service}
+}
+
+// CreateAPIKeyRequest is the body of POST /admin/api-keys.
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"required,max=100" example:"nightly-ingest"`
+	Scopes []string `json:"scopes" binding:"required,min=1,dive,oneof=read write" example:"read,write"`
+}
+
+// APIKeyResponse is the API representation of a key. The plaintext key is only returned on creation.
+type APIKeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	Key        string     `json:"key,omitempty"`
+	CreatedBy  string     `json:"createdBy"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+func toAPIKeyResponse(k *apikey.Key) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         k.ID.String(),
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		CreatedBy:  k.CreatedBy,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
+// Create godoc
+// @Summary      Create API key
+// @Description  Issues a long-lived key for machine clients, sent as the X-API-Key header. Scopes: read, write. The key is only returned in this response; only its hash is stored. Admin only.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateAPIKeyRequest  true  "New API key"
+// @Success      201      {object}  APIKeyResponse
+// @Failure      400      {object}  errors.AppError "Invalid request data"
+// @Failure      403      {object}  map[string]string "admin privileges required"
+// @Failure      500      {object}  errors.AppError "Failed to create API key"
+// @Router       /admin/api-keys [post]
+func (ac *APIKeyController) Create(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if ok {
+			details := make(map[string]string)
+			for _, e := range validationErrors {
+				details[e.Field()] = e.Error()
+			}
+			_ = c.Error(errors.ValidationError("Invalid request data", details))
+			return
+		}
+		_ = c.Error(errors.NewBadRequest("Invalid request body", err))
+		return
+	}
+
+	result, plaintext, err := ac.service.CreateKey(c, req.Name, req.Scopes, c.GetString("user"))
+	if err != nil {
+		ac.handleError(c, "Failed to create API key", err)
+		return
+	}
+
+	response := toAPIKeyResponse(result)
+	response.Key = plaintext
+	c.JSON(http.StatusCreated, response)
+}
+
+// List godoc
+// @Summary      List API keys
+// @Description  Lists every API key, including revoked ones, with its last-used time. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {array}   APIKeyResponse
+// @Failure      403  {object}  map[string]string "admin privileges required"
+// @Failure      500  {object}  errors.AppError "Failed to list API keys"
+// @Router       /admin/api-keys [get]
+func (ac *APIKeyController) List(c *gin.Context) {
+	keys, err := ac.service.ListKeys(c)
+	if err != nil {
+		_ = c.Error(errors.NewInternalServerError("Failed to list API keys", err))
+		return
+	}
+
+	result := make([]APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, toAPIKeyResponse(k))
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// Revoke godoc
+// @Summary      Revoke API key
+// @Description  Permanently disables an API key. The record is kept for auditing. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      string  true  "API key ID"
+// @Success      200  {object}  APIKeyResponse
+// @Failure      403  {object}  map[string]string "admin privileges required"
+// @Failure      404  {object}  errors.AppError "API key not found"
+// @Failure      500  {object}  errors.AppError "Failed to revoke API key"
+// @Router       /admin/api-keys/{id} [delete]
+func (ac *APIKeyController) Revoke(c *gin.Context) {
+	result, err := ac.service.RevokeKey(c, c.Param("id"))
+	if err != nil {
+		ac.handleError(c, "Failed to revoke API key", err)
+		return
+	}
+	c.JSON(http.StatusOK, toAPIKeyResponse(result))
+}
+
+// handleError maps validation and lookup failures to 400/404 and everything else to 500
+func (ac *APIKeyController) handleError(c *gin.Context, message string, err error) {
+	switch {
+	case stdErrors.Is(err, apikey.ErrInvalidKey):
+		_ = c.Error(errors.NewBadRequest(err.Error(), err))
+	case stdErrors.Is(err, gorm.ErrRecordNotFound):
+		_ = c.Error(errors.NewNotFound("API key not found", err))
+	default:
+		_ = c.Error(errors.NewInternalServerError(message, err))
+	}
+}
@@ -1,12 +1,24 @@
 package controller
 
 import (
+	"crypto/subtle"
+	stdErrors "errors"
 	"net/http"
+	"strings"
 
 	authUseCase "github.com/OmidRasouli/weather-api/internal/application/auth"
+	"github.com/OmidRasouli/weather-api/internal/domain/services"
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	// oidcLoginCookie keeps the state, nonce and PKCE verifier between the redirect to
+	// the issuer and the callback
+	oidcLoginCookie     = "oidc_login"
+	oidcLoginCookiePath = "/auth/oidc"
+	oidcLoginCookieAge  = 600
+)
+
 type AuthController struct {
 	authUseCase *authUseCase.UseCase
 }
@@ -23,16 +35,31 @@ type LoginRequestDTO struct {
 	Password string `json:"password" example:"secret"`
 }
 
+// RefreshRequestDTO is the body of POST /token/refresh and, optionally, POST /logout.
+type RefreshRequestDTO struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// TokenResponseDTO documents the token pair returned by login and refresh.
+type TokenResponseDTO struct {
+	Token            string `json:"token"`
+	TokenType        string `json:"tokenType" example:"Bearer"`
+	ExpiresAt        string `json:"expiresAt" example:"2025-01-01T12:15:00Z"`
+	RefreshToken     string `json:"refreshToken"`
+	RefreshExpiresAt string `json:"refreshExpiresAt" example:"2025-01-08T12:00:00Z"`
+}
+
 // Login godoc
 // @Summary      User login
-// @Description  Authenticate user and return a JWT access token
+// @Description  Authenticate user and return a short-lived JWT access token and a refresh token
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        request body LoginRequestDTO true "Login credentials"
-// @Success      200  {object}  map[string]string "token response"
+// @Success      200  {object}  TokenResponseDTO "token response"
 // @Failure      400  {object}  map[string]string "validation error"
 // @Failure      401  {object}  map[string]string "invalid credentials"
+// @Failure      403  {object}  map[string]string "password login is disabled"
 // @Failure      500  {object}  map[string]string "server error"
 // @Router       /login [post]
 func (ac *AuthController) Login(c *gin.Context) {
@@ -42,15 +69,178 @@ func (ac *AuthController) Login(c *gin.Context) {
 		return
 	}
 
-	response, err := ac.authUseCase.Login(req)
+	response, err := ac.authUseCase.Login(c, req)
 	if err != nil {
-		if err.Error() == "invalid credentials" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+		ac.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Refresh godoc
+// @Summary      Refresh tokens
+// @Description  Exchanges a refresh token for a new access and refresh token pair. Each refresh token can be used once; presenting it again is rejected.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body RefreshRequestDTO true "Refresh token"
+// @Success      200  {object}  TokenResponseDTO "token response"
+// @Failure      400  {object}  map[string]string "validation error"
+// @Failure      401  {object}  map[string]string "invalid, expired or revoked refresh token"
+// @Failure      503  {object}  map[string]string "token revocation unavailable"
+// @Router       /token/refresh [post]
+func (ac *AuthController) Refresh(c *gin.Context) {
+	var req authUseCase.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "refreshToken is required"})
+		return
+	}
+
+	response, err := ac.authUseCase.Refresh(c, req.RefreshToken)
+	if err != nil {
+		ac.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Logout godoc
+// @Summary      Logout
+// @Description  Revokes the access token used to call this endpoint and, when given, the refresh token of the same user
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body RefreshRequestDTO false "Refresh token to revoke"
+// @Success      200  {object}  map[string]string "Logged out"
+// @Failure      400  {object}  map[string]string "invalid refresh token"
+// @Failure      401  {object}  map[string]string "invalid or expired token"
+// @Failure      503  {object}  map[string]string "token revocation unavailable"
+// @Router       /logout [post]
+func (ac *AuthController) Logout(c *gin.Context) {
+	var req RefreshRequestDTO
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not issue token"})
+	}
+
+	claims, ok := c.MustGet("claims").(*services.Claims)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+		return
+	}
+
+	if err := ac.authUseCase.Logout(c, claims, req.RefreshToken); err != nil {
+		if stdErrors.Is(err, authUseCase.ErrInvalidToken) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid refresh token"})
+			return
+		}
+		ac.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+// JWKS godoc
+// @Summary      JSON Web Key Set
+// @Description  Public keys for verifying access tokens, selected by the kid header. Retired keys stay listed until their tokens expire. Empty when tokens are signed with a shared HS256 secret.
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object}  services.JWKS
+// @Router       /.well-known/jwks.json [get]
+func (ac *AuthController) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, ac.authUseCase.JWKS())
+}
+
+// OIDCLogin godoc
+// @Summary      Start OIDC login
+// @Description  Redirects to the configured OpenID Connect issuer for an authorization-code login with PKCE. The login state is kept in a short-lived cookie until the callback.
+// @Tags         auth
+// @Success      302
+// @Failure      404  {object}  map[string]string "OIDC login is not configured"
+// @Router       /auth/oidc/login [get]
+func (ac *AuthController) OIDCLogin(c *gin.Context) {
+	login, err := ac.authUseCase.BeginOIDCLogin()
+	if err != nil {
+		ac.handleError(c, err)
+		return
+	}
+
+	// Lax, so the cookie is sent on the top-level redirect back from the issuer
+	c.SetSameSite(http.SameSiteLaxMode)
+	value := strings.Join([]string{login.State, login.Nonce, login.Verifier}, ".")
+	c.SetCookie(oidcLoginCookie, value, oidcLoginCookieAge, oidcLoginCookiePath, "", isSecure(c), true)
+	c.Redirect(http.StatusFound, login.AuthURL)
+}
+
+// OIDCCallback godoc
+// @Summary      Complete OIDC login
+// @Description  Redirect target of the issuer. Exchanges the authorization code, creates the account on first login with the role mapped from the IdP groups, and returns our own token pair.
+// @Tags         auth
+// @Produce      json
+// @Param        code   query     string  true  "Authorization code"
+// @Param        state  query     string  true  "Login state"
+// @Success      200    {object}  TokenResponseDTO "token response"
+// @Failure      400    {object}  map[string]string "invalid or expired login state"
+// @Failure      401    {object}  map[string]string "login failed"
+// @Failure      403    {object}  map[string]string "no role is mapped to your groups"
+// @Failure      404    {object}  map[string]string "OIDC login is not configured"
+// @Failure      409    {object}  map[string]string "username is already used by another account"
+// @Router       /auth/oidc/callback [get]
+func (ac *AuthController) OIDCCallback(c *gin.Context) {
+	cookie, cookieErr := c.Cookie(oidcLoginCookie)
+	c.SetCookie(oidcLoginCookie, "", -1, oidcLoginCookiePath, "", isSecure(c), true)
+
+	if reason := c.Query("error"); reason != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "login was not completed: " + reason})
+		return
+	}
+
+	parts := strings.Split(cookie, ".")
+	if cookieErr != nil || len(parts) != 3 || subtle.ConstantTimeCompare([]byte(parts[0]), []byte(c.Query("state"))) != 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid or expired login state"})
+		return
+	}
+
+	response, err := ac.authUseCase.CompleteOIDCLogin(c, c.Query("code"), parts[1], parts[2])
+	if err != nil {
+		ac.handleError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, response)
 }
+
+// isSecure reports whether the request reached us, or the proxy in front of us, over HTTPS
+func isSecure(c *gin.Context) bool {
+	return c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+}
+
+// handleError maps authentication failures to 401, refused logins to 403/409 and revocation outages to 503
+func (ac *AuthController) handleError(c *gin.Context, err error) {
+	switch {
+	case stdErrors.Is(err, authUseCase.ErrInvalidCredentials):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+	case stdErrors.Is(err, authUseCase.ErrPasswordLoginDisabled):
+		c.JSON(http.StatusForbidden, gin.H{"error": "password login is disabled, use /auth/oidc/login"})
+	case stdErrors.Is(err, authUseCase.ErrNoRole):
+		c.JSON(http.StatusForbidden, gin.H{"error": "no role is mapped to your groups"})
+	case stdErrors.Is(err, authUseCase.ErrIdentityConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case stdErrors.Is(err, authUseCase.ErrOIDCDisabled):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case stdErrors.Is(err, authUseCase.ErrTokenRevoked):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token has been revoked"})
+	case stdErrors.Is(err, authUseCase.ErrInvalidToken):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+	case stdErrors.Is(err, authUseCase.ErrRevocationUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "token revocation unavailable"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not issue token"})
+	}
+}
@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"context"
+	stdErrors "errors"
+	"net/http"
+	"path"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// CacheStatsReporter reports the hits and misses of each tier of a cache.
+type CacheStatsReporter interface {
+	Stats() []interfaces.CacheTierStats
+}
+
+// CacheEntryStore lists and purges the entries of the weather cache by key pattern.
+type CacheEntryStore interface {
+	Entries(ctx context.Context, pattern string, cursor uint64, count int64) ([]interfaces.CacheEntry, uint64, error)
+	Purge(ctx context.Context, pattern string) (int, error)
+}
+
+type CacheController struct {
+	reporter CacheStatsReporter
+	entries  CacheEntryStore
+}
+
+// NewCacheController creates a cache controller; a nil reporter reports no tiers.
+func NewCacheController(reporter CacheStatsReporter, entries CacheEntryStore) *CacheController {
+	return &CacheController{reporter: reporter, entries: entries}
+}
+
+// CacheEntriesQuery selects one page of weather cache entries
+type CacheEntriesQuery struct {
+	Pattern string `form:"pattern"`
+	Cursor  uint64 `form:"cursor"`
+	Count   int64  `form:"count" binding:"omitempty,min=1,max=1000"`
+}
+
+// CacheEntriesResponse is one page of weather cache entries
+type CacheEntriesResponse struct {
+	Entries []interfaces.CacheEntry `json:"entries"`
+	// Cursor continues the listing; 0 when there are no more entries
+	Cursor uint64 `json:"cursor"`
+}
+
+// CachePurgeQuery selects the weather cache entries to delete
+type CachePurgeQuery struct {
+	Pattern string `form:"pattern" binding:"required"`
+}
+
+// CachePurgeResponse reports how many weather cache entries were deleted
+type CachePurgeResponse struct {
+	Deleted int `json:"deleted"`
+}
+
+// Stats godoc
+// @Summary      Cache hit and miss counters
+// @Description  Lookups answered (hits) and missed by each tier of this instance's weather cache since it started: the in-memory tier, then Redis for the lookups the memory tier missed. Empty when the memory tier is disabled. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {array}   interfaces.CacheTierStats
+// @Failure      403  {object}  map[string]string "admin privileges required"
+// @Router       /admin/cache/stats [get]
+func (cc *CacheController) Stats(c *gin.Context) {
+	result := []interfaces.CacheTierStats{}
+	if cc.reporter != nil {
+		result = cc.reporter.Stats()
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// Entries godoc
+// @Summary      List weather cache entries
+// @Description  Lists the weather cache entries whose key, after the "weather:" prefix, matches a glob pattern (e.g. "city:London:*", "id:*"), with the seconds until each expires (-1 when it does not). Keys are scanned in batches without blocking Redis: pass the returned cursor to get the next page, until it is 0. A page may be empty before the listing is done, and an entry may appear twice. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Param        pattern  query     string  false  "Glob pattern of the key after weather:" default(*)
+// @Param        cursor   query     int     false  "Cursor returned by the previous page" default(0)
+// @Param        count    query     int     false  "Number of keys to scan (1-1000)" default(100)
+// @Success      200      {object}  CacheEntriesResponse
+// @Failure      400      {object}  errors.AppError "Invalid query parameters"
+// @Failure      403      {object}  map[string]string "admin privileges required"
+// @Failure      500      {object}  errors.AppError "Failed to list cache entries"
+// @Router       /admin/cache/entries [get]
+func (cc *CacheController) Entries(c *gin.Context) {
+	var req CacheEntriesQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(errors.NewBadRequest("Invalid query parameters", err))
+		return
+	}
+	if req.Pattern == "" {
+		req.Pattern = "*"
+	}
+
+	entries, next, err := cc.entries.Entries(c, req.Pattern, req.Cursor, req.Count)
+	if err != nil {
+		cc.handleError(c, "Failed to list cache entries", err)
+		return
+	}
+	c.JSON(http.StatusOK, CacheEntriesResponse{Entries: entries, Cursor: next})
+}
+
+// Purge godoc
+// @Summary      Purge weather cache entries
+// @Description  Deletes the weather cache entries whose key, after the "weather:" prefix, matches a glob pattern, from Redis and from the memory of every instance. The pattern is required; "*" purges the whole weather cache. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Param        pattern  query     string  true  "Glob pattern of the key after weather:"
+// @Success      200      {object}  CachePurgeResponse
+// @Failure      400      {object}  errors.AppError "Invalid query parameters"
+// @Failure      403      {object}  map[string]string "admin privileges required"
+// @Failure      500      {object}  errors.AppError "Failed to purge cache entries"
+// @Router       /admin/cache/entries [delete]
+func (cc *CacheController) Purge(c *gin.Context) {
+	var req CachePurgeQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(errors.NewBadRequest("Invalid query parameters", err))
+		return
+	}
+
+	deleted, err := cc.entries.Purge(c, req.Pattern)
+	if err != nil {
+		cc.handleError(c, "Failed to purge cache entries", err)
+		return
+	}
+	c.JSON(http.StatusOK, CachePurgeResponse{Deleted: deleted})
+}
+
+// handleError maps malformed patterns to 400 and everything else to 500
+func (cc *CacheController) handleError(c *gin.Context, message string, err error) {
+	if stdErrors.Is(err, path.ErrBadPattern) {
+		_ = c.Error(errors.NewBadRequest("Invalid key pattern", err))
+		return
+	}
+	_ = c.Error(errors.NewInternalServerError(message, err))
+}
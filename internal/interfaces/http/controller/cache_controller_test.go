@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCacheEntryStore is a mock implementation of the CacheEntryStore interface
+type MockCacheEntryStore struct {
+	mock.Mock
+}
+
+func (m *MockCacheEntryStore) Entries(ctx context.Context, pattern string, cursor uint64, count int64) ([]interfaces.CacheEntry, uint64, error) {
+	args := m.Called(ctx, pattern, cursor, count)
+	return args.Get(0).([]interfaces.CacheEntry), args.Get(1).(uint64), args.Error(2)
+}
+
+func (m *MockCacheEntryStore) Purge(ctx context.Context, pattern string) (int, error) {
+	args := m.Called(ctx, pattern)
+	return args.Int(0), args.Error(1)
+}
+
+func TestCacheEntries_ListsPage(t *testing.T) {
+	store := new(MockCacheEntryStore)
+	sut := NewCacheController(nil, store)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/admin/cache/entries?pattern=city:London:*&cursor=17&count=50", nil)
+
+	entries := []interfaces.CacheEntry{{Key: "weather:city:London:UK", TTLSeconds: 420}}
+	store.On("Entries", mock.Anything, "city:London:*", uint64(17), int64(50)).Return(entries, uint64(0), nil)
+
+	sut.Entries(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body CacheEntriesResponse
+	assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, entries, body.Entries)
+	assert.Equal(t, uint64(0), body.Cursor)
+}
+
+func TestCacheEntries_DefaultsToEveryEntry(t *testing.T) {
+	store := new(MockCacheEntryStore)
+	sut := NewCacheController(nil, store)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/admin/cache/entries", nil)
+
+	store.On("Entries", mock.Anything, "*", uint64(0), int64(0)).Return([]interfaces.CacheEntry{}, uint64(0), nil)
+
+	sut.Entries(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	store.AssertExpectations(t)
+}
+
+func TestCacheEntries_BadPattern(t *testing.T) {
+	store := new(MockCacheEntryStore)
+	sut := NewCacheController(nil, store)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/admin/cache/entries?pattern=city:[", nil)
+
+	store.On("Entries", mock.Anything, "city:[", uint64(0), int64(0)).Return([]interfaces.CacheEntry(nil), uint64(0), fmt.Errorf("scan: %w", path.ErrBadPattern))
+
+	sut.Entries(c)
+
+	assert.Equal(t, 1, len(c.Errors))
+	appErr, ok := c.Errors.Last().Err.(*errors.AppError)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, http.StatusBadRequest, appErr.Code)
+}
+
+func TestCachePurge(t *testing.T) {
+	store := new(MockCacheEntryStore)
+	sut := NewCacheController(nil, store)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("DELETE", "/admin/cache/entries?pattern=city:*", nil)
+
+	store.On("Purge", mock.Anything, "city:*").Return(3, nil)
+
+	sut.Purge(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body CachePurgeResponse
+	assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 3, body.Deleted)
+}
+
+func TestCachePurge_RequiresPattern(t *testing.T) {
+	store := new(MockCacheEntryStore)
+	sut := NewCacheController(nil, store)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("DELETE", "/admin/cache/entries", nil)
+
+	sut.Purge(c)
+
+	assert.Equal(t, 1, len(c.Errors))
+	appErr, ok := c.Errors.Last().Err.(*errors.AppError)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, http.StatusBadRequest, appErr.Code)
+	store.AssertNotCalled(t, "Purge", mock.Anything, mock.Anything)
+}
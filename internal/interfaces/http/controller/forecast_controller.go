@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/forecast"
+	"github.com/OmidRasouli/weather-api/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// ForecastService defines the interface for forecast service operations.
+type ForecastService interface {
+	GetForecast(ctx context.Context, city, country string) (*forecast.Forecast, error)
+}
+
+type ForecastController struct {
+	service ForecastService
+}
+
+// NewForecastController creates a new forecast controller with the provided service.
+func NewForecastController(service ForecastService) *ForecastController {
+	return &ForecastController{service: service}
+}
+
+// ForecastQuery holds the optional parameters accepted by GET /forecast/{city}.
+type ForecastQuery struct {
+	Country string `form:"country" binding:"omitempty,min=2,max=3,alpha"`
+}
+
+// GetByCity godoc
+// @Summary      Get forecast by city
+// @Description  Returns the 5-day/3-hour forecast for a city, served from cache when available
+// @Tags         forecast
+// @Produce      json
+// @Param        city     path      string  true   "City name"
+// @Param        country  query     string  false  "Country code"
+// @Param        units    query     string  false  "Measurement system (defaults to the Accept-Units header, then metric)" Enums(metric, imperial, standard)
+// @Param        Accept-Units  header  string  false  "Default measurement system for this client" Enums(metric, imperial, standard)
+// @Success      200  {object}  forecast.Forecast
+// @Failure      400  {object}  errors.AppError "Invalid request data"
+// @Failure      404  {object}  errors.AppError "City not found"
+// @Failure      429  {object}  errors.AppError "Weather provider quota or rate limit exhausted"
+// @Failure      502  {object}  errors.AppError "Failed to fetch forecast"
+// @Failure      503  {object}  errors.AppError "Weather provider unavailable"
+// @Router       /forecast/{city} [get]
+func (fc *ForecastController) GetByCity(c *gin.Context) {
+	city := c.Param("city")
+	if city == "" {
+		_ = c.Error(errors.NewBadRequest("City name is required", nil))
+		return
+	}
+
+	var req ForecastQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(errors.NewBadRequest("Invalid query parameters", err))
+		return
+	}
+
+	units, ok := resolveUnits(c)
+	if !ok {
+		return
+	}
+
+	result, err := fc.service.GetForecast(c, city, req.Country)
+	if err != nil {
+		upstreamFailure(c, err, "Failed to fetch forecast", http.StatusBadGateway)
+		return
+	}
+
+	c.JSON(http.StatusOK, result.InUnits(units))
+}
@@ -8,6 +8,11 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// degradable is implemented by caches that keep serving from memory while their backend is down
+type degradable interface {
+	Degraded() bool
+}
+
 // HealthController handles health check endpoints
 type HealthController struct {
 	db    interfaces.Database
@@ -48,7 +53,7 @@ func (hc *HealthController) BasicHealth(c *gin.Context) {
 
 // ReadinessCheck godoc
 // @Summary      Readiness check
-// @Description  Verifies connections to PostgreSQL and Redis
+// @Description  Verifies connections to PostgreSQL and Redis. While Redis is down and the cache is served from memory, the instance stays ready with status DEGRADED.
 // @Tags         health
 // @Produce      json
 // @Success      200  {object}  HealthResponse
@@ -80,9 +85,17 @@ func (hc *HealthController) ReadinessCheck(c *gin.Context) {
 		statusCode = http.StatusServiceUnavailable
 	} else if err := hc.redis.HealthCheck(ctx); err != nil {
 		logger.Errorf("Redis health check failed: %v", err)
-		components["redis"] = "DOWN"
-		status = "DOWN"
-		statusCode = http.StatusServiceUnavailable
+		if _, ok := hc.redis.(degradable); ok {
+			// Requests are still answered, from the in-memory cache
+			components["redis"] = "DEGRADED"
+			if status == "UP" {
+				status = "DEGRADED"
+			}
+		} else {
+			components["redis"] = "DOWN"
+			status = "DOWN"
+			statusCode = http.StatusServiceUnavailable
+		}
 	}
 
 	response := HealthResponse{
@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+)
+
+type fakeDatabase struct {
+	interfaces.Database
+}
+
+func (fakeDatabase) Ping(ctx context.Context) error {
+	return nil
+}
+
+// downCache fails its health check
+type downCache struct {
+	interfaces.Cache
+}
+
+func (downCache) HealthCheck(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+// degradedCache fails its health check but keeps serving from memory
+type degradedCache struct {
+	downCache
+}
+
+func (degradedCache) Degraded() bool {
+	return true
+}
+
+func readiness(cache interfaces.Cache) (int, HealthResponse) {
+	gin.SetMode(gin.TestMode)
+	hc := NewHealthController(fakeDatabase{}, cache)
+	r := gin.New()
+	r.GET("/health/ready", hc.ReadinessCheck)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/health/ready", nil)
+	r.ServeHTTP(w, req)
+
+	var resp HealthResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	return w.Code, resp
+}
+
+func TestReadinessCheck_RedisDown(t *testing.T) {
+	code, resp := readiness(downCache{})
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "DOWN", resp.Status)
+	assert.Equal(t, "DOWN", resp.Components["redis"])
+}
+
+func TestReadinessCheck_CacheDegraded(t *testing.T) {
+	code, resp := readiness(degradedCache{})
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "DEGRADED", resp.Status)
+	assert.Equal(t, "DEGRADED", resp.Components["redis"])
+	assert.Equal(t, "UP", resp.Components["database"])
+}
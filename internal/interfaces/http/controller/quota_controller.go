@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"context"
+	stdErrors "errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// QuotaReporter reports the call budget usage of one weather provider.
+type QuotaReporter interface {
+	Usage(ctx context.Context) (interfaces.QuotaUsage, error)
+}
+
+type QuotaController struct {
+	reporters []QuotaReporter
+}
+
+func NewQuotaController(reporters ...QuotaReporter) *QuotaController {
+	return &QuotaController{reporters: reporters}
+}
+
+// Usage godoc
+// @Summary      Provider quota usage
+// @Description  Calls made to each rate-capped weather provider in the current minute and UTC day, against the configured budgets. A limit of 0 is unlimited. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {array}   interfaces.QuotaUsage
+// @Failure      403  {object}  map[string]string "admin privileges required"
+// @Failure      500  {object}  errors.AppError "Failed to read quota usage"
+// @Router       /admin/quota [get]
+func (qc *QuotaController) Usage(c *gin.Context) {
+	result := make([]interfaces.QuotaUsage, 0, len(qc.reporters))
+	for _, r := range qc.reporters {
+		usage, err := r.Usage(c)
+		if err != nil {
+			_ = c.Error(errors.NewInternalServerError("Failed to read quota usage", err))
+			return
+		}
+		result = append(result, usage)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// quotaExceeded records a 429 with Retry-After and returns true when err is caused by an
+// exhausted provider quota
+func quotaExceeded(c *gin.Context, err error) bool {
+	var quotaErr *interfaces.QuotaExceededError
+	if !stdErrors.As(err, &quotaErr) {
+		return false
+	}
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(quotaErr.RetryAfter.Seconds()))))
+	_ = c.Error(errors.NewExternalAPIError("Weather provider quota exhausted, try again later", err, http.StatusTooManyRequests))
+	return true
+}
@@ -0,0 +1,227 @@
+package controller
+
+import (
+	"context"
+	stdErrors "errors"
+	"net/http"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/tracking"
+	"github.com/OmidRasouli/weather-api/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// TrackingService defines the operations for managing tracked locations.
+type TrackingService interface {
+	CreateLocation(ctx context.Context, l *tracking.Location) (*tracking.Location, error)
+	ListLocations(ctx context.Context) ([]*tracking.Location, error)
+	GetLocation(ctx context.Context, id string) (*tracking.Location, error)
+	UpdateLocation(ctx context.Context, id string, update tracking.Update) (*tracking.Location, error)
+	DeleteLocation(ctx context.Context, id string) error
+}
+
+type TrackingController struct {
+	service TrackingService
+}
+
+func NewTrackingController(service TrackingService) *TrackingController {
+	return &TrackingController{service: service}
+}
+
+// CreateTrackedLocationRequest starts background refreshes for a city.
+type CreateTrackedLocationRequest struct {
+	City            string `json:"city" binding:"required,min=1"`
+	Country         string `json:"country" binding:"required,min=2,max=3,alpha"`
+	RefreshInterval string `json:"refreshInterval" example:"15m"`
+	Enabled         *bool  `json:"enabled"`
+}
+
+// UpdateTrackedLocationRequest changes the schedule of a tracked location; omitted fields are unchanged.
+type UpdateTrackedLocationRequest struct {
+	RefreshInterval *string `json:"refreshInterval" example:"1h"`
+	Enabled         *bool   `json:"enabled"`
+}
+
+// TrackedLocationResponse is the API representation of a tracked location.
+type TrackedLocationResponse struct {
+	ID              string     `json:"id"`
+	City            string     `json:"city"`
+	Country         string     `json:"country"`
+	RefreshInterval string     `json:"refreshInterval" example:"15m0s"`
+	Enabled         bool       `json:"enabled"`
+	NextRunAt       time.Time  `json:"nextRunAt"`
+	LastRefreshedAt *time.Time `json:"lastRefreshedAt,omitempty"`
+	LastError       string     `json:"lastError,omitempty"`
+}
+
+func toTrackedLocationResponse(l *tracking.Location) TrackedLocationResponse {
+	return TrackedLocationResponse{
+		ID:              l.ID.String(),
+		City:            l.City,
+		Country:         l.Country,
+		RefreshInterval: l.RefreshInterval.String(),
+		Enabled:         l.Enabled,
+		NextRunAt:       l.NextRunAt,
+		LastRefreshedAt: l.LastRefreshedAt,
+		LastError:       l.LastError,
+	}
+}
+
+// Create godoc
+// @Summary      Track a location
+// @Description  Starts refreshing the weather of a city in the background at the given interval (default 30m, between 1m and 168h)
+// @Tags         tracking
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateTrackedLocationRequest  true  "Location and schedule"
+// @Success      201      {object}  TrackedLocationResponse
+// @Failure      400      {object}  errors.AppError "Invalid request data"
+// @Failure      500      {object}  errors.AppError "Failed to create tracked location"
+// @Router       /tracked-locations [post]
+func (tc *TrackingController) Create(c *gin.Context) {
+	var req CreateTrackedLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if ok {
+			details := make(map[string]string)
+			for _, e := range validationErrors {
+				details[e.Field()] = e.Error()
+			}
+			_ = c.Error(errors.ValidationError("Invalid request data", details))
+			return
+		}
+		_ = c.Error(errors.NewBadRequest("Invalid request body", err))
+		return
+	}
+
+	location := &tracking.Location{
+		City:    req.City,
+		Country: req.Country,
+		Enabled: req.Enabled == nil || *req.Enabled,
+	}
+	if req.RefreshInterval != "" {
+		interval, err := time.ParseDuration(req.RefreshInterval)
+		if err != nil {
+			_ = c.Error(errors.NewBadRequest("Invalid refresh interval", err))
+			return
+		}
+		location.RefreshInterval = interval
+	}
+
+	result, err := tc.service.CreateLocation(c, location)
+	if err != nil {
+		tc.handleError(c, "Failed to create tracked location", err)
+		return
+	}
+	c.JSON(http.StatusCreated, toTrackedLocationResponse(result))
+}
+
+// List godoc
+// @Summary      List tracked locations
+// @Description  Lists every tracked location with its schedule and the outcome of its last refresh
+// @Tags         tracking
+// @Produce      json
+// @Success      200  {array}   TrackedLocationResponse
+// @Failure      500  {object}  errors.AppError "Failed to list tracked locations"
+// @Router       /tracked-locations [get]
+func (tc *TrackingController) List(c *gin.Context) {
+	locations, err := tc.service.ListLocations(c)
+	if err != nil {
+		_ = c.Error(errors.NewInternalServerError("Failed to list tracked locations", err))
+		return
+	}
+
+	result := make([]TrackedLocationResponse, 0, len(locations))
+	for _, l := range locations {
+		result = append(result, toTrackedLocationResponse(l))
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetByID godoc
+// @Summary      Get tracked location
+// @Description  Retrieves a tracked location by its ID
+// @Tags         tracking
+// @Produce      json
+// @Param        id   path      string  true  "Tracked location ID"
+// @Success      200  {object}  TrackedLocationResponse
+// @Failure      404  {object}  errors.AppError "Tracked location not found"
+// @Router       /tracked-locations/{id} [get]
+func (tc *TrackingController) GetByID(c *gin.Context) {
+	result, err := tc.service.GetLocation(c, c.Param("id"))
+	if err != nil {
+		_ = c.Error(errors.NewNotFound("Tracked location not found", err))
+		return
+	}
+	c.JSON(http.StatusOK, toTrackedLocationResponse(result))
+}
+
+// Update godoc
+// @Summary      Update tracked location
+// @Description  Changes the refresh interval or pauses/resumes a tracked location
+// @Tags         tracking
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                        true  "Tracked location ID"
+// @Param        request  body      UpdateTrackedLocationRequest  true  "Schedule changes"
+// @Success      200      {object}  TrackedLocationResponse
+// @Failure      400      {object}  errors.AppError "Invalid request data"
+// @Failure      404      {object}  errors.AppError "Tracked location not found"
+// @Failure      500      {object}  errors.AppError "Failed to update tracked location"
+// @Router       /tracked-locations/{id} [put]
+func (tc *TrackingController) Update(c *gin.Context) {
+	var req UpdateTrackedLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(errors.NewBadRequest("Invalid request body", err))
+		return
+	}
+
+	update := tracking.Update{Enabled: req.Enabled}
+	if req.RefreshInterval != nil {
+		interval, err := time.ParseDuration(*req.RefreshInterval)
+		if err != nil {
+			_ = c.Error(errors.NewBadRequest("Invalid refresh interval", err))
+			return
+		}
+		update.RefreshInterval = &interval
+	}
+
+	result, err := tc.service.UpdateLocation(c, c.Param("id"), update)
+	if err != nil {
+		tc.handleError(c, "Failed to update tracked location", err)
+		return
+	}
+	c.JSON(http.StatusOK, toTrackedLocationResponse(result))
+}
+
+// Delete godoc
+// @Summary      Stop tracking a location
+// @Description  Deletes a tracked location; stored weather records are kept
+// @Tags         tracking
+// @Produce      json
+// @Param        id   path      string  true  "Tracked location ID"
+// @Success      200  {object}  map[string]string "Tracked location deleted"
+// @Failure      404  {object}  errors.AppError "Tracked location not found"
+// @Failure      500  {object}  errors.AppError "Failed to delete tracked location"
+// @Router       /tracked-locations/{id} [delete]
+func (tc *TrackingController) Delete(c *gin.Context) {
+	if err := tc.service.DeleteLocation(c, c.Param("id")); err != nil {
+		tc.handleError(c, "Failed to delete tracked location", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Tracked location deleted"})
+}
+
+// handleError maps validation and lookup failures to 400/404 and everything else to 500
+func (tc *TrackingController) handleError(c *gin.Context, message string, err error) {
+	switch {
+	case stdErrors.Is(err, tracking.ErrInvalidLocation):
+		_ = c.Error(errors.NewBadRequest(err.Error(), err))
+	case stdErrors.Is(err, gorm.ErrRecordNotFound):
+		_ = c.Error(errors.NewNotFound("Tracked location not found", err))
+	default:
+		_ = c.Error(errors.NewInternalServerError(message, err))
+	}
+}
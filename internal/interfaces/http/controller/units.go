@@ -0,0 +1,32 @@
+package controller
+
+import (
+	"github.com/OmidRasouli/weather-api/internal/domain/weather"
+	"github.com/OmidRasouli/weather-api/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// AcceptUnitsHeader lets an API client pick its default measurement system for read endpoints.
+	AcceptUnitsHeader = "Accept-Units"
+	// ContentUnitsHeader reports the measurement system used in a response.
+	ContentUnitsHeader = "Content-Units"
+)
+
+// resolveUnits picks the response units from the "units" query parameter, falling back to the
+// Accept-Units header and then metric. On an invalid value it records a 400 error and returns false.
+func resolveUnits(c *gin.Context) (weather.Units, bool) {
+	raw := c.Query("units")
+	if raw == "" && c.Request != nil {
+		raw = c.GetHeader(AcceptUnitsHeader)
+	}
+
+	units, err := weather.ParseUnits(raw)
+	if err != nil {
+		_ = c.Error(errors.ValidationError("Invalid units", map[string]string{"units": err.Error()}))
+		return "", false
+	}
+
+	c.Header(ContentUnitsHeader, string(units))
+	return units, true
+}
@@ -0,0 +1,33 @@
+package controller
+
+import (
+	stdErrors "errors"
+	"net/http"
+
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/pkg/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// upstreamFailure records the error of a failed weather provider call by its kind: 429 when a
+// quota or the provider's rate limit is used up, 404 for an unknown city, 503 while the
+// providers are unavailable and 502 when they reject our credentials. Other errors are
+// recorded with message and status.
+func upstreamFailure(c *gin.Context, err error, message string, status int) {
+	if quotaExceeded(c, err) {
+		return
+	}
+
+	switch {
+	case stdErrors.Is(err, interfaces.ErrCityNotFound):
+		_ = c.Error(errors.NewNotFound("City not found", err))
+	case stdErrors.Is(err, interfaces.ErrUpstreamRateLimited):
+		_ = c.Error(errors.NewExternalAPIError("Weather provider rate limit exceeded, try again later", err, http.StatusTooManyRequests))
+	case stdErrors.Is(err, interfaces.ErrUpstreamUnavailable):
+		_ = c.Error(errors.NewExternalAPIError("Weather provider unavailable, try again later", err, http.StatusServiceUnavailable))
+	case stdErrors.Is(err, interfaces.ErrUpstreamUnauthorized):
+		_ = c.Error(errors.NewExternalAPIError("Weather provider rejected the API credentials", err, http.StatusBadGateway))
+	default:
+		_ = c.Error(errors.NewExternalAPIError(message, err, status))
+	}
+}
@@ -0,0 +1,270 @@
+package controller
+
+import (
+	"context"
+	stdErrors "errors"
+	"net/http"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/user"
+	"github.com/OmidRasouli/weather-api/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// UserService defines the account administration operations.
+type UserService interface {
+	CreateUser(ctx context.Context, username string, password string, role user.Role) (*user.User, error)
+	ListUsers(ctx context.Context) ([]*user.User, error)
+	SetDisabled(ctx context.Context, id string, disabled bool) (*user.User, error)
+	SetRole(ctx context.Context, id string, role user.Role) (*user.User, error)
+	ResetPassword(ctx context.Context, id string, password string) (*user.User, string, error)
+}
+
+type UserController struct {
+	service UserService
+}
+
+func NewUserController(service UserService) *UserController {
+	return &UserController{service: service}
+}
+
+// CreateUserRequest is the body of POST /admin/users. The role defaults to viewer.
+type CreateUserRequest struct {
+	Username string `json:"username" binding:"required" example:"alice"`
+	Password string `json:"password" binding:"required" example:"correct-horse-battery"`
+	Role     string `json:"role" binding:"omitempty,oneof=viewer editor admin" example:"editor"`
+}
+
+// SetRoleRequest is the body of PUT /admin/users/{id}/role.
+type SetRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=viewer editor admin" example:"editor"`
+}
+
+// ResetPasswordRequest is the body of POST /admin/users/{id}/reset-password.
+// An empty password makes the server generate one.
+type ResetPasswordRequest struct {
+	Password string `json:"password"`
+}
+
+// UserResponse is the API representation of an account. Password hashes are never exposed;
+// IdentityIssuer is set on accounts created by an OIDC login.
+type UserResponse struct {
+	ID             string     `json:"id"`
+	Username       string     `json:"username"`
+	Role           string     `json:"role"`
+	Disabled       bool       `json:"disabled"`
+	IdentityIssuer string     `json:"identityIssuer,omitempty"`
+	LastLoginAt    *time.Time `json:"lastLoginAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+// ResetPasswordResponse carries the new password when it was generated by the server.
+type ResetPasswordResponse struct {
+	User     UserResponse `json:"user"`
+	Password string       `json:"password,omitempty"`
+}
+
+func toUserResponse(u *user.User) UserResponse {
+	return UserResponse{
+		ID:             u.ID.String(),
+		Username:       u.Username,
+		Role:           string(u.Role),
+		Disabled:       u.Disabled,
+		IdentityIssuer: u.IdentityIssuer,
+		LastLoginAt:    u.LastLoginAt,
+		CreatedAt:      u.CreatedAt,
+		UpdatedAt:      u.UpdatedAt,
+	}
+}
+
+// Create godoc
+// @Summary      Create user
+// @Description  Creates an account that can log in. Passwords must be 8-72 bytes and are stored as bcrypt hashes. Admin only.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateUserRequest  true  "New account"
+// @Success      201      {object}  UserResponse
+// @Failure      400      {object}  errors.AppError "Invalid request data"
+// @Failure      403      {object}  map[string]string "admin privileges required"
+// @Failure      409      {object}  errors.AppError "Username already taken"
+// @Failure      500      {object}  errors.AppError "Failed to create user"
+// @Router       /admin/users [post]
+func (uc *UserController) Create(c *gin.Context) {
+	var req CreateUserRequest
+	if !uc.bind(c, &req) {
+		return
+	}
+
+	role := user.RoleViewer
+	if req.Role != "" {
+		role = user.Role(req.Role)
+	}
+	result, err := uc.service.CreateUser(c, req.Username, req.Password, role)
+	if err != nil {
+		uc.handleError(c, "Failed to create user", err)
+		return
+	}
+	c.JSON(http.StatusCreated, toUserResponse(result))
+}
+
+// List godoc
+// @Summary      List users
+// @Description  Lists every account. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {array}   UserResponse
+// @Failure      403  {object}  map[string]string "admin privileges required"
+// @Failure      500  {object}  errors.AppError "Failed to list users"
+// @Router       /admin/users [get]
+func (uc *UserController) List(c *gin.Context) {
+	users, err := uc.service.ListUsers(c)
+	if err != nil {
+		_ = c.Error(errors.NewInternalServerError("Failed to list users", err))
+		return
+	}
+
+	result := make([]UserResponse, 0, len(users))
+	for _, u := range users {
+		result = append(result, toUserResponse(u))
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// Disable godoc
+// @Summary      Disable user
+// @Description  Prevents an account from logging in. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  UserResponse
+// @Failure      403  {object}  map[string]string "admin privileges required"
+// @Failure      404  {object}  errors.AppError "User not found"
+// @Failure      500  {object}  errors.AppError "Failed to disable user"
+// @Router       /admin/users/{id}/disable [post]
+func (uc *UserController) Disable(c *gin.Context) {
+	uc.setDisabled(c, true, "Failed to disable user")
+}
+
+// Enable godoc
+// @Summary      Enable user
+// @Description  Re-enables a disabled account. Admin only.
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  UserResponse
+// @Failure      403  {object}  map[string]string "admin privileges required"
+// @Failure      404  {object}  errors.AppError "User not found"
+// @Failure      500  {object}  errors.AppError "Failed to enable user"
+// @Router       /admin/users/{id}/enable [post]
+func (uc *UserController) Enable(c *gin.Context) {
+	uc.setDisabled(c, false, "Failed to enable user")
+}
+
+func (uc *UserController) setDisabled(c *gin.Context, disabled bool, message string) {
+	result, err := uc.service.SetDisabled(c, c.Param("id"), disabled)
+	if err != nil {
+		uc.handleError(c, message, err)
+		return
+	}
+	c.JSON(http.StatusOK, toUserResponse(result))
+}
+
+// SetRole godoc
+// @Summary      Change user role
+// @Description  Sets the role of an account: viewer (read), editor (read, fetch and edit) or admin (everything including deletes and account management). Tokens issued earlier keep their old role until they expire. Admin only.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string          true  "User ID"
+// @Param        request  body      SetRoleRequest  true  "New role"
+// @Success      200      {object}  UserResponse
+// @Failure      400      {object}  errors.AppError "Invalid request data"
+// @Failure      403      {object}  map[string]string "admin privileges required"
+// @Failure      404      {object}  errors.AppError "User not found"
+// @Failure      500      {object}  errors.AppError "Failed to change role"
+// @Router       /admin/users/{id}/role [put]
+func (uc *UserController) SetRole(c *gin.Context) {
+	var req SetRoleRequest
+	if !uc.bind(c, &req) {
+		return
+	}
+
+	result, err := uc.service.SetRole(c, c.Param("id"), user.Role(req.Role))
+	if err != nil {
+		uc.handleError(c, "Failed to change role", err)
+		return
+	}
+	c.JSON(http.StatusOK, toUserResponse(result))
+}
+
+// ResetPassword godoc
+// @Summary      Reset user password
+// @Description  Sets a new password. When the body is empty or has no password, a random one is generated and returned once. Admin only.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                true   "User ID"
+// @Param        request  body      ResetPasswordRequest  false  "New password"
+// @Success      200      {object}  ResetPasswordResponse
+// @Failure      400      {object}  errors.AppError "Invalid request data"
+// @Failure      403      {object}  map[string]string "admin privileges required"
+// @Failure      404      {object}  errors.AppError "User not found"
+// @Failure      500      {object}  errors.AppError "Failed to reset password"
+// @Router       /admin/users/{id}/reset-password [post]
+func (uc *UserController) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			_ = c.Error(errors.NewBadRequest("Invalid request body", err))
+			return
+		}
+	}
+
+	result, password, err := uc.service.ResetPassword(c, c.Param("id"), req.Password)
+	if err != nil {
+		uc.handleError(c, "Failed to reset password", err)
+		return
+	}
+
+	response := ResetPasswordResponse{User: toUserResponse(result)}
+	if req.Password == "" {
+		response.Password = password
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// bind decodes and validates a JSON body, recording a 400 on failure
+func (uc *UserController) bind(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if ok {
+			details := make(map[string]string)
+			for _, e := range validationErrors {
+				details[e.Field()] = e.Error()
+			}
+			_ = c.Error(errors.ValidationError("Invalid request data", details))
+			return false
+		}
+		_ = c.Error(errors.NewBadRequest("Invalid request body", err))
+		return false
+	}
+	return true
+}
+
+// handleError maps validation, conflict and lookup failures to 400/409/404 and everything else to 500
+func (uc *UserController) handleError(c *gin.Context, message string, err error) {
+	switch {
+	case stdErrors.Is(err, user.ErrInvalidUser):
+		_ = c.Error(errors.NewBadRequest(err.Error(), err))
+	case stdErrors.Is(err, user.ErrUsernameTaken):
+		_ = c.Error(errors.NewConflict("Username already taken", err))
+	case stdErrors.Is(err, gorm.ErrRecordNotFound):
+		_ = c.Error(errors.NewNotFound("User not found", err))
+	default:
+		_ = c.Error(errors.NewInternalServerError(message, err))
+	}
+}
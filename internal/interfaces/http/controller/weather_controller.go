@@ -3,6 +3,9 @@ package controller
 import (
 	"context"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/OmidRasouli/weather-api/internal/domain/weather"
 	"github.com/OmidRasouli/weather-api/pkg/errors"
@@ -13,9 +16,12 @@ import (
 // WeatherService defines the interface for weather service operations.
 // This interface decouples the controller from the concrete service implementation.
 type WeatherService interface {
-	FetchAndStoreWeather(ctx context.Context, city, country string) (*weather.Weather, error)
+	FetchAndStoreWeather(ctx context.Context, city, country string) (*weather.Observation, error)
+	FetchAndStoreWeatherByCoordinates(ctx context.Context, lat, lon float64) (*weather.Observation, error)
+	FindNearbyWeather(ctx context.Context, q weather.NearbyQuery) ([]*weather.NearbyResult, error)
 	GetLatestWeatherByCity(ctx context.Context, city string) (*weather.Weather, error)
-	GetAllWeather(ctx context.Context) ([]*weather.Weather, error)
+	ListWeather(ctx context.Context, q weather.Query) (*weather.Page, error)
+	GetWeatherHistory(ctx context.Context, q weather.HistoryQuery) ([]*weather.HistoryBucket, error)
 	GetWeatherByID(ctx context.Context, id string) (*weather.Weather, error)
 	UpdateWeather(ctx context.Context, id string, update *weather.Weather) (*weather.Weather, error)
 	DeleteWeather(ctx context.Context, id string) error
@@ -31,9 +37,12 @@ func NewWeatherController(service WeatherService) *WeatherController {
 	return &WeatherController{service: service}
 }
 
+// FetchWeatherRequest identifies a location either by city and country or by lat/lon.
 type FetchWeatherRequest struct {
-	City    string `json:"city" binding:"required,min=1"`
-	Country string `json:"country" binding:"required,min=2,max=3,alpha"`
+	City    string   `json:"city" binding:"required_without=Lat,omitempty,min=1"`
+	Country string   `json:"country" binding:"required_with=City,omitempty,min=2,max=3,alpha"`
+	Lat     *float64 `json:"lat" binding:"required_with=Lon,omitempty,gte=-90,lte=90" example:"35.6892"`
+	Lon     *float64 `json:"lon" binding:"required_with=Lat,omitempty,gte=-180,lte=180" example:"51.389"`
 }
 
 type UpdateWeatherRequest struct {
@@ -45,16 +54,77 @@ type UpdateWeatherRequest struct {
 	Description string  `json:"description"`
 }
 
+// ListWeatherQuery holds the filter, sort and pagination parameters accepted by GET /weather.
+type ListWeatherQuery struct {
+	City    string    `form:"city"`
+	Country string    `form:"country" binding:"omitempty,min=2,max=3,alpha"`
+	From    time.Time `form:"from"`
+	To      time.Time `form:"to"`
+	Sort    string    `form:"sort"`
+	Order   string    `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit   int       `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset  int       `form:"offset" binding:"omitempty,min=0"`
+}
+
+// PageLinks contains navigation links for a paginated response.
+type PageLinks struct {
+	Self string `json:"self"`
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// WeatherListResponse is the envelope returned by GET /weather.
+type WeatherListResponse struct {
+	Data   []*weather.Weather `json:"data"`
+	Total  int64              `json:"total"`
+	Limit  int                `json:"limit"`
+	Offset int                `json:"offset"`
+	Links  PageLinks          `json:"links"`
+}
+
+// WeatherHistoryQuery holds the parameters accepted by GET /weather/history/{city}.
+type WeatherHistoryQuery struct {
+	Country string    `form:"country" binding:"omitempty,min=2,max=3,alpha"`
+	From    time.Time `form:"from"`
+	To      time.Time `form:"to"`
+	Bucket  string    `form:"bucket"`
+}
+
+// WeatherHistoryResponse is the body returned by GET /weather/history/{city}.
+type WeatherHistoryResponse struct {
+	City    string                   `json:"city"`
+	Country string                   `json:"country,omitempty"`
+	From    time.Time                `json:"from"`
+	To      time.Time                `json:"to"`
+	Bucket  string                   `json:"bucket"`
+	Buckets []*weather.HistoryBucket `json:"buckets"`
+}
+
+// NearbyWeatherQuery holds the parameters accepted by GET /weather/nearby.
+type NearbyWeatherQuery struct {
+	Lat      *float64 `form:"lat" binding:"required,gte=-90,lte=90"`
+	Lon      *float64 `form:"lon" binding:"required,gte=-180,lte=180"`
+	RadiusKm float64  `form:"radius_km" binding:"omitempty,gt=0,lte=500"`
+	Limit    int      `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
 // FetchAndStore godoc
 // @Summary      Fetch and store weather data
-// @Description  Fetches weather data from external API for a city and country, and stores it in the database
+// @Description  Fetches weather data from external API for a city and country, or for lat/lon coordinates, and stores it in the database.
+// @Description  Cached observations are returned with their age in an Age header. Past the soft TTL, or while the API is unavailable, the observation is returned with a Warning header.
 // @Tags         weather
 // @Accept       json
 // @Produce      json
-// @Param        request body FetchWeatherRequest true "City and country information"
+// @Param        request body FetchWeatherRequest true "City and country, or lat and lon"
 // @Success      200  {object}  weather.Weather
+// @Header       200  {integer} Age "Seconds since the observation was fetched, when it was not fetched for this request"
+// @Header       200  {string}  Warning "110 - \"Response is Stale\" when the observation is past its freshness"
 // @Failure      400  {object}  errors.AppError "Invalid request data"
+// @Failure      404  {object}  errors.AppError "City not found"
+// @Failure      429  {object}  errors.AppError "Weather provider quota or rate limit exhausted"
 // @Failure      500  {object}  errors.AppError "Failed to fetch weather data"
+// @Failure      502  {object}  errors.AppError "Weather provider rejected the API credentials"
+// @Failure      503  {object}  errors.AppError "Weather provider unavailable"
 // @Router       /weather [post]
 func (wc *WeatherController) FetchAndStore(c *gin.Context) {
 	var req FetchWeatherRequest
@@ -72,13 +142,20 @@ func (wc *WeatherController) FetchAndStore(c *gin.Context) {
 		return
 	}
 
-	result, err := wc.service.FetchAndStoreWeather(c, req.City, req.Country)
+	var result *weather.Observation
+	var err error
+	if req.Lat != nil && req.Lon != nil {
+		result, err = wc.service.FetchAndStoreWeatherByCoordinates(c, *req.Lat, *req.Lon)
+	} else {
+		result, err = wc.service.FetchAndStoreWeather(c, req.City, req.Country)
+	}
 	if err != nil {
-		_ = c.Error(errors.NewExternalAPIError("Failed to fetch weather data", err, http.StatusInternalServerError))
+		upstreamFailure(c, err, "Failed to fetch weather data", http.StatusInternalServerError)
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	setFreshnessHeaders(c, result)
+	c.JSON(http.StatusOK, result.Weather)
 }
 
 // Deprecated: Duplicate of GetLatestByCity. Kept for backward compatibility (no Swagger docs).
@@ -89,31 +166,265 @@ func (wc *WeatherController) GetByCity(c *gin.Context) {
 		return
 	}
 
+	units, ok := resolveUnits(c)
+	if !ok {
+		return
+	}
+
 	result, err := wc.service.GetLatestWeatherByCity(c, city)
 	if err != nil {
 		_ = c.Error(errors.NewNotFound("Weather data not found for the city", err))
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	c.JSON(http.StatusOK, result.InUnits(units))
 }
 
 // GetAll godoc
-// @Summary      Get all weather records
-// @Description  Retrieves all weather records from the database
+// @Summary      List weather records
+// @Description  Retrieves a page of weather records, optionally filtered by city, country and fetch time
 // @Tags         weather
 // @Produce      json
-// @Success      200  {array}   weather.Weather
+// @Param        city     query     string  false  "Filter by city"
+// @Param        country  query     string  false  "Filter by country code"
+// @Param        from     query     string  false  "Only records fetched at or after this time (RFC3339)"
+// @Param        to       query     string  false  "Only records fetched at or before this time (RFC3339)"
+// @Param        sort     query     string  false  "Sort field" Enums(id, city, country, latitude, longitude, temperature, feels_like, temp_min, temp_max, description, condition_id, icon, humidity, pressure, visibility, clouds, wind_speed, wind_deg, wind_gust, rain, snow, sunrise, sunset, fetched_at, provider, created_at, updated_at) default(fetched_at)
+// @Param        order    query     string  false  "Sort order" Enums(asc, desc) default(desc)
+// @Param        limit    query     int     false  "Page size (max 100)" default(20)
+// @Param        offset   query     int     false  "Number of records to skip" default(0)
+// @Param        units    query     string  false  "Measurement system (defaults to the Accept-Units header, then metric)" Enums(metric, imperial, standard)
+// @Param        Accept-Units  header  string  false  "Default measurement system for this client" Enums(metric, imperial, standard)
+// @Success      200  {object}  WeatherListResponse
+// @Failure      400  {object}  errors.AppError "Invalid query parameters"
 // @Failure      500  {object}  errors.AppError "Failed to fetch weather records"
 // @Router       /weather [get]
 func (wc *WeatherController) GetAll(c *gin.Context) {
-	result, err := wc.service.GetAllWeather(c)
+	var req ListWeatherQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if ok {
+			details := make(map[string]string)
+			for _, e := range validationErrors {
+				details[e.Field()] = e.Error()
+			}
+			_ = c.Error(errors.ValidationError("Invalid query parameters", details))
+			return
+		}
+		_ = c.Error(errors.NewBadRequest("Invalid query parameters", err))
+		return
+	}
+
+	units, ok := resolveUnits(c)
+	if !ok {
+		return
+	}
+
+	if req.Sort != "" && !weather.IsSortField(req.Sort) {
+		_ = c.Error(errors.ValidationError("Invalid query parameters", map[string]string{
+			"sort": "unsupported sort field: " + req.Sort,
+		}))
+		return
+	}
+
+	query := weather.Query{
+		City:        req.City,
+		Country:     req.Country,
+		FetchedFrom: req.From,
+		FetchedTo:   req.To,
+		SortBy:      req.Sort,
+		Descending:  req.Order != "asc",
+		Limit:       req.Limit,
+		Offset:      req.Offset,
+	}
+
+	page, err := wc.service.ListWeather(c, query)
 	if err != nil {
 		_ = c.Error(errors.NewInternalServerError("Failed to fetch weather records", err))
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	data := make([]*weather.Weather, 0, len(page.Items))
+	for _, w := range page.Items {
+		data = append(data, w.InUnits(units))
+	}
+
+	c.JSON(http.StatusOK, WeatherListResponse{
+		Data:   data,
+		Total:  page.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+		Links:  pageLinks(c.Request.URL, page),
+	})
+}
+
+// pageLinks builds self/next/prev links for a page, preserving the request's other query parameters.
+func pageLinks(u *url.URL, page *weather.Page) PageLinks {
+	link := func(offset int) string {
+		params := u.Query()
+		params.Set("limit", strconv.Itoa(page.Limit))
+		params.Set("offset", strconv.Itoa(offset))
+		return u.Path + "?" + params.Encode()
+	}
+
+	links := PageLinks{Self: link(page.Offset)}
+	if int64(page.Offset+page.Limit) < page.Total {
+		links.Next = link(page.Offset + page.Limit)
+	}
+	if page.Offset > 0 {
+		prev := page.Offset - page.Limit
+		if prev < 0 {
+			prev = 0
+		}
+		links.Prev = link(prev)
+	}
+	return links
+}
+
+// GetHistory godoc
+// @Summary      Get weather history for a city
+// @Description  Returns per-bucket min/max/avg temperature, humidity and wind speed for a city over a time range
+// @Tags         weather
+// @Produce      json
+// @Param        city     path      string  true   "City name"
+// @Param        country  query     string  false  "Country code"
+// @Param        from     query     string  false  "Range start (RFC3339), defaults to 24h before to"
+// @Param        to       query     string  false  "Range end (RFC3339), defaults to now"
+// @Param        bucket   query     string  false  "Bucket width, e.g. 15m, 1h, 1d" default(1h)
+// @Param        units    query     string  false  "Measurement system (defaults to the Accept-Units header, then metric)" Enums(metric, imperial, standard)
+// @Param        Accept-Units  header  string  false  "Default measurement system for this client" Enums(metric, imperial, standard)
+// @Success      200  {object}  WeatherHistoryResponse
+// @Failure      400  {object}  errors.AppError "Invalid query parameters"
+// @Failure      500  {object}  errors.AppError "Failed to fetch weather history"
+// @Router       /weather/history/{city} [get]
+func (wc *WeatherController) GetHistory(c *gin.Context) {
+	var req WeatherHistoryQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if ok {
+			details := make(map[string]string)
+			for _, e := range validationErrors {
+				details[e.Field()] = e.Error()
+			}
+			_ = c.Error(errors.ValidationError("Invalid query parameters", details))
+			return
+		}
+		_ = c.Error(errors.NewBadRequest("Invalid query parameters", err))
+		return
+	}
+
+	units, ok := resolveUnits(c)
+	if !ok {
+		return
+	}
+
+	query := weather.HistoryQuery{
+		City:    c.Param("city"),
+		Country: req.Country,
+		From:    req.From,
+		To:      req.To,
+		Bucket:  weather.DefaultHistoryBucket,
+	}
+	if query.To.IsZero() {
+		query.To = time.Now().UTC()
+	}
+	if query.From.IsZero() {
+		query.From = query.To.Add(-weather.DefaultHistoryWindow)
+	}
+	if req.Bucket != "" {
+		bucket, err := weather.ParseBucket(req.Bucket)
+		if err != nil {
+			_ = c.Error(errors.NewBadRequest(err.Error(), err))
+			return
+		}
+		query.Bucket = bucket
+	}
+	if err := query.Validate(); err != nil {
+		_ = c.Error(errors.NewBadRequest(err.Error(), err))
+		return
+	}
+
+	buckets, err := wc.service.GetWeatherHistory(c, query)
+	if err != nil {
+		_ = c.Error(errors.NewInternalServerError("Failed to fetch weather history", err))
+		return
+	}
+
+	converted := make([]*weather.HistoryBucket, 0, len(buckets))
+	for _, b := range buckets {
+		converted = append(converted, b.InUnits(units))
+	}
+
+	bucketLabel := req.Bucket
+	if bucketLabel == "" {
+		bucketLabel = query.Bucket.String()
+	}
+
+	c.JSON(http.StatusOK, WeatherHistoryResponse{
+		City:    query.City,
+		Country: query.Country,
+		From:    query.From,
+		To:      query.To,
+		Bucket:  bucketLabel,
+		Buckets: converted,
+	})
+}
+
+// GetNearby godoc
+// @Summary      Find weather near a point
+// @Description  Returns stored observations within a radius of a latitude/longitude, nearest first
+// @Tags         weather
+// @Produce      json
+// @Param        lat        query     number  true   "Latitude"
+// @Param        lon        query     number  true   "Longitude"
+// @Param        radius_km  query     number  false  "Search radius in km (max 500)" default(25)
+// @Param        limit      query     int     false  "Maximum number of results (max 100)" default(20)
+// @Param        units    query     string  false  "Measurement system (defaults to the Accept-Units header, then metric)" Enums(metric, imperial, standard)
+// @Param        Accept-Units  header  string  false  "Default measurement system for this client" Enums(metric, imperial, standard)
+// @Success      200  {array}   weather.NearbyResult
+// @Failure      400  {object}  errors.AppError "Invalid query parameters"
+// @Failure      500  {object}  errors.AppError "Failed to find nearby weather"
+// @Router       /weather/nearby [get]
+func (wc *WeatherController) GetNearby(c *gin.Context) {
+	var req NearbyWeatherQuery
+	if err := c.ShouldBindQuery(&req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if ok {
+			details := make(map[string]string)
+			for _, e := range validationErrors {
+				details[e.Field()] = e.Error()
+			}
+			_ = c.Error(errors.ValidationError("Invalid query parameters", details))
+			return
+		}
+		_ = c.Error(errors.NewBadRequest("Invalid query parameters", err))
+		return
+	}
+
+	units, ok := resolveUnits(c)
+	if !ok {
+		return
+	}
+
+	query := weather.NearbyQuery{
+		Latitude:  *req.Lat,
+		Longitude: *req.Lon,
+		RadiusKm:  req.RadiusKm,
+		Limit:     req.Limit,
+	}
+
+	result, err := wc.service.FindNearbyWeather(c, query)
+	if err != nil {
+		_ = c.Error(errors.NewInternalServerError("Failed to find nearby weather", err))
+		return
+	}
+
+	converted := make([]*weather.NearbyResult, 0, len(result))
+	for _, r := range result {
+		converted = append(converted, &weather.NearbyResult{Weather: r.Weather.InUnits(units), DistanceKm: r.DistanceKm})
+	}
+
+	c.JSON(http.StatusOK, converted)
 }
 
 // GetByID godoc
@@ -122,17 +433,24 @@ func (wc *WeatherController) GetAll(c *gin.Context) {
 // @Tags         weather
 // @Produce      json
 // @Param        id   path      string  true  "Weather ID"
+// @Param        units    query     string  false  "Measurement system (defaults to the Accept-Units header, then metric)" Enums(metric, imperial, standard)
+// @Param        Accept-Units  header  string  false  "Default measurement system for this client" Enums(metric, imperial, standard)
 // @Success      200  {object}  weather.Weather
+// @Failure      400  {object}  errors.AppError "Invalid units"
 // @Failure      404  {object}  errors.AppError "Weather data not found"
 // @Router       /weather/{id} [get]
 func (wc *WeatherController) GetByID(c *gin.Context) {
 	id := c.Param("id")
+	units, ok := resolveUnits(c)
+	if !ok {
+		return
+	}
 	result, err := wc.service.GetWeatherByID(c, id)
 	if err != nil {
 		_ = c.Error(errors.NewNotFound("Weather data not found", err))
 		return
 	}
-	c.JSON(http.StatusOK, result)
+	c.JSON(http.StatusOK, result.InUnits(units))
 }
 
 // Update godoc
@@ -208,15 +526,33 @@ func (wc *WeatherController) Delete(c *gin.Context) {
 // @Tags         weather
 // @Produce      json
 // @Param        city   path      string  true  "City name"
+// @Param        units    query     string  false  "Measurement system (defaults to the Accept-Units header, then metric)" Enums(metric, imperial, standard)
+// @Param        Accept-Units  header  string  false  "Default measurement system for this client" Enums(metric, imperial, standard)
 // @Success      200    {object}  weather.Weather
+// @Failure      400    {object}  errors.AppError "Invalid units"
 // @Failure      404    {object}  errors.AppError "Weather data not found for the city"
 // @Router       /weather/latest/{city} [get]
 func (wc *WeatherController) GetLatestByCity(c *gin.Context) {
 	city := c.Param("city")
+	units, ok := resolveUnits(c)
+	if !ok {
+		return
+	}
 	result, err := wc.service.GetLatestWeatherByCity(c, city)
 	if err != nil {
 		_ = c.Error(errors.NewNotFound("Weather data not found for the city", err))
 		return
 	}
-	c.JSON(http.StatusOK, result)
+	c.JSON(http.StatusOK, result.InUnits(units))
+}
+
+// setFreshnessHeaders reports the age of an observation that was not fetched for this
+// request, and warns when it is stale
+func setFreshnessHeaders(c *gin.Context, o *weather.Observation) {
+	if age := int(o.Age / time.Second); age > 0 {
+		c.Header("Age", strconv.Itoa(age))
+	}
+	if o.Stale {
+		c.Header("Warning", `110 - "Response is Stale"`)
+	}
 }
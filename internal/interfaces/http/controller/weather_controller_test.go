@@ -2,6 +2,8 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -9,8 +11,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
 	"github.com/OmidRasouli/weather-api/internal/domain/weather"
 	"github.com/OmidRasouli/weather-api/internal/testhelpers"
+	"github.com/OmidRasouli/weather-api/pkg/errors"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/assert/v2"
 	"github.com/stretchr/testify/mock"
@@ -20,9 +24,25 @@ type MockWeatherService struct {
 	mock.Mock
 }
 
-func (m *MockWeatherService) FetchAndStoreWeather(ctx context.Context, city, country string) (*weather.Weather, error) {
+func (m *MockWeatherService) FetchAndStoreWeather(ctx context.Context, city, country string) (*weather.Observation, error) {
 	args := m.Called(ctx, city, country)
-	return args.Get(0).(*weather.Weather), args.Error(1)
+	return args.Get(0).(*weather.Observation), args.Error(1)
+}
+
+func (m *MockWeatherService) FetchAndStoreWeatherByCoordinates(ctx context.Context, lat, lon float64) (*weather.Observation, error) {
+	args := m.Called(ctx, lat, lon)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*weather.Observation), args.Error(1)
+}
+
+func (m *MockWeatherService) FindNearbyWeather(ctx context.Context, q weather.NearbyQuery) ([]*weather.NearbyResult, error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*weather.NearbyResult), args.Error(1)
 }
 
 func (m *MockWeatherService) GetLatestWeatherByCity(ctx context.Context, city string) (*weather.Weather, error) {
@@ -33,9 +53,20 @@ func (m *MockWeatherService) GetLatestWeatherByCity(ctx context.Context, city st
 	return args.Get(0).(*weather.Weather), args.Error(1)
 }
 
-func (m *MockWeatherService) GetAllWeather(ctx context.Context) ([]*weather.Weather, error) {
-	args := m.Called(ctx)
-	return args.Get(0).([]*weather.Weather), args.Error(1)
+func (m *MockWeatherService) ListWeather(ctx context.Context, q weather.Query) (*weather.Page, error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*weather.Page), args.Error(1)
+}
+
+func (m *MockWeatherService) GetWeatherHistory(ctx context.Context, q weather.HistoryQuery) ([]*weather.HistoryBucket, error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*weather.HistoryBucket), args.Error(1)
 }
 
 func (m *MockWeatherService) GetWeatherByID(ctx context.Context, id string) (*weather.Weather, error) {
@@ -87,7 +118,7 @@ func TestFetchAndStore_Success(t *testing.T) {
 		FetchedAt:   time.Now(),
 	}
 
-	mockService.On("FetchAndStoreWeather", mock.Anything, "tehran", "IR").Return(expected, nil)
+	mockService.On("FetchAndStoreWeather", mock.Anything, "tehran", "IR").Return(&weather.Observation{Weather: expected}, nil)
 
 	sut.FetchAndStore(c)
 
@@ -95,6 +126,37 @@ func TestFetchAndStore_Success(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestFetchAndStore_StaleObservation(t *testing.T) {
+	mockService := new(MockWeatherService)
+	sut := NewWeatherController(mockService)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/weather", strings.NewReader(`{"city":"tehran", "country":"IR"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	cached := &weather.Observation{
+		Weather: &weather.Weather{City: "tehran", Country: "IR", Temperature: 28.5},
+		Age:     7 * time.Minute,
+		Stale:   true,
+	}
+	mockService.On("FetchAndStoreWeather", mock.Anything, "tehran", "IR").Return(cached, nil)
+
+	sut.FetchAndStore(c)
+
+	// Freshness is reported in headers only, the body is the record itself
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "420", w.Header().Get("Age"))
+	assert.Equal(t, `110 - "Response is Stale"`, w.Header().Get("Warning"))
+	var body map[string]interface{}
+	assert.Equal(t, nil, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "tehran", body["City"])
+	_, hasStale := body["Stale"]
+	_, hasAge := body["AgeSeconds"]
+	assert.Equal(t, false, hasStale)
+	assert.Equal(t, false, hasAge)
+}
+
 func TestGetByID_Success(t *testing.T) {
 	mockService := new(MockWeatherService)
 	sut := NewWeatherController(mockService)
@@ -195,3 +257,366 @@ func TestGetLatestByCity_Success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	mockService.AssertExpectations(t)
 }
+
+func TestGetAll_Paginated(t *testing.T) {
+	mockService := new(MockWeatherService)
+	sut := NewWeatherController(mockService)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/weather?city=tehran&sort=temperature&order=asc&limit=2&offset=2", nil)
+
+	expectedQuery := weather.Query{
+		City:       "tehran",
+		SortBy:     "temperature",
+		Descending: false,
+		Limit:      2,
+		Offset:     2,
+	}
+	page := &weather.Page{
+		Items: []*weather.Weather{
+			{City: "tehran", Country: "IR", Temperature: 20.5},
+			{City: "tehran", Country: "IR", Temperature: 21.0},
+		},
+		Total:  5,
+		Limit:  2,
+		Offset: 2,
+	}
+
+	mockService.On("ListWeather", mock.Anything, expectedQuery).Return(page, nil)
+
+	sut.GetAll(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var body WeatherListResponse
+	err := json.Unmarshal(w.Body.Bytes(), &body)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(5), body.Total)
+	assert.Equal(t, 2, len(body.Data))
+	assert.Equal(t, "/weather?city=tehran&limit=2&offset=4&order=asc&sort=temperature", body.Links.Next)
+	assert.Equal(t, "/weather?city=tehran&limit=2&offset=0&order=asc&sort=temperature", body.Links.Prev)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetAll_InvalidSortField(t *testing.T) {
+	mockService := new(MockWeatherService)
+	sut := NewWeatherController(mockService)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/weather?sort=password", nil)
+
+	sut.GetAll(c)
+
+	assert.Equal(t, 1, len(c.Errors))
+	appErr, ok := c.Errors.Last().Err.(*errors.AppError)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, http.StatusBadRequest, appErr.Code)
+	mockService.AssertNotCalled(t, "ListWeather", mock.Anything, mock.Anything)
+}
+
+func TestGetAll_SortFields(t *testing.T) {
+	for _, field := range []string{"latitude", "longitude", "icon", "provider"} {
+		t.Run(field, func(t *testing.T) {
+			mockService := new(MockWeatherService)
+			sut := NewWeatherController(mockService)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/weather?sort="+field, nil)
+
+			sortedBy := mock.MatchedBy(func(q weather.Query) bool { return q.SortBy == field })
+			mockService.On("ListWeather", mock.Anything, sortedBy).Return(&weather.Page{}, nil)
+
+			sut.GetAll(c)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetHistory_Success(t *testing.T) {
+	mockService := new(MockWeatherService)
+	sut := NewWeatherController(mockService)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "city", Value: "tehran"}}
+	c.Request = httptest.NewRequest("GET", "/weather/history/tehran?from=2025-01-01T00:00:00Z&to=2025-01-08T00:00:00Z&bucket=1d", nil)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	expectedQuery := weather.HistoryQuery{
+		City:   "tehran",
+		From:   from,
+		To:     from.Add(7 * 24 * time.Hour),
+		Bucket: 24 * time.Hour,
+	}
+	buckets := []*weather.HistoryBucket{
+		{Start: from, Samples: 3, MinTemperature: 10, MaxTemperature: 14, AvgTemperature: 12},
+	}
+
+	mockService.On("GetWeatherHistory", mock.Anything, mock.MatchedBy(func(q weather.HistoryQuery) bool {
+		return q.City == expectedQuery.City && q.From.Equal(expectedQuery.From) &&
+			q.To.Equal(expectedQuery.To) && q.Bucket == expectedQuery.Bucket
+	})).Return(buckets, nil)
+
+	sut.GetHistory(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var body WeatherHistoryResponse
+	err := json.Unmarshal(w.Body.Bytes(), &body)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "1d", body.Bucket)
+	assert.Equal(t, 1, len(body.Buckets))
+	assert.Equal(t, 12.0, body.Buckets[0].AvgTemperature)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetHistory_InvalidBucket(t *testing.T) {
+	mockService := new(MockWeatherService)
+	sut := NewWeatherController(mockService)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "city", Value: "tehran"}}
+	c.Request = httptest.NewRequest("GET", "/weather/history/tehran?bucket=1s", nil)
+
+	sut.GetHistory(c)
+
+	assert.Equal(t, 1, len(c.Errors))
+	appErr, ok := c.Errors.Last().Err.(*errors.AppError)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, http.StatusBadRequest, appErr.Code)
+	mockService.AssertNotCalled(t, "GetWeatherHistory", mock.Anything, mock.Anything)
+}
+
+func TestFetchAndStore_ByCoordinates(t *testing.T) {
+	mockService := new(MockWeatherService)
+	sut := NewWeatherController(mockService)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	reqBody := `{"lat":35.6892, "lon":51.389}`
+	c.Request = httptest.NewRequest("POST", "/weather", strings.NewReader(reqBody))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	lat, lon := 35.6892, 51.389
+	expected := &weather.Weather{
+		City:        "Tehran",
+		Country:     "IR",
+		Latitude:    &lat,
+		Longitude:   &lon,
+		Temperature: 32.5,
+	}
+
+	mockService.On("FetchAndStoreWeatherByCoordinates", mock.Anything, lat, lon).Return(&weather.Observation{Weather: expected}, nil)
+
+	sut.FetchAndStore(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertNotCalled(t, "FetchAndStoreWeather", mock.Anything, mock.Anything, mock.Anything)
+	mockService.AssertExpectations(t)
+}
+
+func TestFetchAndStore_MissingLocation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "empty body", body: `{}`},
+		{name: "lat without lon", body: `{"lat":35.6892}`},
+		{name: "city without country", body: `{"city":"tehran"}`},
+		{name: "latitude out of range", body: `{"lat":135.0, "lon":51.389}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockWeatherService)
+			sut := NewWeatherController(mockService)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("POST", "/weather", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			sut.FetchAndStore(c)
+
+			assert.Equal(t, 1, len(c.Errors))
+			appErr, ok := c.Errors.Last().Err.(*errors.AppError)
+			assert.Equal(t, true, ok)
+			assert.Equal(t, http.StatusBadRequest, appErr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetNearby_Success(t *testing.T) {
+	mockService := new(MockWeatherService)
+	sut := NewWeatherController(mockService)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/weather/nearby?lat=35.7&lon=51.4&radius_km=10", nil)
+
+	expectedQuery := weather.NearbyQuery{Latitude: 35.7, Longitude: 51.4, RadiusKm: 10}
+	results := []*weather.NearbyResult{
+		{Weather: &weather.Weather{City: "tehran", Country: "IR"}, DistanceKm: 1.2},
+	}
+	mockService.On("FindNearbyWeather", mock.Anything, expectedQuery).Return(results, nil)
+
+	sut.GetNearby(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var body []map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &body)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(body))
+	assert.Equal(t, "tehran", body[0]["City"])
+	assert.Equal(t, 1.2, body[0]["distanceKm"])
+	mockService.AssertExpectations(t)
+}
+
+func TestGetNearby_MissingCoordinates(t *testing.T) {
+	mockService := new(MockWeatherService)
+	sut := NewWeatherController(mockService)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/weather/nearby?lat=35.7", nil)
+
+	sut.GetNearby(c)
+
+	assert.Equal(t, 1, len(c.Errors))
+	mockService.AssertNotCalled(t, "FindNearbyWeather", mock.Anything, mock.Anything)
+}
+
+func TestGetByID_Units(t *testing.T) {
+	tests := []struct {
+		name            string
+		url             string
+		acceptUnits     string
+		wantTemperature float64
+		wantWindSpeed   float64
+		wantUnits       string
+	}{
+		{name: "default metric", url: "/weather/test-id", wantTemperature: 20, wantWindSpeed: 10, wantUnits: "metric"},
+		{name: "imperial query", url: "/weather/test-id?units=imperial", wantTemperature: 68, wantWindSpeed: 22.369362920544, wantUnits: "imperial"},
+		{name: "header default", url: "/weather/test-id", acceptUnits: "standard", wantTemperature: 293.15, wantWindSpeed: 10, wantUnits: "standard"},
+		{name: "query overrides header", url: "/weather/test-id?units=metric", acceptUnits: "imperial", wantTemperature: 20, wantWindSpeed: 10, wantUnits: "metric"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockWeatherService)
+			sut := NewWeatherController(mockService)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Params = gin.Params{{Key: "id", Value: "test-id"}}
+			c.Request = httptest.NewRequest("GET", tt.url, nil)
+			if tt.acceptUnits != "" {
+				c.Request.Header.Set(AcceptUnitsHeader, tt.acceptUnits)
+			}
+
+			stored := &weather.Weather{City: "tehran", Country: "IR", Temperature: 20, WindSpeed: 10}
+			mockService.On("GetWeatherByID", mock.Anything, "test-id").Return(stored, nil)
+
+			sut.GetByID(c)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.wantUnits, w.Header().Get(ContentUnitsHeader))
+
+			var body weather.Weather
+			err := json.Unmarshal(w.Body.Bytes(), &body)
+			assert.Equal(t, nil, err)
+			assert.Equal(t, tt.wantTemperature, body.Temperature)
+			assert.Equal(t, tt.wantWindSpeed, body.WindSpeed)
+
+			// The stored (canonical) value must not be modified
+			assert.Equal(t, 20.0, stored.Temperature)
+		})
+	}
+}
+
+func TestGetByID_InvalidUnits(t *testing.T) {
+	mockService := new(MockWeatherService)
+	sut := NewWeatherController(mockService)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Params = gin.Params{{Key: "id", Value: "test-id"}}
+	c.Request = httptest.NewRequest("GET", "/weather/test-id?units=rankine", nil)
+
+	sut.GetByID(c)
+
+	assert.Equal(t, 1, len(c.Errors))
+	appErr, ok := c.Errors.Last().Err.(*errors.AppError)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, http.StatusBadRequest, appErr.Code)
+	mockService.AssertNotCalled(t, "GetWeatherByID", mock.Anything, mock.Anything)
+}
+
+func TestFetchAndStore_QuotaExhausted(t *testing.T) {
+	mockService := new(MockWeatherService)
+	sut := NewWeatherController(mockService)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/weather", strings.NewReader(`{"city":"tehran", "country":"IR"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	quotaErr := &interfaces.QuotaExceededError{Provider: "openweather", Period: "minute", RetryAfter: 12500 * time.Millisecond}
+	mockService.On("FetchAndStoreWeather", mock.Anything, "tehran", "IR").
+		Return((*weather.Observation)(nil), fmt.Errorf("all weather providers failed: %w", quotaErr))
+
+	sut.FetchAndStore(c)
+
+	assert.Equal(t, 1, len(c.Errors))
+	appErr, ok := c.Errors.Last().Err.(*errors.AppError)
+	assert.Equal(t, true, ok)
+	assert.Equal(t, http.StatusTooManyRequests, appErr.Code)
+	assert.Equal(t, "13", w.Header().Get("Retry-After"))
+	mockService.AssertExpectations(t)
+}
+
+func TestFetchAndStore_UpstreamErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{name: "unknown city", err: &interfaces.UpstreamError{Provider: "openweather", StatusCode: 404, Kind: interfaces.ErrCityNotFound}, status: http.StatusNotFound},
+		{name: "bad credentials", err: &interfaces.UpstreamError{Provider: "openweather", StatusCode: 401, Kind: interfaces.ErrUpstreamUnauthorized}, status: http.StatusBadGateway},
+		{name: "throttled", err: &interfaces.UpstreamError{Provider: "openweather", StatusCode: 429, Kind: interfaces.ErrUpstreamRateLimited}, status: http.StatusTooManyRequests},
+		{name: "unavailable", err: &interfaces.UpstreamError{Provider: "openweather", StatusCode: 503, Kind: interfaces.ErrUpstreamUnavailable}, status: http.StatusServiceUnavailable},
+		{name: "circuit open", err: interfaces.ErrCircuitOpen, status: http.StatusServiceUnavailable},
+		{name: "unclassified", err: fmt.Errorf("invalid response: missing weather description"), status: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockWeatherService)
+			sut := NewWeatherController(mockService)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("POST", "/weather", strings.NewReader(`{"city":"tehran", "country":"IR"}`))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			mockService.On("FetchAndStoreWeather", mock.Anything, "tehran", "IR").
+				Return((*weather.Observation)(nil), fmt.Errorf("all weather providers failed: %w", tt.err))
+
+			sut.FetchAndStore(c)
+
+			assert.Equal(t, 1, len(c.Errors))
+			appErr, ok := c.Errors.Last().Err.(*errors.AppError)
+			assert.Equal(t, true, ok)
+			assert.Equal(t, tt.status, appErr.Code)
+		})
+	}
+}
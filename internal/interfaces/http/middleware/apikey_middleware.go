@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/OmidRasouli/weather-api/internal/domain/apikey"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader carries API keys of machine clients.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuthenticator resolves a plaintext API key to its record.
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, plaintext string) (*apikey.Key, error)
+}
+
+// APIKeyAuth is the API key sibling of JWTAuth. It validates X-API-Key and stores
+// "apikey:<prefix>" as "user", the key's scopes as "scopes" and the key as "apiKey",
+// so RequireScope works the same for both. Keys carry no role.
+func APIKeyAuth(keys APIKeyAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		plaintext := c.GetHeader(APIKeyHeader)
+		if plaintext == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing X-API-Key header"})
+			return
+		}
+
+		k, err := keys.Authenticate(c, plaintext)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or revoked api key"})
+			return
+		}
+
+		c.Set("user", "apikey:"+k.Prefix)
+		c.Set("scopes", k.TokenScopes())
+		c.Set("apiKey", k)
+		c.Next()
+	}
+}
+
+// JWTOrAPIKey authenticates with apiKeyAuth when the request has an X-API-Key header
+// and with jwtAuth otherwise.
+func JWTOrAPIKey(jwtAuth gin.HandlerFunc, apiKeyAuth gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(APIKeyHeader) != "" {
+			apiKeyAuth(c)
+			return
+		}
+		jwtAuth(c)
+	}
+}
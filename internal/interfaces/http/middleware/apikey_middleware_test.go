@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	authUseCase "github.com/OmidRasouli/weather-api/internal/application/auth"
+	"github.com/OmidRasouli/weather-api/internal/domain/apikey"
+	"github.com/OmidRasouli/weather-api/internal/domain/user"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+)
+
+// fakeKeys authenticates the keys in its map
+type fakeKeys map[string]*apikey.Key
+
+func (f fakeKeys) Authenticate(ctx context.Context, plaintext string) (*apikey.Key, error) {
+	if k, ok := f[plaintext]; ok {
+		return k, nil
+	}
+	return nil, errors.New("unknown key")
+}
+
+func TestJWTOrAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	authUC := authUseCase.NewUseCase(testAuthService(), nil, nil, 0, 0)
+	keys := fakeKeys{
+		"read-key":  {Prefix: "aaaaaaaa", Scopes: []string{apikey.ScopeRead}},
+		"write-key": {Prefix: "bbbbbbbb", Scopes: []string{apikey.ScopeRead, apikey.ScopeWrite}},
+	}
+
+	r := gin.New()
+	protected := r.Group("/weather", JWTOrAPIKey(JWTAuth(authUC), APIKeyAuth(keys)))
+	protected.POST("", RequireScope(user.ScopeWeatherWrite), func(c *gin.Context) { c.Status(http.StatusCreated) })
+	protected.DELETE("/:id", RequireScope(user.ScopeWeatherDelete), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	token, _, err := testAuthService().GenerateToken("alice", user.RoleEditor, time.Minute)
+	assert.Equal(t, nil, err)
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		headers map[string]string
+		want    int
+	}{
+		{"write key can fetch", http.MethodPost, "/weather", map[string]string{"X-API-Key": "write-key"}, http.StatusCreated},
+		{"read key cannot fetch", http.MethodPost, "/weather", map[string]string{"X-API-Key": "read-key"}, http.StatusForbidden},
+		{"keys cannot delete", http.MethodDelete, "/weather/1", map[string]string{"X-API-Key": "write-key"}, http.StatusForbidden},
+		{"unknown key", http.MethodPost, "/weather", map[string]string{"X-API-Key": "stolen"}, http.StatusUnauthorized},
+		{"bearer token still works", http.MethodPost, "/weather", map[string]string{"Authorization": "Bearer " + token}, http.StatusCreated},
+		{"no credentials", http.MethodPost, "/weather", nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
@@ -1,13 +1,20 @@
 package middleware
 
 import (
+	"errors"
 	"net/http"
+	"slices"
 	"strings"
 
 	authUseCase "github.com/OmidRasouli/weather-api/internal/application/auth"
+	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/domain/user"
 	"github.com/gin-gonic/gin"
 )
 
+// JWTAuth validates the bearer access token, ours or one issued by the configured OIDC
+// issuer, rejects revoked ones, and stores its subject, role, scopes and full claims in
+// the gin context as "user", "role", "scopes" and "claims".
 func JWTAuth(authUC *authUseCase.UseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
@@ -22,13 +29,63 @@ func JWTAuth(authUC *authUseCase.UseCase) gin.HandlerFunc {
 			return
 		}
 
-		username, err := authUC.ValidateToken(parts[1])
+		claims, err := authUC.ValidateToken(c, parts[1])
+		if errors.Is(err, authUseCase.ErrRevocationUnavailable) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "token revocation check unavailable"})
+			return
+		}
+		if errors.Is(err, authUseCase.ErrTokenRevoked) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token has been revoked"})
+			return
+		}
+		if errors.Is(err, authUseCase.ErrNoRole) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "no role is mapped to your groups"})
+			return
+		}
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
 			return
 		}
 
-		c.Set("user", username)
+		c.Set("user", claims.Subject)
+		c.Set("role", string(claims.Role))
+		c.Set("scopes", claims.Scopes())
+		c.Set("claims", claims)
+		c.Next()
+	}
+}
+
+// RequireRole must run after JWTAuth. It rejects requests whose token role does not include role.
+func RequireRole(role user.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !user.Role(c.GetString("role")).Includes(role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient role"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireScope must run after JWTAuth. It rejects requests whose token does not grant scope.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !slices.Contains(c.GetStringSlice("scopes"), scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "insufficient scope"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAdmin must run after JWTAuth. It re-reads the account on every request,
+// so disabling an admin or demoting it takes effect before the token expires.
+func RequireAdmin(users interfaces.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		u, err := users.FindByUsername(c, c.GetString("user"))
+		if err != nil || u.Disabled || !u.Role.Includes(user.RoleAdmin) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin privileges required"})
+			return
+		}
 		c.Next()
 	}
 }
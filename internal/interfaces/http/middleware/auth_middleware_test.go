@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	authUseCase "github.com/OmidRasouli/weather-api/internal/application/auth"
+	"github.com/OmidRasouli/weather-api/internal/domain/services"
+	"github.com/OmidRasouli/weather-api/internal/domain/user"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+)
+
+// testAuthService signs with a fixed HS256 secret
+func testAuthService() *services.AuthService {
+	return services.NewAuthService(services.NewKeySet(services.NewHMACKey([]byte("test-secret"))))
+}
+
+// setupRoleRouter mounts the same guards the router uses for fetching and deleting weather
+func setupRoleRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	authUC := authUseCase.NewUseCase(testAuthService(), nil, nil, 0, 0)
+
+	r := gin.New()
+	protected := r.Group("/weather", JWTAuth(authUC))
+	protected.POST("", RequireScope(user.ScopeWeatherWrite), func(c *gin.Context) { c.Status(http.StatusCreated) })
+	protected.DELETE("/:id", RequireRole(user.RoleAdmin), func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func TestRoleAuthorization(t *testing.T) {
+	router := setupRoleRouter()
+
+	tests := []struct {
+		name   string
+		role   user.Role
+		method string
+		path   string
+		want   int
+	}{
+		{"viewer cannot fetch", user.RoleViewer, http.MethodPost, "/weather", http.StatusForbidden},
+		{"editor can fetch", user.RoleEditor, http.MethodPost, "/weather", http.StatusCreated},
+		{"editor cannot delete", user.RoleEditor, http.MethodDelete, "/weather/1", http.StatusForbidden},
+		{"admin can delete", user.RoleAdmin, http.MethodDelete, "/weather/1", http.StatusOK},
+		{"token without role cannot fetch", "", http.MethodPost, "/weather", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, _, err := testAuthService().GenerateToken("alice", tt.role, time.Minute)
+			assert.Equal(t, nil, err)
+
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
+
+func TestRoleAuthorization_MissingToken(t *testing.T) {
+	router := setupRoleRouter()
+
+	req, _ := http.NewRequest(http.MethodPost, "/weather", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/OmidRasouli/weather-api/internal/application/ratelimit"
+	"github.com/OmidRasouli/weather-api/internal/domain/apikey"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimits are the requests allowed per window for each kind of client. A zero limit
+// disables limiting for that kind of client.
+type RateLimits struct {
+	PerIP     int
+	PerUser   int
+	PerAPIKey int
+}
+
+// RateLimiter builds the rate limit middlewares of the read and write route groups,
+// which share one limiter but are counted and limited separately.
+type RateLimiter struct {
+	limiter *ratelimit.Limiter
+	read    RateLimits
+	write   RateLimits
+}
+
+func NewRateLimiter(limiter *ratelimit.Limiter, read RateLimits, write RateLimits) *RateLimiter {
+	return &RateLimiter{limiter: limiter, read: read, write: write}
+}
+
+// Read limits read-only routes. A nil RateLimiter disables limiting.
+func (rl *RateLimiter) Read() gin.HandlerFunc {
+	if rl == nil {
+		return passThrough
+	}
+	return RateLimit(rl.limiter, "read", rl.read)
+}
+
+// Write limits routes that change data or spend upstream quota. A nil RateLimiter disables limiting.
+func (rl *RateLimiter) Write() gin.HandlerFunc {
+	if rl == nil {
+		return passThrough
+	}
+	return RateLimit(rl.limiter, "write", rl.write)
+}
+
+func passThrough(c *gin.Context) {
+	c.Next()
+}
+
+// RateLimit counts requests per client within group and rejects them with 429 once the
+// client's limit is used up. Placed after JWTAuth or APIKeyAuth it limits per API key
+// or per user; otherwise, or for anonymous requests, per client IP. Every response
+// carries X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset (seconds until
+// the window resets); rejected ones also carry Retry-After.
+func RateLimit(limiter *ratelimit.Limiter, group string, limits RateLimits) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, limit := clientKey(c, limits)
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		result := limiter.Allow(c, group+":"+key, limit)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(int(math.Ceil(result.Reset.Seconds()))))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(int(result.RetryAfter.Seconds())))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// clientKey identifies the client of the request and picks its limit
+func clientKey(c *gin.Context, limits RateLimits) (string, int) {
+	if v, ok := c.Get("apiKey"); ok {
+		if k, ok := v.(*apikey.Key); ok {
+			return "apikey:" + k.ID.String(), limits.PerAPIKey
+		}
+	}
+	if username := c.GetString("user"); username != "" {
+		return "user:" + username, limits.PerUser
+	}
+	return "ip:" + c.ClientIP(), limits.PerIP
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OmidRasouli/weather-api/internal/application/ratelimit"
+	"github.com/OmidRasouli/weather-api/internal/domain/apikey"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"github.com/google/uuid"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limiter := NewRateLimiter(ratelimit.NewLimiter(nil, time.Minute),
+		RateLimits{PerIP: 2, PerUser: 3},
+		RateLimits{PerIP: 1, PerUser: 1, PerAPIKey: 0},
+	)
+	keys := fakeKeys{"write-key": {ID: uuid.New(), Prefix: "bbbbbbbb", Scopes: []string{apikey.ScopeWrite}}}
+
+	// identify stands in for JWTAuth
+	identify := func(c *gin.Context) {
+		if name := c.GetHeader("X-Test-User"); name != "" {
+			c.Set("user", name)
+		}
+		c.Next()
+	}
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+
+	r := gin.New()
+	r.GET("/weather", limiter.Read(), ok)
+	r.GET("/alerts", identify, limiter.Read(), ok)
+	r.POST("/weather", JWTOrAPIKey(identify, APIKeyAuth(keys)), limiter.Write(), ok)
+
+	do := func(method string, path string, headers map[string]string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Anonymous reads are limited per IP
+	w := do(http.MethodGet, "/weather", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/weather", nil).Code)
+
+	w = do(http.MethodGet, "/weather", nil)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	assert.NotEqual(t, "", w.Header().Get("Retry-After"))
+	assert.NotEqual(t, "", w.Header().Get("X-RateLimit-Reset"))
+
+	// Users get their own, larger budget from the same IP
+	alice := map[string]string{"X-Test-User": "alice"}
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, do(http.MethodGet, "/alerts", alice).Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, do(http.MethodGet, "/alerts", alice).Code)
+
+	// Writes are counted separately from reads
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/weather", alice).Code)
+	assert.Equal(t, http.StatusTooManyRequests, do(http.MethodPost, "/weather", alice).Code)
+
+	// A zero limit disables limiting for API keys
+	for i := 0; i < 3; i++ {
+		w = do(http.MethodPost, "/weather", map[string]string{"X-API-Key": "write-key"})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "", w.Header().Get("X-RateLimit-Limit"))
+	}
+
+	// A nil limiter disables limiting altogether
+	var disabled *RateLimiter
+	r2 := gin.New()
+	r2.GET("/weather", disabled.Read(), ok)
+	for i := 0; i < 5; i++ {
+		req, _ := http.NewRequest(http.MethodGet, "/weather", nil)
+		w := httptest.NewRecorder()
+		r2.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+}
@@ -3,6 +3,7 @@ package router
 import (
 	authUseCase "github.com/OmidRasouli/weather-api/internal/application/auth"
 	"github.com/OmidRasouli/weather-api/internal/application/interfaces"
+	"github.com/OmidRasouli/weather-api/internal/domain/user"
 	"github.com/OmidRasouli/weather-api/internal/interfaces/http/controller"
 	"github.com/OmidRasouli/weather-api/internal/interfaces/http/middleware"
 	"github.com/gin-contrib/cors"
@@ -11,8 +12,18 @@ import (
 
 func Setup(
 	weatherController *controller.WeatherController,
+	forecastController *controller.ForecastController,
+	trackingController *controller.TrackingController,
+	alertController *controller.AlertController,
+	userController *controller.UserController,
+	apiKeyController *controller.APIKeyController,
+	quotaController *controller.QuotaController,
+	cacheController *controller.CacheController,
 	authController *controller.AuthController,
 	authUC *authUseCase.UseCase,
+	users interfaces.UserRepository,
+	apiKeys middleware.APIKeyAuthenticator,
+	rateLimiter *middleware.RateLimiter,
 	db interfaces.Database,
 	redisClient interfaces.Cache) *gin.Engine {
 	router := gin.Default()
@@ -23,24 +34,83 @@ func Setup(
 	// Global error handler to standardize error responses and convert c.Error(...) into JSON.
 	router.Use(middleware.ErrorHandler())
 
-	// Auth routes (public)
-	router.POST("/login", authController.Login)
+	// Rate limits: reads and writes are counted separately, per API key, user or client IP.
+	// Limits run after authentication so authenticated clients are counted by identity.
+	readLimit := rateLimiter.Read()
+	writeLimit := rateLimiter.Write()
+
+	// Auth routes (public); credential checks count as writes, limited per IP
+	router.POST("/login", writeLimit, authController.Login)
+	router.POST("/token/refresh", writeLimit, authController.Refresh)
+	router.POST("/logout", middleware.JWTAuth(authUC), authController.Logout)
+	router.GET("/.well-known/jwks.json", authController.JWKS)
+	router.GET("/auth/oidc/login", authController.OIDCLogin)
+	router.GET("/auth/oidc/callback", writeLimit, authController.OIDCCallback)
 
 	// Public weather routes (read-only)
-	weatherPublic := router.Group("/weather")
+	weatherPublic := router.Group("/weather", readLimit)
 	{
 		weatherPublic.GET("", weatherController.GetAll)
 		// Register static path before parameterized to avoid shadowing
 		weatherPublic.GET("/latest/:city", weatherController.GetLatestByCity)
+		weatherPublic.GET("/history/:city", weatherController.GetHistory)
+		weatherPublic.GET("/nearby", weatherController.GetNearby)
 		weatherPublic.GET("/:id", weatherController.GetByID)
 	}
 
-	// Protected weather routes (mutating operations require JWT)
-	weatherProtected := router.Group("/weather", middleware.JWTAuth(authUC))
+	// Protected weather routes: fetching and editing need weather:write (editor or a write API key),
+	// deleting weather:delete (admin). Machine clients may authenticate with X-API-Key instead of a JWT.
+	weatherProtected := router.Group("/weather", middleware.JWTOrAPIKey(middleware.JWTAuth(authUC), middleware.APIKeyAuth(apiKeys)), writeLimit)
 	{
-		weatherProtected.POST("", weatherController.FetchAndStore)
-		weatherProtected.PUT("/:id", weatherController.Update)
-		weatherProtected.DELETE("/:id", weatherController.Delete)
+		weatherProtected.POST("", middleware.RequireScope(user.ScopeWeatherWrite), weatherController.FetchAndStore)
+		weatherProtected.PUT("/:id", middleware.RequireScope(user.ScopeWeatherWrite), weatherController.Update)
+		weatherProtected.DELETE("/:id", middleware.RequireScope(user.ScopeWeatherDelete), weatherController.Delete)
+	}
+
+	// Public forecast routes
+	router.GET("/forecast/:city", readLimit, forecastController.GetByCity)
+
+	// Tracked locations drive the background refresh scheduler (reads need weather:read, changes weather:write)
+	canRead := middleware.RequireScope(user.ScopeWeatherRead)
+	canWrite := middleware.RequireScope(user.ScopeWeatherWrite)
+	tracked := router.Group("/tracked-locations", middleware.JWTAuth(authUC))
+	{
+		tracked.GET("", canRead, readLimit, trackingController.List)
+		tracked.POST("", canWrite, writeLimit, trackingController.Create)
+		tracked.GET("/:id", canRead, readLimit, trackingController.GetByID)
+		tracked.PUT("/:id", canWrite, writeLimit, trackingController.Update)
+		tracked.DELETE("/:id", canWrite, writeLimit, trackingController.Delete)
+	}
+
+	// Alert rules notify webhooks when stored observations match (reads need weather:read, changes weather:write)
+	alerts := router.Group("/alerts", middleware.JWTAuth(authUC))
+	{
+		alerts.GET("", canRead, readLimit, alertController.List)
+		alerts.POST("", canWrite, writeLimit, alertController.Create)
+		alerts.GET("/:id", canRead, readLimit, alertController.GetByID)
+		alerts.PUT("/:id", canWrite, writeLimit, alertController.Update)
+		alerts.DELETE("/:id", canWrite, writeLimit, alertController.Delete)
+		alerts.GET("/:id/deliveries", canRead, readLimit, alertController.GetDeliveries)
+	}
+
+	// Administration: the token must carry the admin role and the account must still be an enabled admin
+	admin := router.Group("/admin", middleware.JWTAuth(authUC), middleware.RequireRole(user.RoleAdmin), middleware.RequireAdmin(users))
+	{
+		admin.GET("/users", userController.List)
+		admin.POST("/users", userController.Create)
+		admin.POST("/users/:id/disable", userController.Disable)
+		admin.POST("/users/:id/enable", userController.Enable)
+		admin.PUT("/users/:id/role", userController.SetRole)
+		admin.POST("/users/:id/reset-password", userController.ResetPassword)
+
+		admin.GET("/api-keys", apiKeyController.List)
+		admin.POST("/api-keys", apiKeyController.Create)
+		admin.DELETE("/api-keys/:id", apiKeyController.Revoke)
+
+		admin.GET("/quota", quotaController.Usage)
+		admin.GET("/cache/stats", cacheController.Stats)
+		admin.GET("/cache/entries", cacheController.Entries)
+		admin.DELETE("/cache/entries", cacheController.Purge)
 	}
 
 	// Add health check routes
@@ -35,6 +35,15 @@ func NewNotFound(message string, err error) *AppError {
 	}
 }
 
+// NewConflict returns a 409 Conflict error
+func NewConflict(message string, err error) *AppError {
+	return &AppError{
+		Code:    http.StatusConflict,
+		Message: message,
+		Err:     err,
+	}
+}
+
 // NewInternalServerError returns a 500 Internal Server Error
 func NewInternalServerError(message string, err error) *AppError {
 	return &AppError{